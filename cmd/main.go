@@ -21,6 +21,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -49,6 +50,11 @@ func main() {
 		log.Println("Loaded environment variables from .env file")
 	}
 
+	// Schema migrations can be run on their own, without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	apiKey := os.Getenv("DATADOG_API_KEY")
 
 	if apiKey == "" {
@@ -72,6 +78,26 @@ func main() {
 	}
 	defer dbManager.CloseDB()
 
+	// Apply pending schema migrations unless they are run separately with the migrate subcommand
+	if os.Getenv("DB_MIGRATE_ON_BOOT") != "false" {
+		if err := dbManager.MigrateDB(); err != nil {
+			logError("Failed to migrate database", map[string]interface{}{
+				"error": err.Error(),
+			})
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+
+		// In the background, so that many files or an unreachable blob store do not hold back startup.
+		// Files not moved yet cannot be downloaded until they are.
+		go func() {
+			if err := moveFileContents(context.Background()); err != nil {
+				logError("Failed to move file contents to the blob store", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}()
+	}
+
 	mux := controller.SetupRoutes(datadogLogger, datadogMetrics)
 
 	corsHandler := cors.New(cors.Options{
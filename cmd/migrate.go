@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"templateGo/internal/repositories"
+	"templateGo/internal/storage"
+	"text/tabwriter"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up          apply every pending migration
+  down [n]    revert the last n applied migrations (1 by default)
+  status      list the migrations and whether they are applied
+  blobs       move the contents of files uploaded before the blob store to it`
+
+// runMigrate implements the migrate subcommand and returns the exit code of the process
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	steps := 1
+	switch args[0] {
+	case "up", "status", "blobs":
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+	case "down":
+		if len(args) > 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				fmt.Fprintf(os.Stderr, "invalid number of migrations to revert: %s\n", args[1])
+				return 2
+			}
+			steps = n
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	dbManager := repositories.NewDatabaseManager()
+	if err := dbManager.ConnectDB(); err != nil {
+		log.Printf("Failed to connect to database: %v", err)
+		return 1
+	}
+	defer dbManager.CloseDB()
+
+	if args[0] == "blobs" {
+		if err := moveFileContents(context.Background()); err != nil {
+			log.Printf("Failed to move file contents: %v", err)
+			return 1
+		}
+		fmt.Println("Moved the file contents to the blob store")
+		return 0
+	}
+
+	migrator, err := dbManager.NewMigrator()
+	if err != nil {
+		log.Printf("Failed to load migrations: %v", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Printf("Migration failed: %v", err)
+			return 1
+		}
+		fmt.Printf("Applied %d migration(s)\n", len(applied))
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Printf("Migration failed: %v", err)
+			return 1
+		}
+		fmt.Printf("Reverted %d migration(s)\n", len(reverted))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Printf("Failed to read migrations status: %v", err)
+			return 1
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(writer, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		writer.Flush()
+	}
+
+	return 0
+}
+
+// moveFileContents moves the contents of the files uploaded before the blob store existed, still kept in the
+// database, to the blob store
+func moveFileContents(ctx context.Context) error {
+	blobStore, err := storage.NewBlobStoreFromEnv()
+	if err != nil {
+		return fmt.Errorf("error configuring blob store: %w", err)
+	}
+	return repositories.MoveFileContentsToStore(ctx, blobStore)
+}
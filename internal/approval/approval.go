@@ -0,0 +1,215 @@
+// Package approval approves the students of a course in bulk with the completion rules of the course,
+// either on demand or in the background when the course ends.
+package approval
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"templateGo/internal/model"
+	"templateGo/internal/repositories"
+	"templateGo/internal/utils"
+	"time"
+)
+
+// notificationType is the notification sent to each approved student
+const notificationType = "course_approve"
+
+// Notifier notifies a user about a course. notification.NotificationClient satisfies it.
+type Notifier interface {
+	SendNotification(userID, courseName, notificationType string)
+}
+
+// CertificateIssuer issues the certificate of an approved student. certificates.Issuer satisfies it.
+type CertificateIssuer interface {
+	Issue(course *model.Course, userID string) (*model.Certificate, error)
+}
+
+// Engine evaluates the approval rules of courses and approves the eligible students
+type Engine struct {
+	repo         repositories.CourseRepository
+	notifier     Notifier
+	certificates CertificateIssuer
+}
+
+// NewEngine creates an engine that notifies the students it approves and issues their certificates.
+// Both notifier and certificates are optional.
+func NewEngine(repo repositories.CourseRepository, notifier Notifier, certificates CertificateIssuer) *Engine {
+	return &Engine{repo: repo, notifier: notifier, certificates: certificates}
+}
+
+// Evaluate checks every enrolled student against the rules of the course. Unless it is a dry run,
+// eligible students not approved yet are approved and notified.
+func (e *Engine) Evaluate(course *model.Course, rules *model.ApprovalRules, dryRun bool) (*model.ApprovalReport, error) {
+	progress, err := e.progress(course)
+	if err != nil {
+		return nil, err
+	}
+	approvedUsers, err := e.repo.GetApprovedUsersForCourse(course.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving approved users: %w", err)
+	}
+	approved := make(map[string]bool, len(approvedUsers))
+	for _, userID := range approvedUsers {
+		approved[userID] = true
+	}
+
+	report := &model.ApprovalReport{
+		CourseID:    course.ID,
+		DryRun:      dryRun,
+		EvaluatedAt: time.Now(),
+		Students:    make([]model.ApprovalResult, 0, len(progress)),
+	}
+	for _, student := range progress {
+		result := rules.Evaluate(student)
+		result.AlreadyApproved = approved[student.UserID]
+		if result.Eligible {
+			report.Eligible++
+		}
+		if result.Eligible && !result.AlreadyApproved && !dryRun {
+			if err := e.approve(course, student.UserID, &result); err != nil {
+				return nil, err
+			}
+			if result.Approved {
+				report.Approved++
+			}
+		}
+		report.Students = append(report.Students, result)
+	}
+	return report, nil
+}
+
+func (e *Engine) approve(course *model.Course, userID string, result *model.ApprovalResult) error {
+	if err := e.repo.ApproveCourse(userID, course.ID, course.Title); err != nil {
+		// Approved by a teacher since the approved users were read
+		if errors.Is(err, utils.ErrAlreadyApproved) {
+			result.AlreadyApproved = true
+			return nil
+		}
+		return fmt.Errorf("error approving user %s: %w", userID, err)
+	}
+	result.Approved = true
+	if e.notifier != nil {
+		e.notifier.SendNotification(userID, course.Title, notificationType)
+	}
+	// The student stays approved without it, and can get it later from the course
+	if e.certificates != nil {
+		if _, err := e.certificates.Issue(course, userID); err != nil {
+			log.Printf("Error issuing the certificate of user %s for course %d: %v", userID, course.ID, err)
+		}
+	}
+	return nil
+}
+
+// progress collects what each enrolled student did in a course: the final grade of the gradebook, the
+// mandatory assignments not submitted and the resources completed
+func (e *Engine) progress(course *model.Course) ([]model.ApprovalProgress, error) {
+	members, err := e.repo.GetCourseMembers(course.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving course members: %w", err)
+	}
+	categories, err := e.repo.GetGradeCategories(course.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving grade categories: %w", err)
+	}
+	// As the creator of the course, so the status of each assignment is not looked up
+	assignments, err := e.repo.GetAssignmentsPreviews(course.ID, "", course.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving assignments: %w", err)
+	}
+
+	submissions := make(map[string]map[uint]*model.Submission, len(members))
+	for _, assignment := range assignments {
+		assignmentSubmissions, err := e.repo.GetSubmissions(course.ID, assignment.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving submissions: %w", err)
+		}
+		for i, submission := range assignmentSubmissions {
+			if submissions[submission.UserID] == nil {
+				submissions[submission.UserID] = make(map[uint]*model.Submission)
+			}
+			submissions[submission.UserID][assignment.ID] = &assignmentSubmissions[i]
+		}
+	}
+
+	resources, completed, err := e.repo.GetResourceCompletion(course.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving resource completion: %w", err)
+	}
+
+	now := time.Now()
+	progress := make([]model.ApprovalProgress, 0, len(members))
+	for _, member := range members {
+		userID, _ := member["user_id"].(string)
+		grade := model.ComputeStudentGrade(userID, categories, assignments, submissions[userID], now)
+
+		var missing []uint
+		for _, assignment := range assignments {
+			if assignment.Mandatory && submissions[userID][assignment.ID] == nil {
+				missing = append(missing, assignment.ID)
+			}
+		}
+
+		progress = append(progress, model.ApprovalProgress{
+			UserID:             userID,
+			FinalGrade:         grade.FinalGrade,
+			MissingAssignments: missing,
+			ResourcesCompleted: completed[userID],
+			ResourcesTotal:     resources,
+		})
+	}
+	return progress, nil
+}
+
+// claimLease is how long an instance holds the rules it claimed. Rules not completed by then, such as when
+// the instance died while applying them, are claimed again.
+const claimLease = 30 * time.Minute
+
+// ApplyDueApprovals applies the rules of the courses that ended by now, once per course, and returns how
+// many courses it processed. The rules are marked as run only once applied; courses that fail are released
+// to be tried again.
+func (e *Engine) ApplyDueApprovals(now time.Time) (int, error) {
+	due, err := e.repo.ClaimDueApprovalRules(now, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var firstErr error
+	processed := 0
+	for i := range due {
+		rules := &due[i]
+		if err := e.applyRules(rules); err != nil {
+			log.Printf("Error applying the approval rules of course %d: %v", rules.CourseID, err)
+			if err := e.repo.ReleaseApprovalRules(rules.ID); err != nil {
+				log.Printf("Error releasing the approval rules of course %d: %v", rules.CourseID, err)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		// Left claimed, the rules are applied again once the lease expires, approving nobody twice
+		if err := e.repo.CompleteApprovalRules(rules.ID, time.Now()); err != nil {
+			log.Printf("Error marking the approval rules of course %d as run: %v", rules.CourseID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		processed++
+	}
+	return processed, firstErr
+}
+
+func (e *Engine) applyRules(rules *model.ApprovalRules) error {
+	course, err := e.repo.GetByID(rules.CourseID)
+	if err != nil {
+		return err
+	}
+	report, err := e.Evaluate(course, rules, false)
+	if err != nil {
+		return err
+	}
+	log.Printf("Approved %d of %d eligible students of course %d", report.Approved, report.Eligible, course.ID)
+	return nil
+}
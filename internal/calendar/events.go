@@ -0,0 +1,61 @@
+package calendar
+
+import (
+	"fmt"
+	"templateGo/internal/model"
+	"time"
+)
+
+// CourseEvents returns the events of a course: the days it starts and ends and the deadlines of its assignments
+func CourseEvents(course *model.Course, assignments []model.Assignment) []Event {
+	var events []Event
+	if !course.StartDate.IsZero() {
+		events = append(events, dayEvent(fmt.Sprintf("course-%d-start", course.ID), "Inicio: "+course.Title, course.StartDate))
+	}
+	if !course.EndDate.IsZero() {
+		events = append(events, dayEvent(fmt.Sprintf("course-%d-end", course.ID), "Fin: "+course.Title, course.EndDate))
+	}
+	for _, assignment := range assignments {
+		if assignment.Deadline.IsZero() {
+			continue
+		}
+		events = append(events, Event{
+			UID:         uid(fmt.Sprintf("assignment-%d-deadline", assignment.ID)),
+			Summary:     fmt.Sprintf("Entrega: %s (%s)", assignment.Title, course.Title),
+			Description: assignment.Description,
+			Start:       assignment.Deadline,
+		})
+	}
+	return events
+}
+
+// SessionEvents returns the windows of the timed sessions a user started, while each one lasts.
+// Sessions of assignments without a time limit or missing from assignments are skipped.
+func SessionEvents(sessions []model.AssignmentSession, assignments map[uint]model.Assignment) []Event {
+	var events []Event
+	for _, session := range sessions {
+		assignment, ok := assignments[session.AssignmentID]
+		if !ok || assignment.TimeLimit <= 0 {
+			continue
+		}
+		events = append(events, Event{
+			UID:     uid(fmt.Sprintf("assignment-%d-session-%d", assignment.ID, session.ID)),
+			Summary: "En curso: " + assignment.Title,
+			Description: fmt.Sprintf("Sesión de %d minutos iniciada el %s",
+				assignment.TimeLimit, session.StartedAt.UTC().Format("02/01/2006 15:04 UTC")),
+			Start: session.StartedAt,
+			End:   session.StartedAt.Add(time.Duration(assignment.TimeLimit) * time.Minute),
+		})
+	}
+	return events
+}
+
+// dayEvent returns an all day event on the day of date, in UTC
+func dayEvent(name, summary string, date time.Time) Event {
+	day := date.UTC().Truncate(24 * time.Hour)
+	return Event{UID: uid(name), Summary: summary, Start: day, End: day.AddDate(0, 0, 1), AllDay: true}
+}
+
+func uid(name string) string {
+	return name + "@" + uidDomain
+}
@@ -0,0 +1,52 @@
+package calendar
+
+import (
+	"templateGo/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourseEvents(t *testing.T) {
+	course := &model.Course{
+		Title:     "Algoritmos",
+		StartDate: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 7, 4, 18, 0, 0, 0, time.UTC),
+	}
+	course.ID = 4
+	deadline := time.Date(2025, 4, 1, 23, 59, 0, 0, time.UTC)
+	assignments := []model.Assignment{
+		{ID: 9, Title: "TP 1", Description: "Primer trabajo", Deadline: deadline},
+		{ID: 10, Title: "Sin fecha"},
+	}
+
+	events := CourseEvents(course, assignments)
+
+	assert.Equal(t, []Event{
+		{UID: "course-4-start@classconnect", Summary: "Inicio: Algoritmos", Start: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), AllDay: true},
+		{UID: "course-4-end@classconnect", Summary: "Fin: Algoritmos", Start: time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC), AllDay: true},
+		{UID: "assignment-9-deadline@classconnect", Summary: "Entrega: TP 1 (Algoritmos)", Description: "Primer trabajo", Start: deadline},
+	}, events)
+}
+
+func TestSessionEvents(t *testing.T) {
+	startedAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	assignments := map[uint]model.Assignment{
+		9:  {ID: 9, Title: "Parcial", TimeLimit: 90},
+		10: {ID: 10, Title: "Sin límite"},
+	}
+	sessions := []model.AssignmentSession{
+		{ID: 3, AssignmentID: 9, StartedAt: startedAt},
+		{ID: 4, AssignmentID: 10, StartedAt: startedAt},
+		{ID: 5, AssignmentID: 11, StartedAt: startedAt},
+	}
+
+	events := SessionEvents(sessions, assignments)
+
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "assignment-9-session-3@classconnect", events[0].UID)
+		assert.Equal(t, startedAt, events[0].Start)
+		assert.Equal(t, startedAt.Add(90*time.Minute), events[0].End)
+	}
+}
@@ -0,0 +1,105 @@
+// Package calendar publishes the dates of courses as iCalendar (RFC 5545) feeds that calendar apps subscribe to.
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// uidDomain makes the UIDs of the events globally unique, as RFC 5545 recommends
+const uidDomain = "classconnect"
+
+// maxLineOctets is the longest a content line may be before it is folded
+const maxLineOctets = 75
+
+// Event is an event of a feed. Its UID must not change between requests, so calendar apps update the event
+// instead of adding a new one, and drop it when it disappears from the feed.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time // no end makes the event a point in time
+	AllDay      bool      // Start and End are dates, End is the day after the last one
+}
+
+// Calendar is a feed of events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Encode writes the calendar in iCalendar format. stamp is when the feed was generated.
+func (c *Calendar) Encode(stamp time.Time) []byte {
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//ClassConnect//Courses//ES")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + escapeText(c.Name))
+	for _, event := range c.Events {
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + event.UID)
+		w.line("DTSTAMP:" + formatDateTime(stamp))
+		if event.AllDay {
+			w.line("DTSTART;VALUE=DATE:" + formatDate(event.Start))
+			if !event.End.IsZero() {
+				w.line("DTEND;VALUE=DATE:" + formatDate(event.End))
+			}
+		} else {
+			w.line("DTSTART:" + formatDateTime(event.Start))
+			if !event.End.IsZero() {
+				w.line("DTEND:" + formatDateTime(event.End))
+			}
+		}
+		w.line("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			w.line("DESCRIPTION:" + escapeText(event.Description))
+		}
+		// Free, so deadlines do not block the day in the calendar of the user
+		w.line("TRANSP:TRANSPARENT")
+		w.line("END:VEVENT")
+	}
+	w.line("END:VCALENDAR")
+	return w.Bytes()
+}
+
+type writer struct {
+	bytes.Buffer
+}
+
+// line writes a content line, folding it in lines of at most 75 octets without splitting characters
+func (w *writer) line(content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		// The space that starts each continuation line counts towards its length
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeText escapes a TEXT value
+func escapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func formatDate(t time.Time) string {
+	return t.UTC().Format("20060102")
+}
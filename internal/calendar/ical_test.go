@@ -0,0 +1,52 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendar_Encode(t *testing.T) {
+	deadline := time.Date(2025, 5, 2, 23, 59, 0, 0, time.FixedZone("ART", -3*60*60))
+	calendar := &Calendar{Name: "ClassConnect", Events: []Event{
+		{UID: "assignment-1-deadline@classconnect", Summary: "Entrega: TP 1, parte A; B", Description: "Línea 1\nLínea 2 \\ fin", Start: deadline},
+		{UID: "course-1-start@classconnect", Summary: "Inicio", Start: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), AllDay: true},
+	}}
+
+	ics := string(calendar.Encode(time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)))
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(ics, "\r\n", ""), "\n", "every line ends in CRLF")
+	assert.Contains(t, ics, "UID:assignment-1-deadline@classconnect\r\nDTSTAMP:20250401T120000Z\r\n")
+	// Times are written in UTC
+	assert.Contains(t, ics, "DTSTART:20250503T025900Z\r\n")
+	assert.Contains(t, ics, `SUMMARY:Entrega: TP 1\, parte A\; B`+"\r\n")
+	assert.Contains(t, ics, `DESCRIPTION:Línea 1\nLínea 2 \\ fin`+"\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20250310\r\nDTEND;VALUE=DATE:20250311\r\n")
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+	// The deadline is a point in time
+	assert.Equal(t, 1, strings.Count(ics, "DTEND"))
+}
+
+func TestWriter_FoldsLongLines(t *testing.T) {
+	w := &writer{}
+	content := "DESCRIPTION:" + strings.Repeat("ñandú ", 40)
+	w.line(content)
+
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\r\n"), "\r\n")
+	assert.Greater(t, len(lines), 1)
+	unfolded := lines[0]
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), maxLineOctets, "line %d", i)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, " "))
+			unfolded += line[1:]
+		}
+		// Characters are never split between lines
+		assert.True(t, strings.ToValidUTF8(line, "?") == line, "line %d", i)
+	}
+	assert.Equal(t, content, unfolded)
+}
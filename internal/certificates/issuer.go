@@ -0,0 +1,92 @@
+package certificates
+
+import (
+	"errors"
+	"fmt"
+	"templateGo/internal/model"
+	"templateGo/internal/repositories"
+	"templateGo/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserDirectory looks up the names of users. notification.NotificationClient satisfies it.
+type UserDirectory interface {
+	GetUserName(userID string) string
+}
+
+// Issuer issues the certificates of approved students and verifies them
+type Issuer struct {
+	repo   repositories.CourseRepository
+	signer *Signer
+	users  UserDirectory
+}
+
+// NewIssuer creates an issuer that signs with signer and takes the names of students from users
+func NewIssuer(repo repositories.CourseRepository, signer *Signer, users UserDirectory) *Issuer {
+	return &Issuer{repo: repo, signer: signer, users: users}
+}
+
+// PublicKey returns the key that verifies the certificates, in base64
+func (i *Issuer) PublicKey() string {
+	return i.signer.PublicKey()
+}
+
+// Issue returns the certificate of a student for a course, issuing it the first time. The caller checks
+// that the student approved the course.
+func (i *Issuer) Issue(course *model.Course, userID string) (*model.Certificate, error) {
+	certificate, err := i.repo.GetUserCertificate(course.ID, userID)
+	if err == nil {
+		return certificate, nil
+	}
+	if !errors.Is(err, utils.ErrCertificateNotFound) {
+		return nil, err
+	}
+
+	certificate = &model.Certificate{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		CourseID:    course.ID,
+		CourseTitle: course.Title,
+		StudentName: i.userName(userID),
+		Teacher:     course.CreatedBy, // the email of the teacher, the users service finds users by ID
+		// Stored with second precision, so the payload signed is the one read back
+		IssuedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	i.signer.Sign(certificate)
+	if err := i.repo.CreateCertificate(certificate); err != nil {
+		return nil, fmt.Errorf("error storing certificate: %w", err)
+	}
+
+	// Another request may have issued it first, in which case that one is kept
+	return i.repo.GetUserCertificate(course.ID, userID)
+}
+
+// Verify checks that a certificate exists and signature is its signature. Unknown certificates are not
+// valid rather than an error, as anyone can ask about any ID, and only genuine ones are returned.
+func (i *Issuer) Verify(certificateID, signature string) (*model.CertificateVerification, error) {
+	certificate, err := i.repo.GetCertificate(certificateID)
+	if errors.Is(err, utils.ErrCertificateNotFound) {
+		return &model.CertificateVerification{Valid: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature must be the one issued and still match the record, so a tampered record is not genuine
+	if certificate.Signature != signature || !i.signer.Verify(certificate, signature) {
+		return &model.CertificateVerification{Valid: false}, nil
+	}
+	return &model.CertificateVerification{Valid: true, Certificate: certificate}, nil
+}
+
+// userName returns the name of a user, or the ID if the users service does not know it
+func (i *Issuer) userName(userID string) string {
+	if i.users != nil {
+		if name := i.users.GetUserName(userID); name != "" {
+			return name
+		}
+	}
+	return userID
+}
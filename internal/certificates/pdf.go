@@ -0,0 +1,151 @@
+package certificates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"templateGo/internal/model"
+)
+
+// Size of the page, A4 in landscape, and the margin kept around the text, in points
+const (
+	pageWidth  = 842
+	pageHeight = 595
+	textMargin = 60
+)
+
+// Fonts of the certificate, two of the standard fonts every PDF reader has
+const (
+	boldFont    = "F1"
+	regularFont = "F2"
+)
+
+// Widths of the printable ASCII characters of Helvetica and Helvetica-Bold, from their font metrics, in
+// thousandths of the font size. Other characters are measured as an average letter.
+var (
+	regularWidths = []int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	boldWidths = []int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+const averageWidth = 556
+
+// line is a line of text centered in the page
+type line struct {
+	text string
+	font string
+	size float64
+	y    float64
+}
+
+// RenderPDF renders a certificate as a one page PDF. verifyURL is printed so readers can check it is genuine.
+func RenderPDF(certificate *model.Certificate, verifyURL string) []byte {
+	lines := []line{
+		{"ClassConnect", regularFont, 16, 500},
+		{"Certificado de aprobación", boldFont, 36, 440},
+		{"Se certifica que", regularFont, 16, 385},
+		{certificate.StudentName, boldFont, 30, 340},
+		{"aprobó el curso", regularFont, 16, 295},
+		{certificate.CourseTitle, boldFont, 24, 255},
+		{"Docente: " + certificate.Teacher, regularFont, 14, 195},
+		{"Fecha: " + certificate.IssuedAt.UTC().Format("02/01/2006"), regularFont, 14, 172},
+		{"Certificado " + certificate.ID, regularFont, 9, 95},
+		{"Firma Ed25519: " + certificate.Signature, regularFont, 7, 80},
+		{"Verificar en " + verifyURL, regularFont, 9, 65},
+	}
+
+	var content bytes.Buffer
+	// Double border around the page
+	content.WriteString("2 w 30 30 782 535 re S\n0.5 w 38 38 766 519 re S\n")
+	for _, l := range lines {
+		text := encodeWinAnsi(l.text)
+		size := l.size
+		// Long texts, such as titles, shrink to fit in the page
+		if width := textWidth(text, l.font, size); width > pageWidth-2*textMargin {
+			size = size * (pageWidth - 2*textMargin) / width
+		}
+		x := (pageWidth - textWidth(text, l.font, size)) / 2
+		fmt.Fprintf(&content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", l.font, size, x, l.y, escapePDFString(text))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /%s 4 0 R /%s 5 0 R >> >> /Contents 6 0 R >>",
+			pageWidth, pageHeight, boldFont, regularFont),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		fmt.Sprintf("<< /Title (%s) /Producer (ClassConnect) >>", escapePDFString(encodeWinAnsi("Certificado - "+certificate.CourseTitle))),
+	}
+	return writePDF(objects)
+}
+
+// writePDF writes the objects, numbered from 1, with the cross-reference table readers use to find them.
+// The first object is the catalog and the last one the document information.
+func writePDF(objects []string) []byte {
+	var out bytes.Buffer
+	// The binary comment tells transfer programs the file is not text
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xref)
+	return out.Bytes()
+}
+
+// encodeWinAnsi converts text to the encoding of the fonts. Latin-1 characters, such as accented letters,
+// keep their code, and any other character becomes a question mark.
+func encodeWinAnsi(text string) string {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x20 || (r >= 0x7f && r < 0xa0) || r > 0xff {
+			r = '?'
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return string(encoded)
+}
+
+func escapePDFString(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(text)
+}
+
+// textWidth measures text already encoded in WinAnsi, in points
+func textWidth(text, font string, size float64) float64 {
+	widths := regularWidths
+	if font == boldFont {
+		widths = boldWidths
+	}
+	total := 0
+	for i := 0; i < len(text); i++ {
+		if c := int(text[i]) - ' '; c >= 0 && c < len(widths) {
+			total += widths[c]
+		} else {
+			total += averageWidth
+		}
+	}
+	return float64(total) * size / 1000
+}
@@ -0,0 +1,53 @@
+package certificates
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPDF(t *testing.T) {
+	certificate := testCertificate()
+	certificate.Signature = "c2lnbmF0dXJl"
+	pdf := RenderPDF(certificate, "https://classconnect.example/certificates/x/verify?signature=c2lnbmF0dXJl")
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	// Accented letters are in WinAnsi and parentheses are escaped
+	assert.Contains(t, string(pdf), "(Ana P\xe9rez)")
+	assert.Contains(t, string(pdf), "(Programaci\xf3n \\(avanzada\\))")
+	assert.Contains(t, string(pdf), "(Fecha: 30/06/2025)")
+
+	// Every entry of the cross-reference table points at its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	require.Len(t, entries, 7)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+}
+
+func TestRenderPDF_ShrinksLongText(t *testing.T) {
+	certificate := testCertificate()
+	certificate.CourseTitle = string(bytes.Repeat([]byte("Curso muy largo "), 20))
+	pdf := string(RenderPDF(certificate, "https://classconnect.example"))
+
+	size := regexp.MustCompile(`/F1 ([\d.]+) Tf ([\d.]+) 255\.00 Td`).FindStringSubmatch(pdf)
+	require.NotNil(t, size)
+	fontSize, _ := strconv.ParseFloat(size[1], 64)
+	x, _ := strconv.ParseFloat(size[2], 64)
+	assert.Less(t, fontSize, 24.0)
+	assert.InDelta(t, textMargin, x, 0.5)
+}
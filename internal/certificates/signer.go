@@ -0,0 +1,80 @@
+// Package certificates issues the completion certificates of approved students, signs them with Ed25519
+// so anyone can check they are genuine, and renders them as PDF.
+package certificates
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"templateGo/internal/model"
+)
+
+// Signer signs certificates with an Ed25519 key and verifies their signatures
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner creates a signer with a private key
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{key: key}
+}
+
+// NewSignerFromEnv creates a signer with the key in CERTIFICATE_SIGNING_KEY, in base64, either the 32 byte
+// seed or the 64 byte private key. Without it, a key is generated that only lasts until the service restarts,
+// which is enough for development but leaves the certificates issued unverifiable afterwards.
+func NewSignerFromEnv() (*Signer, error) {
+	encoded := os.Getenv("CERTIFICATE_SIGNING_KEY")
+	if encoded == "" {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		log.Println("CERTIFICATE_SIGNING_KEY is not set, certificates are signed with a temporary key")
+		return NewSigner(key), nil
+	}
+
+	key, err := ParsePrivateKey(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CERTIFICATE_SIGNING_KEY: %w", err)
+	}
+	return NewSigner(key), nil
+}
+
+// ParsePrivateKey decodes an Ed25519 private key, or its seed, from base64
+func ParsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, errors.New("the key must be a 32 byte seed or a 64 byte private key")
+	}
+}
+
+// PublicKey returns the key that verifies the signatures, in base64
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// Sign sets the signature of a certificate
+func (s *Signer) Sign(certificate *model.Certificate) {
+	certificate.Signature = base64.RawURLEncoding.EncodeToString(ed25519.Sign(s.key, certificate.Payload()))
+}
+
+// Verify reports whether signature is a signature of the certificate made with the key of the signer
+func (s *Signer) Verify(certificate *model.Certificate, signature string) bool {
+	raw, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(s.key.Public().(ed25519.PublicKey), certificate.Payload(), raw)
+}
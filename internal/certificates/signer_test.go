@@ -0,0 +1,67 @@
+package certificates
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"templateGo/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCertificate() *model.Certificate {
+	return &model.Certificate{
+		ID:          "5b0c6f1e-2d7a-4c43-9a8e-0f3c9a1d2e4b",
+		UserID:      "user-1",
+		CourseID:    7,
+		CourseTitle: "Programación (avanzada)",
+		StudentName: "Ana Pérez",
+		Teacher:     "Juan Gómez",
+		IssuedAt:    time.Date(2025, 6, 30, 14, 5, 0, 0, time.UTC),
+	}
+}
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	signer := NewSigner(key)
+
+	certificate := testCertificate()
+	signer.Sign(certificate)
+	assert.True(t, signer.Verify(certificate, certificate.Signature))
+
+	// Any change to what is certified invalidates the signature
+	tampered := *certificate
+	tampered.StudentName = "Otra Persona"
+	assert.False(t, signer.Verify(&tampered, certificate.Signature))
+
+	// The date is signed in UTC, as read back from the database
+	moved := *certificate
+	moved.IssuedAt = certificate.IssuedAt.In(time.FixedZone("ART", -3*60*60))
+	assert.True(t, signer.Verify(&moved, certificate.Signature))
+
+	assert.False(t, signer.Verify(certificate, "not base64!"))
+
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	assert.False(t, NewSigner(otherKey).Verify(certificate, certificate.Signature))
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	seed := bytes.Repeat([]byte{7}, ed25519.SeedSize)
+	fromSeed, err := ParsePrivateKey(base64.StdEncoding.EncodeToString(seed))
+	require.NoError(t, err)
+	assert.Equal(t, ed25519.NewKeyFromSeed(seed), fromSeed)
+
+	fromKey, err := ParsePrivateKey(base64.StdEncoding.EncodeToString(fromSeed))
+	require.NoError(t, err)
+	assert.Equal(t, fromSeed, fromKey)
+
+	_, err = ParsePrivateKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+	_, err = ParsePrivateKey("not base64!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,26 @@
+package ai
+
+import (
+	"context"
+	"templateGo/internal/model"
+)
+
+// FeedbackAnalyzer represents a service that can analyze course feedback
+type FeedbackAnalyzer interface {
+	// GenerateCourseFeedbackAnalysis analyzes a collection of course feedback and returns insights
+	GenerateCourseFeedbackAnalysis(ctx context.Context, courseTitle string, feedbacks []model.CourseFeedback) (string, error)
+
+	// GenerateGradeAndFeedback generates a grade and feedback for a submission
+	GenerateGradeAndFeedback(ctx context.Context, assignmentDescription string, submissionContent string, submissionFiles []model.SubmissionFile) (int, string, error)
+
+	// GenerateGradeDraft grades a submission, criterion by criterion when a rubric is given.
+	// The draft is validated against the rubric but not stored.
+	GenerateGradeDraft(ctx context.Context, assignmentDescription string, rubric *model.Rubric, submissionContent string, submissionFiles []model.SubmissionFile) (*model.GradeDraft, error)
+
+	// GenerateUserFeedbackAnalysis generates AI analysis for user feedback
+	GenerateUserFeedbackAnalysis(ctx context.Context, feedbacks []model.UserFeedback) (string, error)
+
+	// GenerateCourseSuggestionsBasedOnStats generates course suggestions based on statistics. gradedCount is the
+	// number of graded submissions behind the average grade, which may be 0 when every student scored 0.
+	GenerateCourseSuggestionsBasedOnStats(ctx context.Context, lastGradeTendency string, lastSubmissionRateTendency string, averageGrade float64, gradedCount int) (string, error)
+}
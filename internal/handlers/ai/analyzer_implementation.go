@@ -0,0 +1,320 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"templateGo/internal/model"
+)
+
+// LLMAnalyzer implements FeedbackAnalyzer on top of any language model Provider
+type LLMAnalyzer struct {
+	provider Provider
+}
+
+// NewLLMAnalyzer creates a new instance of LLMAnalyzer
+func NewLLMAnalyzer(provider Provider) *LLMAnalyzer {
+	return &LLMAnalyzer{
+		provider: provider,
+	}
+}
+
+// gradeResponseSchema is the structured answer expected when grading a submission
+var gradeResponseSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"grade": {
+			Type:        "integer",
+			Description: "Grade of the submission",
+			Minimum:     floatPtr(0),
+			Maximum:     floatPtr(100),
+		},
+		"feedback": {
+			Type:        "string",
+			Description: "Short paragraph explaining the grade and suggestions for improvement",
+		},
+	},
+	Required: []string{"grade", "feedback"},
+}
+
+// GenerateGradeAndFeedback generates a grade and feedback for a submission
+func (a *LLMAnalyzer) GenerateGradeAndFeedback(ctx context.Context, submissionDescription string, submissionContent string, submissionFiles []model.SubmissionFile) (int, string, error) {
+	// Format the submission content and files for the language model
+	submissionText := "You are analyzing a student's submission for the following assignment:\n\n" + submissionDescription + "\n\nYour task is to provide a grade and feedback based on the text and the files of the given submission. The grade should be a number between 0 and 100, and the feedback should be a short paragraph explaining the grade and any suggestions for improvement."
+
+	parts, err := submissionParts(submissionContent, submissionFiles)
+	if err != nil {
+		return 0, "", err
+	}
+
+	parts = append(parts, TextPart(submissionText))
+
+	text, err := a.provider.Generate(ctx, GenerateRequest{
+		Parts:          parts,
+		ResponseSchema: gradeResponseSchema,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	// Extract the grade and feedback from the result
+	var result struct {
+		Grade    int    `json:"grade"`
+		Feedback string `json:"feedback"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return 0, "", fmt.Errorf("unexpected response format from %s: %s", a.provider.Name(), text)
+	}
+	if result.Grade < 0 || result.Grade > 100 {
+		return 0, "", fmt.Errorf("grade out of range: %d", result.Grade)
+	}
+
+	return result.Grade, strings.TrimSpace(result.Feedback), nil
+}
+
+// GenerateGradeDraft grades a submission, criterion by criterion when a rubric is given
+func (a *LLMAnalyzer) GenerateGradeDraft(ctx context.Context, assignmentDescription string, rubric *model.Rubric, submissionContent string, submissionFiles []model.SubmissionFile) (*model.GradeDraft, error) {
+	if rubric == nil {
+		grade, feedback, err := a.GenerateGradeAndFeedback(ctx, assignmentDescription, submissionContent, submissionFiles)
+		if err != nil {
+			return nil, err
+		}
+		return &model.GradeDraft{
+			Grade:    uint(grade),
+			Feedback: feedback,
+			Provider: a.provider.Name(),
+			Status:   model.GradeDraftStatusDraft,
+		}, nil
+	}
+
+	parts, err := submissionParts(submissionContent, submissionFiles)
+	if err != nil {
+		return nil, err
+	}
+	parts = append(parts, TextPart(formatRubricGradingPrompt(assignmentDescription, rubric)))
+
+	text, err := a.provider.Generate(ctx, GenerateRequest{
+		Parts:          parts,
+		ResponseSchema: rubricResponseSchema(rubric),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Criteria map[string]struct {
+			Level         string  `json:"level"`
+			Score         float64 `json:"score"`
+			Justification string  `json:"justification"`
+		} `json:"criteria"`
+		Total    float64 `json:"total"`
+		Feedback string  `json:"feedback"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return nil, fmt.Errorf("unexpected response format from %s: %s", a.provider.Name(), text)
+	}
+
+	scores := make([]model.CriterionScore, 0, len(result.Criteria))
+	for _, criterion := range rubric.Criteria {
+		score, ok := result.Criteria[criterion.Name]
+		if !ok {
+			continue
+		}
+		scores = append(scores, model.CriterionScore{
+			Criterion:     criterion.Name,
+			Level:         score.Level,
+			Score:         score.Score,
+			Justification: strings.TrimSpace(score.Justification),
+		})
+	}
+	if len(scores) != len(result.Criteria) {
+		return nil, fmt.Errorf("response from %s scores criteria that are not part of the rubric", a.provider.Name())
+	}
+	if err := rubric.ValidateScores(scores); err != nil {
+		return nil, fmt.Errorf("invalid rubric grade from %s: %w", a.provider.Name(), err)
+	}
+
+	// The total is always recomputed from the scores, the one reported by the model is only checked
+	total := rubric.Total(scores)
+	if math.Abs(result.Total-float64(total)) > 1 {
+		log.Printf("%s reported a total of %.2f but the rubric scores add up to %d", a.provider.Name(), result.Total, total)
+	}
+
+	return &model.GradeDraft{
+		RubricID: &rubric.ID,
+		Scores:   scores,
+		Grade:    total,
+		Feedback: strings.TrimSpace(result.Feedback),
+		Provider: a.provider.Name(),
+		Status:   model.GradeDraftStatusDraft,
+	}, nil
+}
+
+// GenerateCourseFeedbackAnalysis analyzes course feedback
+func (a *LLMAnalyzer) GenerateCourseFeedbackAnalysis(ctx context.Context, courseTitle string, feedbacks []model.CourseFeedback) (string, error) {
+	feedbackText := formatCourseFeedbackForAnalysis(courseTitle, feedbacks)
+
+	return a.generateText(ctx, feedbackText)
+}
+
+// GenerateUserFeedbackAnalysis analyzes user feedback
+func (a *LLMAnalyzer) GenerateUserFeedbackAnalysis(ctx context.Context, feedbacks []model.UserFeedback) (string, error) {
+	feedbackText := formatUserFeedbackForAnalysis(feedbacks)
+
+	return a.generateText(ctx, feedbackText)
+}
+
+// GenerateCourseSuggestionsBasedOnStats generates suggestions for a course from its statistics tendencies
+func (a *LLMAnalyzer) GenerateCourseSuggestionsBasedOnStats(ctx context.Context, lastGradeTendency string, lastSubmissionRateTendency string, averageGrade float64, gradedCount int) (string, error) {
+	if gradedCount == 0 {
+		return "No stats to analyze", fmt.Errorf("there are no graded submissions to analyze")
+	}
+	inputText := fmt.Sprintf(
+		"You are analyzing a course's statistics. The last grade tendency is '%s', the last submission rate tendency is '%s' and the last average grade is '%s'. Your task is to provide suggestions for improving the course based on these tendencies. Output strictly plain text. Do not use lists, bullet points, bold text, markdown, or any kind of formatting.",
+		lastGradeTendency,
+		lastSubmissionRateTendency,
+		strconv.FormatFloat(averageGrade, 'f', 2, 64),
+	)
+
+	suggestions, err := a.generateText(ctx, inputText)
+	if err != nil {
+		log.Printf("Error generating course suggestions: %v", err)
+		return "Error generating suggestions", fmt.Errorf("error generating course suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// generateText sends a plain text prompt to the provider
+func (a *LLMAnalyzer) generateText(ctx context.Context, prompt string) (string, error) {
+	return a.provider.Generate(ctx, GenerateRequest{
+		Parts: []Part{TextPart(prompt)},
+	})
+}
+
+// formatRubricGradingPrompt describes the assignment and its rubric for the language model
+func formatRubricGradingPrompt(assignmentDescription string, rubric *model.Rubric) string {
+	var builder strings.Builder
+	builder.WriteString("You are analyzing a student's submission for the following assignment:\n\n")
+	builder.WriteString(assignmentDescription)
+	builder.WriteString("\n\nGrade the given submission files with the following rubric. For every criterion choose the level that best describes the submission, give a score between 0 and the maximum points of the criterion, and justify it in one or two sentences. Then give the weighted total from 0 to 100 and a short paragraph of feedback with suggestions for improvement.\n\n")
+
+	for _, criterion := range rubric.Criteria {
+		builder.WriteString(fmt.Sprintf("Criterion: %s (weight %g, maximum %g points)\n", criterion.Name, criterion.Weight, criterion.MaxPoints()))
+		if criterion.Description != "" {
+			builder.WriteString(fmt.Sprintf("Description: %s\n", criterion.Description))
+		}
+		for _, level := range criterion.Levels {
+			builder.WriteString(fmt.Sprintf("- %s (%g points): %s\n", level.Title, level.Points, level.Description))
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// rubricResponseSchema is the structured answer expected when grading with a rubric:
+// one entry per criterion, constrained to its levels and points
+func rubricResponseSchema(rubric *model.Rubric) *Schema {
+	criteria := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema, len(rubric.Criteria)),
+	}
+	for _, criterion := range rubric.Criteria {
+		levels := make([]string, 0, len(criterion.Levels))
+		for _, level := range criterion.Levels {
+			levels = append(levels, level.Title)
+		}
+
+		criteria.Properties[criterion.Name] = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"level":         {Type: "string", Enum: levels},
+				"score":         {Type: "number", Minimum: floatPtr(0), Maximum: floatPtr(criterion.MaxPoints())},
+				"justification": {Type: "string", Description: "Justification of the score"},
+			},
+			Required: []string{"level", "score", "justification"},
+		}
+		criteria.Required = append(criteria.Required, criterion.Name)
+	}
+
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"criteria": criteria,
+			"total":    {Type: "number", Minimum: floatPtr(0), Maximum: floatPtr(100)},
+			"feedback": {Type: "string", Description: "Short paragraph explaining the grade and suggestions for improvement"},
+		},
+		Required: []string{"criteria", "total", "feedback"},
+	}
+}
+
+// formatCourseFeedbackForAnalysis formats the feedback data into text format for the language model
+func formatCourseFeedbackForAnalysis(courseTitle string, feedbacks []model.CourseFeedback) string {
+	// Calculate the average rating
+	var totalRating int
+
+	for _, feedback := range feedbacks {
+		totalRating += feedback.Rating
+	}
+
+	averageRating := fmt.Sprintf("%.2f", float64(totalRating)/float64(len(feedbacks)))
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(
+		"You are analyzing course feedbacks for '%s'. Your task is to provide a short and clear summary of the most common themes mentioned by students. First tell the average rating which is '%s' (you don't have to recalculate it), then identify key strengths and areas where the course can improve considering that the ratings go from 1 to 5. Output strictly plain text. Do not use lists, bullet points, bold text, markdown, or any kind of formatting.",
+		courseTitle,
+		averageRating,
+	))
+
+	for i, feedback := range feedbacks {
+		builder.WriteString(fmt.Sprintf("Feedback %d:\n", i+1))
+		builder.WriteString(fmt.Sprintf("Rating: %d/100\n", feedback.Rating))
+		if feedback.Summary != "" {
+			builder.WriteString(fmt.Sprintf("Summary: %s\n", feedback.Summary))
+		}
+		if feedback.Comment != "" {
+			builder.WriteString(fmt.Sprintf("Comment: %s\n", feedback.Comment))
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// formatUserFeedbackForAnalysis formats the feedback data into text format for the language model
+func formatUserFeedbackForAnalysis(feedbacks []model.UserFeedback) string {
+	// Calculate the average rating
+	var totalRating uint
+
+	for _, feedback := range feedbacks {
+		totalRating += feedback.Rating
+	}
+
+	averageRating := fmt.Sprintf("%.2f", float64(totalRating)/float64(len(feedbacks)))
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(
+		"You are analyzing feedbacks given to a student. Your task is to provide a short and clear summary of the comments and ratings from the teachers. First tell the average rating which is '%s' (you don't have to recalculate it), then identify key strengths and areas where the student can improve considering that the ratings go from 1 to 5. Output strictly plain text. Do not use lists, bullet points, bold text, markdown, or any kind of formatting.",
+		averageRating,
+	))
+
+	for i, feedback := range feedbacks {
+		builder.WriteString(fmt.Sprintf("Feedback %d:\n", i+1))
+		builder.WriteString(fmt.Sprintf("Rating: %d/100\n", feedback.Rating))
+		if feedback.Comment != "" {
+			builder.WriteString(fmt.Sprintf("Comment: %s\n", feedback.Comment))
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOpenAIModel   = "gpt-4o-mini"
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+)
+
+// OpenAIProvider implements Provider for any endpoint compatible with the OpenAI chat completions API
+type OpenAIProvider struct {
+	baseURL string
+	apiKey  string
+	config  ProviderConfig
+	client  *http.Client
+}
+
+// NewOpenAIProvider creates a new instance of OpenAIProvider.
+// The API key is optional since local OpenAI-compatible servers usually do not require it.
+func NewOpenAIProvider(baseURL string, apiKey string, config ProviderConfig) (*OpenAIProvider, error) {
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+
+	return &OpenAIProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		config:  config,
+		client:  &http.Client{},
+	}, nil
+}
+
+// Name identifies the provider
+func (o *OpenAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+// Generate generates content with the chat completions endpoint
+func (o *OpenAIProvider) Generate(ctx context.Context, request GenerateRequest) (string, error) {
+	ctx, cancel := withTimeout(ctx, o.config.Timeout)
+	defer cancel()
+
+	content := make([]map[string]any, 0, len(request.Parts))
+	for _, part := range request.Parts {
+		if part.Data == nil {
+			content = append(content, map[string]any{"type": "text", "text": part.Text})
+			continue
+		}
+
+		dataURL := fmt.Sprintf("data:%s;base64,%s", part.MIMEType, base64.StdEncoding.EncodeToString(part.Data))
+		if strings.HasPrefix(part.MIMEType, "image/") {
+			content = append(content, map[string]any{
+				"type":      "image_url",
+				"image_url": map[string]any{"url": dataURL},
+			})
+		} else {
+			content = append(content, map[string]any{
+				"type": "file",
+				"file": map[string]any{"filename": "submission", "file_data": dataURL},
+			})
+		}
+	}
+
+	requestBody := map[string]any{
+		"model": o.config.Model,
+		"messages": []map[string]any{
+			{"role": "user", "content": content},
+		},
+	}
+	if request.ResponseSchema != nil {
+		requestBody["response_format"] = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   "response",
+				"schema": request.ResponseSchema,
+			},
+		}
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error calling OpenAI-compatible API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OpenAI-compatible API returned non-OK status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var responseData struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		return "", fmt.Errorf("error unmarshaling response body: %w", err)
+	}
+
+	if len(responseData.Choices) == 0 {
+		return "", fmt.Errorf("unexpected response format from OpenAI-compatible API")
+	}
+
+	return responseData.Choices[0].Message.Content, nil
+}
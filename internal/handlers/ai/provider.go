@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Part is a piece of the content sent to a language model: either text or inline binary data
+type Part struct {
+	Text     string
+	MIMEType string
+	Data     []byte
+}
+
+// TextPart creates a text part
+func TextPart(text string) Part {
+	return Part{Text: text}
+}
+
+// Schema is the subset of JSON Schema used to request structured responses
+type Schema struct {
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+}
+
+// GenerateRequest is a single prompt sent to a provider.
+// When ResponseSchema is set the provider must answer with JSON matching it.
+type GenerateRequest struct {
+	Parts          []Part
+	ResponseSchema *Schema
+}
+
+// Provider is a large language model backend
+type Provider interface {
+	// Name identifies the provider in logs and errors
+	Name() string
+
+	// Generate returns the text generated for the request
+	Generate(ctx context.Context, request GenerateRequest) (string, error)
+}
+
+// ProviderConfig holds the settings shared by every provider
+type ProviderConfig struct {
+	Model   string
+	Timeout time.Duration
+}
+
+// Supported values of AI_PROVIDER
+const (
+	ProviderGemini = "gemini"
+	ProviderOpenAI = "openai"
+	ProviderStub   = "stub"
+)
+
+const defaultProviderTimeout = 60 * time.Second
+
+// NewProviderFromEnv creates the provider selected by AI_PROVIDER.
+// Each provider reads its model from <PROVIDER>_MODEL and its timeout from <PROVIDER>_TIMEOUT,
+// falling back to AI_TIMEOUT. Without AI_PROVIDER, Gemini is used when GEMINI_API_KEY is set
+// and the offline stub otherwise.
+func NewProviderFromEnv() (Provider, error) {
+	name := strings.ToLower(os.Getenv("AI_PROVIDER"))
+	if name == "" {
+		name = ProviderStub
+		if os.Getenv("GEMINI_API_KEY") != "" {
+			name = ProviderGemini
+		}
+	}
+
+	switch name {
+	case ProviderGemini:
+		return NewGeminiProvider(os.Getenv("GEMINI_API_KEY"), providerConfigFromEnv("GEMINI", defaultGeminiModel))
+	case ProviderOpenAI:
+		return NewOpenAIProvider(os.Getenv("OPENAI_BASE_URL"), os.Getenv("OPENAI_API_KEY"), providerConfigFromEnv("OPENAI", defaultOpenAIModel))
+	case ProviderStub:
+		return NewStubProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider: %s", name)
+	}
+}
+
+// providerConfigFromEnv reads the model and timeout of a provider
+func providerConfigFromEnv(prefix string, defaultModel string) ProviderConfig {
+	config := ProviderConfig{
+		Model:   os.Getenv(prefix + "_MODEL"),
+		Timeout: defaultProviderTimeout,
+	}
+	if config.Model == "" {
+		config.Model = defaultModel
+	}
+
+	for _, key := range []string{"AI_TIMEOUT", prefix + "_TIMEOUT"} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			log.Printf("Invalid %s %q, ignoring it: %v", key, value, err)
+			continue
+		}
+		config.Timeout = timeout
+	}
+
+	return config
+}
+
+// withTimeout bounds a provider call by the configured timeout
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
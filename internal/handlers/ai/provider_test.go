@@ -0,0 +1,193 @@
+package ai
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStubProvider_GradeIsDeterministicAndInRange(t *testing.T) {
+	analyzer := NewLLMAnalyzer(NewStubProvider())
+	files := []model.SubmissionFile{{Name: "answer.pdf", FileContent: model.FileContent{Content: []byte("%PDF-1.4 answer")}}}
+
+	grade, feedback, err := analyzer.GenerateGradeAndFeedback(context.Background(), "Solve the exercise", "", files)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, grade, 0)
+	assert.LessOrEqual(t, grade, 100)
+	assert.NotEmpty(t, feedback)
+
+	again, _, err := analyzer.GenerateGradeAndFeedback(context.Background(), "Solve the exercise", "", files)
+	assert.NoError(t, err)
+	assert.Equal(t, grade, again)
+}
+
+func TestStubProvider_RespectsSchemaConstraints(t *testing.T) {
+	minItems := 3
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"levels": {Type: "array", MinItems: &minItems, Items: &Schema{Type: "string", Enum: []string{"low", "high"}}},
+		},
+	}
+
+	text, err := NewStubProvider().Generate(context.Background(), GenerateRequest{
+		Parts:          []Part{TextPart("rate this")},
+		ResponseSchema: schema,
+	})
+	assert.NoError(t, err)
+
+	var result struct {
+		Levels []string `json:"levels"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(text), &result))
+	assert.Len(t, result.Levels, 3)
+	for _, level := range result.Levels {
+		assert.Contains(t, []string{"low", "high"}, level)
+	}
+}
+
+func TestOpenAIProvider_SendsSchemaAndParsesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "test-model", body["model"])
+		assert.Contains(t, body, "response_format")
+
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"grade\":77,\"feedback\":\"Good\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewOpenAIProvider(server.URL+"/v1", "secret", ProviderConfig{Model: "test-model", Timeout: time.Second})
+	assert.NoError(t, err)
+
+	grade, feedback, err := NewLLMAnalyzer(provider).GenerateGradeAndFeedback(context.Background(), "Essay", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 77, grade)
+	assert.Equal(t, "Good", feedback)
+}
+
+func TestOpenAIProvider_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	provider, _ := NewOpenAIProvider(server.URL, "", ProviderConfig{Model: "test-model", Timeout: 20 * time.Millisecond})
+
+	_, err := provider.Generate(context.Background(), GenerateRequest{Parts: []Part{TextPart("hello")}})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	t.Setenv("AI_PROVIDER", "")
+	t.Setenv("GEMINI_API_KEY", "")
+	provider, err := NewProviderFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, ProviderStub, provider.Name())
+
+	t.Setenv("AI_PROVIDER", "openai")
+	t.Setenv("OPENAI_MODEL", "local-model")
+	t.Setenv("AI_TIMEOUT", "5s")
+	provider, err = NewProviderFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "local-model", provider.(*OpenAIProvider).config.Model)
+	assert.Equal(t, 5*time.Second, provider.(*OpenAIProvider).config.Timeout)
+
+	t.Setenv("AI_PROVIDER", "unknown")
+	_, err = NewProviderFromEnv()
+	assert.Error(t, err)
+}
+
+func TestStubProvider_RubricGradeDraftIsValid(t *testing.T) {
+	rubric := &model.Rubric{
+		ID: 7,
+		Criteria: []model.RubricCriterion{
+			{Name: "Correctness", Weight: 2, Levels: []model.RubricLevel{{Title: "Poor", Points: 0}, {Title: "Good", Points: 4}}},
+			{Name: "Style", Weight: 1, Levels: []model.RubricLevel{{Title: "Poor", Points: 0}, {Title: "Good", Points: 2}}},
+		},
+	}
+	assert.NoError(t, rubric.Validate())
+
+	analyzer := NewLLMAnalyzer(NewStubProvider())
+	files := []model.SubmissionFile{{Name: "answer.pdf", FileContent: model.FileContent{Content: []byte("%PDF-1.4 answer")}}}
+
+	draft, err := analyzer.GenerateGradeDraft(context.Background(), "Solve the exercise", rubric, "", files)
+	assert.NoError(t, err)
+	assert.Equal(t, model.GradeDraftStatusDraft, draft.Status)
+	assert.Equal(t, rubric.ID, *draft.RubricID)
+	assert.Len(t, draft.Scores, 2)
+	assert.NoError(t, rubric.ValidateScores(draft.Scores))
+	assert.Equal(t, rubric.Total(draft.Scores), draft.Grade)
+}
+
+func TestSubmissionParts_DetectsFormatsAndExpandsArchives(t *testing.T) {
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	entry, _ := writer.Create("src/main.go")
+	entry.Write([]byte("package main\n\nfunc main() {}\n"))
+	entry, _ = writer.Create("diagram.png")
+	entry.Write([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+	assert.NoError(t, writer.Close())
+
+	parts, err := submissionParts("My answer", []model.SubmissionFile{
+		{Name: "README.md", FileContent: model.FileContent{Content: []byte("# Solution\n")}},
+		{Name: "project.zip", FileContent: model.FileContent{Content: archive.Bytes()}},
+		{Name: "data.bin", FileContent: model.FileContent{Content: []byte{0x00, 0x01, 0x02}}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, parts, 5)
+	assert.Contains(t, parts[0].Text, "My answer")
+	assert.Contains(t, parts[1].Text, "# Solution")
+	assert.Contains(t, parts[2].Text, "project.zip/src/main.go")
+	assert.Equal(t, "image/png", parts[3].MIMEType)
+	assert.Contains(t, parts[4].Text, "not analyzed")
+}
+
+func TestSubmissionParts_RejectsArchivesOverTheLimit(t *testing.T) {
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	entry, _ := writer.Create("huge.txt")
+	entry.Write(bytes.Repeat([]byte("a"), maxArchiveSize+1))
+	assert.NoError(t, writer.Close())
+
+	_, err := submissionParts("", []model.SubmissionFile{{Name: "huge.zip", FileContent: model.FileContent{Content: archive.Bytes()}}})
+	assert.ErrorIs(t, err, utils.ErrSubmissionTooLarge)
+}
+
+func TestSubmissionParts_SharesTheBudgetWithNestedArchives(t *testing.T) {
+	// Each nested archive is under the limit of a single archive, together they are over the submission limit
+	var nested bytes.Buffer
+	writer := zip.NewWriter(&nested)
+	entry, _ := writer.Create("big.txt")
+	entry.Write(bytes.Repeat([]byte("a"), maxSubmissionInline/3+1))
+	assert.NoError(t, writer.Close())
+
+	var archive bytes.Buffer
+	writer = zip.NewWriter(&archive)
+	for i := 0; i < 3; i++ {
+		entry, _ := writer.Create(fmt.Sprintf("part%d.zip", i))
+		entry.Write(nested.Bytes())
+	}
+	assert.NoError(t, writer.Close())
+
+	_, err := submissionParts("", []model.SubmissionFile{{Name: "bomb.zip", FileContent: model.FileContent{Content: archive.Bytes()}}})
+	assert.ErrorIs(t, err, utils.ErrSubmissionTooLarge)
+	assert.Contains(t, err.Error(), "add up to")
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+// StubProvider implements Provider without any network access.
+// Its answers only depend on the request, so tests and development machines get stable results.
+// Structured requests get JSON that satisfies the response schema.
+type StubProvider struct{}
+
+// NewStubProvider creates a new instance of StubProvider
+func NewStubProvider() *StubProvider {
+	return &StubProvider{}
+}
+
+// Name identifies the provider
+func (s *StubProvider) Name() string {
+	return ProviderStub
+}
+
+// Generate returns a deterministic answer for the request
+func (s *StubProvider) Generate(ctx context.Context, request GenerateRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	hash := fnv.New64a()
+	for _, part := range request.Parts {
+		hash.Write([]byte(part.Text))
+		hash.Write(part.Data)
+	}
+	seed := hash.Sum64()
+
+	if request.ResponseSchema == nil {
+		return fmt.Sprintf("Offline analysis generated without a language model (request %016x).", seed), nil
+	}
+
+	value := stubValue(request.ResponseSchema, seed, "$")
+	response, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("error encoding stub response: %w", err)
+	}
+	return string(response), nil
+}
+
+// stubValue builds a value matching the schema, derived from the seed and the path of the value
+func stubValue(schema *Schema, seed uint64, path string) any {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%d%s", seed, path)
+	n := hash.Sum64()
+
+	switch schema.Type {
+	case "object":
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		object := make(map[string]any, len(names))
+		for _, name := range names {
+			object[name] = stubValue(schema.Properties[name], seed, path+"."+name)
+		}
+		return object
+	case "array":
+		count := 1
+		if schema.MinItems != nil {
+			count = *schema.MinItems
+		}
+		if schema.MaxItems != nil && count > *schema.MaxItems {
+			count = *schema.MaxItems
+		}
+
+		items := make([]any, 0, count)
+		for i := 0; i < count; i++ {
+			if schema.Items == nil {
+				items = append(items, nil)
+				continue
+			}
+			items = append(items, stubValue(schema.Items, seed, fmt.Sprintf("%s[%d]", path, i)))
+		}
+		return items
+	case "integer", "number":
+		low, high := 0.0, 100.0
+		if schema.Minimum != nil {
+			low = *schema.Minimum
+		}
+		if schema.Maximum != nil {
+			high = *schema.Maximum
+		}
+		if high < low {
+			high = low
+		}
+		return int64(low) + int64(n%uint64(int64(high)-int64(low)+1))
+	case "boolean":
+		return n%2 == 0
+	default:
+		if len(schema.Enum) > 0 {
+			return schema.Enum[n%uint64(len(schema.Enum))]
+		}
+		if schema.Description != "" {
+			return fmt.Sprintf("Offline response: %s", schema.Description)
+		}
+		return fmt.Sprintf("Offline response for %s", path)
+	}
+}
@@ -0,0 +1,188 @@
+package ai
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"unicode/utf8"
+)
+
+// Limits applied to the files sent to the language model
+const (
+	maxTextFileSize     = 100 * 1024       // text files are truncated past this size
+	maxArchiveEntries   = 200              // files inside a single archive
+	maxArchiveSize      = 20 * 1024 * 1024 // uncompressed bytes of a single archive
+	maxArchiveDepth     = 2                // archives nested inside archives
+	maxSubmissionInline = 40 * 1024 * 1024 // bytes sent inline to the model for a whole submission
+)
+
+// textExtensions are extensions of text formats that content sniffing may not recognize
+var textExtensions = map[string]bool{
+	".txt": true, ".md": true, ".markdown": true, ".csv": true, ".json": true, ".yaml": true, ".yml": true,
+	".xml": true, ".html": true, ".css": true, ".sql": true, ".sh": true, ".go": true, ".py": true,
+	".java": true, ".c": true, ".h": true, ".cpp": true, ".hpp": true, ".cs": true, ".js": true,
+	".ts": true, ".jsx": true, ".tsx": true, ".rb": true, ".rs": true, ".kt": true, ".swift": true,
+	".php": true, ".hs": true, ".scala": true, ".r": true, ".m": true, ".ipynb": true, ".tex": true,
+}
+
+// submissionParts converts the text and the files of a submission into parts for the language model.
+// Text formats are sent inline as text, PDFs and PNG/JPEG images as binary data and zip archives
+// are expanded. Files in any other format are listed so the model knows they were not analyzed.
+func submissionParts(submissionContent string, submissionFiles []model.SubmissionFile) ([]Part, error) {
+	parts := []Part{}
+	if content := strings.TrimSpace(submissionContent); content != "" {
+		parts = append(parts, TextPart("Text of the submission:\n\n"+content))
+	}
+
+	budget := &expansionBudget{remaining: maxSubmissionInline}
+	inline := 0
+	for _, file := range submissionFiles {
+		fileParts, err := fileParts(file.Name, file.Content, 0, budget)
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range fileParts {
+			inline += len(part.Text) + len(part.Data)
+		}
+		parts = append(parts, fileParts...)
+	}
+
+	if inline > maxSubmissionInline {
+		return nil, errSubmissionOverInlineLimit()
+	}
+
+	return parts, nil
+}
+
+// expansionBudget counts the bytes the archives of a submission may still decompress to. It is shared by
+// every archive, nested ones included, so archives inside archives cannot multiply the size of a submission.
+type expansionBudget struct {
+	remaining int64
+}
+
+// take uses n bytes of the budget, failing once it is used up
+func (b *expansionBudget) take(n int64) error {
+	if n > b.remaining {
+		return errSubmissionOverInlineLimit()
+	}
+	b.remaining -= n
+	return nil
+}
+
+func errSubmissionOverInlineLimit() error {
+	return fmt.Errorf("%w: the files add up to more than %d MB", utils.ErrSubmissionTooLarge, maxSubmissionInline/(1024*1024))
+}
+
+// fileParts converts a single file into parts, depth being the number of archives containing it
+func fileParts(name string, content []byte, depth int, budget *expansionBudget) ([]Part, error) {
+	switch mimeType := detectMIMEType(name, content); mimeType {
+	case "application/pdf", "image/png", "image/jpeg":
+		return []Part{{MIMEType: mimeType, Data: content}}, nil
+	case "application/zip":
+		if depth >= maxArchiveDepth {
+			return []Part{TextPart(fmt.Sprintf("File %s: nested archive, not analyzed.", name))}, nil
+		}
+		return archiveParts(name, content, depth+1, budget)
+	case "text/plain":
+		return []Part{textFilePart(name, content)}, nil
+	default:
+		return []Part{TextPart(fmt.Sprintf("File %s: %s content, not analyzed.", name, mimeType))}, nil
+	}
+}
+
+// detectMIMEType sniffs the content of a file, using its extension for text formats
+func detectMIMEType(name string, content []byte) string {
+	mimeType, _, _ := strings.Cut(http.DetectContentType(content), ";")
+	switch {
+	case mimeType == "application/pdf", mimeType == "image/png", mimeType == "image/jpeg", mimeType == "application/zip":
+		return mimeType
+	case strings.HasPrefix(mimeType, "text/"), textExtensions[strings.ToLower(path.Ext(name))]:
+		if isText(content) {
+			return "text/plain"
+		}
+	}
+	return mimeType
+}
+
+// isText reports whether the content is UTF-8 text without control characters other than whitespace
+func isText(content []byte) bool {
+	if !utf8.Valid(content) {
+		return false
+	}
+	for _, b := range content {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f' {
+			return false
+		}
+	}
+	return true
+}
+
+// textFilePart sends a text file inline, truncated to maxTextFileSize
+func textFilePart(name string, content []byte) Part {
+	text := string(content)
+	note := ""
+	if len(text) > maxTextFileSize {
+		text = strings.ToValidUTF8(text[:maxTextFileSize], "")
+		note = fmt.Sprintf("\n(truncated, only the first %d KB of %d KB are shown)", maxTextFileSize/1024, len(content)/1024)
+	}
+	return TextPart(fmt.Sprintf("File %s:\n```\n%s\n```%s", name, text, note))
+}
+
+// archiveParts expands a zip archive, rejecting archives over the entry and size limits and failing as soon
+// as the submission uses up its budget
+func archiveParts(name string, content []byte, depth int, budget *expansionBudget) ([]Part, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return []Part{TextPart(fmt.Sprintf("File %s: corrupted zip archive, not analyzed.", name))}, nil
+	}
+	if len(reader.File) > maxArchiveEntries {
+		return nil, fmt.Errorf("%w: archive %s has more than %d files", utils.ErrSubmissionTooLarge, name, maxArchiveEntries)
+	}
+
+	parts := []Part{}
+	remaining := int64(maxArchiveSize)
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+
+		// Reading one byte past the smallest limit is enough to know it was exceeded
+		entryContent, err := readArchiveEntry(entry, min(remaining, budget.remaining)+1)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s from archive %s: %w", entry.Name, name, err)
+		}
+		if int64(len(entryContent)) > remaining {
+			return nil, fmt.Errorf("%w: uncompressed archive %s is larger than %d MB", utils.ErrSubmissionTooLarge, name, maxArchiveSize/(1024*1024))
+		}
+		if err := budget.take(int64(len(entryContent))); err != nil {
+			return nil, err
+		}
+		remaining -= int64(len(entryContent))
+
+		entryParts, err := fileParts(name+"/"+entry.Name, entryContent, depth, budget)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, entryParts...)
+	}
+
+	return parts, nil
+}
+
+// readArchiveEntry decompresses at most limit bytes of an entry.
+// The size declared in the archive is not trusted since it is written by the client.
+func readArchiveEntry(entry *zip.File, limit int64) ([]byte, error) {
+	file, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(io.LimitReader(file, limit))
+}
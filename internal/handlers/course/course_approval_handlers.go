@@ -0,0 +1,181 @@
+package course
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SaveApprovalRules creates or replaces the approval rules of a course
+// @Summary Set the approval rules of a course
+// @Description Set the completion rules a student must meet to approve the course: a minimum final grade, every mandatory assignment submitted and a minimum share of the resources completed. With auto_approve, the rules are applied in the background once the course ends.
+// @Tags approval
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param rules body model.ApprovalRulesRequest true "Approval rules"
+// @Success 200 {object} model.SuccessResponse{data=model.ApprovalRules}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/approval/rules [put]
+func (h *courseHandlerImpl) SaveApprovalRules(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	var req model.ApprovalRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	rules := &model.ApprovalRules{CourseID: courseID}
+	req.ApplyTo(rules)
+	if err := h.repo.SaveApprovalRules(rules); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error saving approval rules")
+		return
+	}
+
+	// Reads them back to return when they last ran
+	saved, err := h.repo.GetApprovalRules(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving approval rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": saved})
+}
+
+// GetApprovalRules retrieves the approval rules of a course
+// @Summary Get the approval rules of a course
+// @Description Get the completion rules of the course and when they were last applied in the background
+// @Tags approval
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=model.ApprovalRules}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/approval/rules [get]
+func (h *courseHandlerImpl) GetApprovalRules(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	rules, ok := h.getApprovalRules(c, courseID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// PreviewApprovals evaluates the approval rules of a course without approving anyone
+// @Summary Preview the approvals of a course
+// @Description Check every enrolled student against the approval rules of the course and report who is eligible and why the others are not, without approving anyone
+// @Tags approval
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=model.ApprovalReport}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/approval/preview [get]
+func (h *courseHandlerImpl) PreviewApprovals(c *gin.Context) {
+	h.evaluateApprovals(c, true)
+}
+
+// RunApprovals approves every student of a course that meets its approval rules
+// @Summary Approve the eligible students of a course
+// @Description Check every enrolled student against the approval rules of the course and approve the eligible ones not approved yet, notifying each of them
+// @Tags approval
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=model.ApprovalReport}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/approval/run [post]
+func (h *courseHandlerImpl) RunApprovals(c *gin.Context) {
+	h.evaluateApprovals(c, false)
+}
+
+func (h *courseHandlerImpl) evaluateApprovals(c *gin.Context, dryRun bool) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	course, ok := h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	rules, ok := h.getApprovalRules(c, courseID)
+	if !ok {
+		return
+	}
+
+	report, err := h.approvals.Evaluate(course, rules, dryRun)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error evaluating approval rules")
+		return
+	}
+
+	if !dryRun && h.metricsClient != nil {
+		tags := []string{fmt.Sprintf("course_id:%d", course.ID)}
+		if err := h.metricsClient.IncrementCounter("classconnect.courses.rule_approvals", tags); err != nil {
+			fmt.Printf("Error sending rule approval metric: %v\n", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+func (h *courseHandlerImpl) getApprovalRules(c *gin.Context, courseID uint) (*model.ApprovalRules, bool) {
+	rules, err := h.repo.GetApprovalRules(courseID)
+	if errors.Is(err, utils.ErrApprovalRulesNotFound) {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "The course has no approval rules")
+		return nil, false
+	}
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving approval rules")
+		return nil, false
+	}
+	return rules, true
+}
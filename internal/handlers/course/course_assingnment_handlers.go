@@ -3,6 +3,7 @@ package course
 import (
 	"net/http"
 	"strconv"
+	middleware "templateGo/internal/middlewares"
 	"templateGo/internal/model"
 	"templateGo/internal/utils"
 
@@ -13,16 +14,21 @@ const TIPO_NOTIFICACTION = "new_assignment"
 
 // CreateAssignment creates a new assignment for a course
 // @Summary Create a new assignment for a course
-// @Description Create a new assignment within the specified course
+// @Description Create a new assignment within the specified course. Files can be sent as multipart/form-data, with the assignment JSON in a data part and each file in a files part, instead of inline in the JSON.
 // @Tags assignments
 // @Accept json
+// @Accept mpfd
 // @Produce json
 // @Param course_id path string true "Course ID"
 // @Param assignment body model.AssignmentRequest true "Assignment information"
+// @Param data formData string false "Assignment information as JSON, for multipart uploads"
+// @Param files formData file false "Files of the assignment, for multipart uploads"
 // @Success 201 {object} model.SuccessResponse{data=model.AssignmentResponse}
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 413 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /{course_id}/assignment [post]
@@ -38,9 +44,10 @@ func (h *courseHandlerImpl) CreateAssignment(c *gin.Context) {
 		return
 	}
 
+	// Files of the assignment itself are only bounded by the limits of the service
 	var req model.CreateAssignmentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+	uploads, ok := h.bindUpload(c, &req, &model.Assignment{})
+	if !ok {
 		return
 	}
 
@@ -51,6 +58,39 @@ func (h *courseHandlerImpl) CreateAssignment(c *gin.Context) {
 		Deadline:    req.Deadline,
 		TimeLimit:   req.TimeLimit,
 		Files:       req.Files,
+
+		LatePolicy:        req.LatePolicy,
+		LatePenaltyPerDay: req.LatePenaltyPerDay,
+		GracePeriod:       req.GracePeriod,
+		MaxAttempts:       req.MaxAttempts,
+		AttemptPolicy:     req.AttemptPolicy,
+		AllowedFileTypes:  req.AllowedFileTypes,
+		MaxFileSize:       req.MaxFileSize,
+		CategoryID:        req.CategoryID,
+		Points:            req.Points,
+		Mandatory:         req.Mandatory,
+	}
+	if assignment.LatePolicy == "" {
+		assignment.LatePolicy = model.LatePolicyAccept
+	}
+	if assignment.AttemptPolicy == "" {
+		assignment.AttemptPolicy = model.AttemptPolicyLast
+	}
+	if assignment.Points == 0 {
+		assignment.Points = model.DefaultAssignmentPoints
+	}
+
+	if !h.setAssignmentRubric(c, assignment, req.Rubric, req.RubricID) {
+		return
+	}
+	if !h.checkAssignmentCategory(c, assignment) {
+		return
+	}
+	if !h.storeAssignmentFiles(c, assignment.Files, nil) {
+		return
+	}
+	for _, file := range uploads {
+		assignment.Files = append(assignment.Files, model.File{Name: file.Name, FileContent: file.FileContent})
 	}
 
 	if err := h.repo.CreateAssignment(assignment); err != nil {
@@ -75,6 +115,7 @@ func (h *courseHandlerImpl) CreateAssignment(c *gin.Context) {
 // @Success 204 "Assignment updated successfully"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -96,6 +137,10 @@ func (h *courseHandlerImpl) UpdateAssignment(c *gin.Context) {
 	if !ok {
 		return
 	}
+	existingAssignment, ok := h.getAssignmentByID(c, uint(assignmentID))
+	if !ok {
+		return
+	}
 
 	var req model.UpdateAssignmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -111,6 +156,43 @@ func (h *courseHandlerImpl) UpdateAssignment(c *gin.Context) {
 		Deadline:    req.Deadline,
 		TimeLimit:   req.TimeLimit,
 		Files:       req.Files,
+
+		LatePolicy:        req.LatePolicy,
+		LatePenaltyPerDay: req.LatePenaltyPerDay,
+		GracePeriod:       req.GracePeriod,
+		MaxAttempts:       req.MaxAttempts,
+		AttemptPolicy:     req.AttemptPolicy,
+		AllowedFileTypes:  req.AllowedFileTypes,
+		MaxFileSize:       req.MaxFileSize,
+		CategoryID:        req.CategoryID,
+		Points:            req.Points,
+	}
+	if assignment.LatePolicy == "" {
+		assignment.LatePolicy = model.LatePolicyAccept
+	}
+	if assignment.AttemptPolicy == "" {
+		assignment.AttemptPolicy = model.AttemptPolicyLast
+	}
+	// The category, points and whether it is mandatory are kept when omitted
+	if assignment.CategoryID == nil {
+		assignment.CategoryID = existingAssignment.CategoryID
+	}
+	if assignment.Points == 0 {
+		assignment.Points = existingAssignment.Points
+	}
+	assignment.Mandatory = existingAssignment.Mandatory
+	if req.Mandatory != nil {
+		assignment.Mandatory = *req.Mandatory
+	}
+
+	if !h.setAssignmentRubric(c, assignment, req.Rubric, req.RubricID) {
+		return
+	}
+	if !h.checkAssignmentCategory(c, assignment) {
+		return
+	}
+	if !h.storeAssignmentFiles(c, assignment.Files, existingAssignment.Files) {
+		return
 	}
 
 	if err := h.repo.UpdateAssignment(assignment); err != nil {
@@ -132,6 +214,7 @@ func (h *courseHandlerImpl) UpdateAssignment(c *gin.Context) {
 // @Param assignment_id path string true "Assignment ID"
 // @Success 204 "Assignment deleted successfully"
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -172,6 +255,7 @@ func (h *courseHandlerImpl) DeleteAssignment(c *gin.Context) {
 // @Param course_id path string true "Course ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -218,6 +302,7 @@ func (h *courseHandlerImpl) GetAssignmentsPreviews(c *gin.Context) {
 // @Param assignment_id path string true "Assignment ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -235,7 +320,7 @@ func (h *courseHandlerImpl) GetAssignmentByID(c *gin.Context) {
 	}
 
 	// Check if course exists
-	course, ok := h.getCourseByID(c, courseID)
+	_, ok = h.getCourseByID(c, courseID)
 	if !ok {
 		return
 	}
@@ -251,8 +336,8 @@ func (h *courseHandlerImpl) GetAssignmentByID(c *gin.Context) {
 		return
 	}
 
-	// If the user is the teacher, just return the assignment
-	if course.CreatedBy == userID {
+	// If the user is a teacher of the course, just return the assignment
+	if middleware.HasRole(c, middleware.RoleOwner, middleware.RoleTeachingAssistant, middleware.RoleAdmin) {
 		c.JSON(http.StatusOK, gin.H{"data": assignment})
 		return
 	}
@@ -271,3 +356,35 @@ func (h *courseHandlerImpl) GetAssignmentByID(c *gin.Context) {
 	})
 
 }
+
+// setAssignmentRubric attaches the rubric given in an assignment request, either a new one or an existing rubric of the course
+func (h *courseHandlerImpl) setAssignmentRubric(c *gin.Context, assignment *model.Assignment, rubricRequest *model.RubricRequest, rubricID *uint) bool {
+	switch {
+	case rubricRequest != nil && rubricID != nil:
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", "Either a rubric or a rubric_id can be given, not both")
+		return false
+	case rubricRequest != nil:
+		rubric := rubricRequest.ToModel(assignment.CourseID)
+		if err := rubric.Validate(); err != nil {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+			return false
+		}
+		assignment.Rubric = rubric
+	case rubricID != nil:
+		rubric, ok := h.getRubricByID(c, assignment.CourseID, *rubricID)
+		if !ok {
+			return false
+		}
+		assignment.RubricID = &rubric.ID
+	}
+	return true
+}
+
+// checkAssignmentCategory checks that the category of the gradebook of an assignment belongs to its course
+func (h *courseHandlerImpl) checkAssignmentCategory(c *gin.Context, assignment *model.Assignment) bool {
+	if assignment.CategoryID == nil {
+		return true
+	}
+	_, ok := h.getGradeCategoryByID(c, assignment.CourseID, *assignment.CategoryID)
+	return ok
+}
@@ -0,0 +1,160 @@
+package course
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAttemptsOfCurrentUser returns every attempt of the current user's submission
+// @Summary Get the attempts of the current user's submission
+// @Description Retrieve every attempt the current user submitted for an assignment, oldest first, each with its own grade and feedback
+// @Tags submissions
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Success 200 {object} model.SuccessResponse{data=[]model.SubmissionAttempt}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/submission/attempts [get]
+func (h *courseHandlerImpl) GetAttemptsOfCurrentUser(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	assignmentID, ok := h.getAssignmentID(c)
+	if !ok {
+		return
+	}
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+	submission, err := h.repo.GetSubmissionByUserID(courseID, assignmentID, userID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Submission not found")
+		return
+	}
+
+	attempts, err := h.repo.GetSubmissionAttempts(submission.ID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving attempts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": attempts})
+}
+
+// GetSubmissionAttempts returns every attempt of a submission
+// @Summary Get the attempts of a submission
+// @Description Retrieve every attempt of a student's submission, oldest first, each with its own grade and feedback
+// @Tags submissions
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Param submission_id path string true "Submission ID"
+// @Success 200 {object} model.SuccessResponse{data=[]model.SubmissionAttempt}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/submission/{submission_id}/attempts [get]
+func (h *courseHandlerImpl) GetSubmissionAttempts(c *gin.Context) {
+	submission, ok := h.getCourseSubmission(c)
+	if !ok {
+		return
+	}
+
+	attempts, err := h.repo.GetSubmissionAttempts(submission.ID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving attempts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": attempts})
+}
+
+// DiffSubmissionAttempts compares two attempts of a submission
+// @Summary Compare two attempts of a submission
+// @Description Get the lines of the submission text inserted and deleted between two attempts, and the files added, removed or changed. By default the latest attempt is compared with the previous one.
+// @Tags submissions
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Param submission_id path string true "Submission ID"
+// @Param from query int false "Number of the older attempt"
+// @Param to query int false "Number of the newer attempt"
+// @Success 200 {object} model.SuccessResponse{data=model.AttemptDiff}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/submission/{submission_id}/attempts/diff [get]
+func (h *courseHandlerImpl) DiffSubmissionAttempts(c *gin.Context) {
+	submission, ok := h.getCourseSubmission(c)
+	if !ok {
+		return
+	}
+
+	to, ok := getAttemptNumber(c, "to", submission.Attempt)
+	if !ok {
+		return
+	}
+	from, ok := getAttemptNumber(c, "from", to-1)
+	if !ok {
+		return
+	}
+
+	fromAttempt, ok := h.getSubmissionAttempt(c, submission.ID, from)
+	if !ok {
+		return
+	}
+	toAttempt, ok := h.getSubmissionAttempt(c, submission.ID, to)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": model.DiffAttempts(fromAttempt, toAttempt)})
+}
+
+func (h *courseHandlerImpl) getSubmissionAttempt(c *gin.Context, submissionID uint, number int) (*model.SubmissionAttempt, bool) {
+	attempt, err := h.repo.GetSubmissionAttempt(submissionID, number)
+	if errors.Is(err, utils.ErrAttemptNotFound) {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Attempt "+strconv.Itoa(number)+" not found")
+		return nil, false
+	}
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving attempt")
+		return nil, false
+	}
+	return attempt, true
+}
+
+// getAttemptNumber reads an attempt number from the query, or returns the default when it is missing
+func getAttemptNumber(c *gin.Context, param string, defaultNumber int) (int, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return defaultNumber, true
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "Attempt "+param+" must be a positive number")
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,305 @@
+package course
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"templateGo/internal/calendar"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCalendarFeed returns the calendar feed of every course of the current user, creating it the first time
+// @Summary Subscribe to my calendar
+// @Description Get the address of an iCalendar feed with the start and end of the courses of the current user, the deadlines of their assignments and the timed sessions the user started. Calendar apps read it without a Bearer token.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse{data=model.CalendarFeed}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /calendar/feeds [post]
+func (h *courseHandlerImpl) CreateCalendarFeed(c *gin.Context) {
+	h.createCalendarFeed(c, 0)
+}
+
+// CreateCourseCalendarFeed returns the calendar feed of a course for the current user, creating it the first time
+// @Summary Subscribe to the calendar of a course
+// @Description Get the address of an iCalendar feed with the start and end of the course, the deadlines of its assignments and the timed sessions the current user started. Calendar apps read it without a Bearer token.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=model.CalendarFeed}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/calendar/feeds [post]
+func (h *courseHandlerImpl) CreateCourseCalendarFeed(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	h.createCalendarFeed(c, courseID)
+}
+
+// GetCalendarFeeds returns the calendar feeds of the current user
+// @Summary Get my calendar feeds
+// @Description Retrieve the calendar feeds of the current user with their addresses
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse{data=[]model.CalendarFeed}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /calendar/feeds [get]
+func (h *courseHandlerImpl) GetCalendarFeeds(c *gin.Context) {
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	feeds, err := h.repo.GetCalendarFeeds(userID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving calendar feeds")
+		return
+	}
+	for i := range feeds {
+		feeds[i].URL = h.calendarFeedURL(c, &feeds[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": feeds})
+}
+
+// DeleteCalendarFeed revokes a calendar feed of the current user
+// @Summary Revoke a calendar feed
+// @Description Revoke a calendar feed of the current user, so its address stops working. Subscribing again creates a new address.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Param feed_id path string true "Feed ID"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /calendar/feeds/{feed_id} [delete]
+func (h *courseHandlerImpl) DeleteCalendarFeed(c *gin.Context) {
+	feedID, ok := h.getFeedID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	err := h.repo.DeleteCalendarFeed(userID, feedID)
+	if errors.Is(err, utils.ErrCalendarFeedNotFound) {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Calendar feed not found")
+		return
+	}
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error revoking calendar feed")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCalendar serves a calendar feed. It does not require authentication, the token of its address is the secret.
+// @Summary Read a calendar feed
+// @Description Get a calendar feed in iCalendar format (RFC 5545), as calendar apps subscribed to it do. The token may end in .ics.
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Token of the feed"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /calendar/{token} [get]
+func (h *courseHandlerImpl) GetCalendar(c *gin.Context) {
+	feed, err := h.repo.GetCalendarFeed(strings.TrimSuffix(c.Param("token"), ".ics"))
+	if errors.Is(err, utils.ErrCalendarFeedNotFound) {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Calendar feed not found")
+		return
+	}
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving calendar feed")
+		return
+	}
+
+	courses, ok := h.calendarCourses(c, feed)
+	if !ok {
+		return
+	}
+
+	courseIDs := make([]uint, len(courses))
+	for i, course := range courses {
+		courseIDs[i] = course.ID
+	}
+	assignments, err := h.repo.GetCoursesAssignments(courseIDs)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving assignments")
+		return
+	}
+
+	// Only timed assignments have sessions worth showing
+	byCourse := make(map[uint][]model.Assignment, len(courses))
+	timed := make(map[uint]model.Assignment)
+	var timedIDs []uint
+	for _, assignment := range assignments {
+		byCourse[assignment.CourseID] = append(byCourse[assignment.CourseID], assignment)
+		if assignment.TimeLimit > 0 {
+			timed[assignment.ID] = assignment
+			timedIDs = append(timedIDs, assignment.ID)
+		}
+	}
+	sessions, err := h.repo.GetAssignmentSessions(feed.UserID, timedIDs)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving assignment sessions")
+		return
+	}
+
+	feedCalendar := calendar.Calendar{Name: "ClassConnect"}
+	if feed.CourseID != 0 {
+		feedCalendar.Name = "ClassConnect - " + courses[0].Title
+	}
+	for i := range courses {
+		feedCalendar.Events = append(feedCalendar.Events, calendar.CourseEvents(&courses[i], byCourse[courses[i].ID])...)
+	}
+	feedCalendar.Events = append(feedCalendar.Events, calendar.SessionEvents(sessions, timed)...)
+
+	c.Header("Content-Disposition", `inline; filename="classconnect.ics"`)
+	// Calendar apps poll the feed, so it is generated on every request
+	c.Header("Cache-Control", "private, no-cache")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feedCalendar.Encode(time.Now()))
+}
+
+func (h *courseHandlerImpl) createCalendarFeed(c *gin.Context, courseID uint) {
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+	userEmail, ok := h.getUserEmailFromToken(c)
+	if !ok {
+		return
+	}
+
+	token, err := newCalendarToken()
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error creating calendar feed")
+		return
+	}
+	feed := &model.CalendarFeed{Token: token, UserID: userID, UserEmail: userEmail, CourseID: courseID}
+	if err := h.repo.CreateCalendarFeed(feed); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error creating calendar feed")
+		return
+	}
+
+	// Reads it back, since the user may already have had a feed for the course
+	feed, err = h.repo.GetUserCalendarFeed(userID, courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving calendar feed")
+		return
+	}
+	feed.URL = h.calendarFeedURL(c, feed)
+
+	c.JSON(http.StatusOK, gin.H{"data": feed})
+}
+
+// calendarCourses returns the courses of a feed. The feed of a course answers not found once its owner is no
+// longer a member of the course, while the feed of every course follows the courses the owner has now.
+func (h *courseHandlerImpl) calendarCourses(c *gin.Context, feed *model.CalendarFeed) ([]model.Course, bool) {
+	if feed.CourseID != 0 {
+		course, err := h.repo.GetByID(feed.CourseID)
+		if err != nil {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Calendar feed not found")
+			return nil, false
+		}
+		if isCourseStaff(course, feed.UserEmail) {
+			return []model.Course{*course}, true
+		}
+		enrolled, err := h.repo.IsUserEnrolled(course.ID, feed.UserID)
+		if err != nil {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error checking course membership")
+			return nil, false
+		}
+		if !enrolled {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Calendar feed not found")
+			return nil, false
+		}
+		return []model.Course{*course}, true
+	}
+
+	enrolled, _, err := h.repo.GetEnrolledCourses(feed.UserID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving enrolled courses")
+		return nil, false
+	}
+	taught, err := h.repo.GetCoursesForTeacher(feed.UserEmail)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving taught courses")
+		return nil, false
+	}
+
+	seen := make(map[uint]bool, len(enrolled)+len(taught))
+	courses := make([]model.Course, 0, len(enrolled)+len(taught))
+	for _, course := range append(enrolled, taught...) {
+		// Templates are not taught, so their dates mean nothing
+		if seen[course.ID] || course.IsTemplate {
+			continue
+		}
+		seen[course.ID] = true
+		courses = append(courses, course)
+	}
+	return courses, true
+}
+
+// calendarFeedURL returns the address calendar apps subscribe to
+func (h *courseHandlerImpl) calendarFeedURL(c *gin.Context, feed *model.CalendarFeed) string {
+	return h.publicURL(c, "/calendar/"+feed.Token+".ics")
+}
+
+// isCourseStaff reports whether the user with an email created or assists a course
+func isCourseStaff(course *model.Course, userEmail string) bool {
+	if userEmail == "" {
+		return false
+	}
+	if course.CreatedBy == userEmail {
+		return true
+	}
+	for _, assistant := range course.TeachingAssistants {
+		if assistant == userEmail {
+			return true
+		}
+	}
+	return false
+}
+
+// newCalendarToken returns a random token for the address of a calendar feed
+func newCalendarToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,232 @@
+package course
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"templateGo/internal/certificates"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyCertificates returns the certificates of the current user
+// @Summary Get my certificates
+// @Description Retrieve the completion certificates issued to the current user, newest first
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse{data=[]model.Certificate}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /certificates [get]
+func (h *courseHandlerImpl) GetMyCertificates(c *gin.Context) {
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	certificates, err := h.repo.GetCertificatesOfUser(userID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving certificates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": certificates})
+}
+
+// GetCourseCertificate returns the certificate of the current user for a course
+// @Summary Get my certificate for a course
+// @Description Retrieve the completion certificate of the current user for a course they approved, issuing it if it was not issued yet
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Certificate}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/certificate [get]
+func (h *courseHandlerImpl) GetCourseCertificate(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	course, ok := h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	// Certificates issued before the course was approved again are kept, so it is looked up first
+	certificate, err := h.repo.GetUserCertificate(courseID, userID)
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"data": certificate})
+		return
+	}
+	if !errors.Is(err, utils.ErrCertificateNotFound) {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving certificate")
+		return
+	}
+
+	approvedUsers, err := h.repo.GetApprovedUsersForCourse(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving approved users")
+		return
+	}
+	approved := false
+	for _, approvedUser := range approvedUsers {
+		if approvedUser == userID {
+			approved = true
+			break
+		}
+	}
+	if !approved {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "You have not approved this course")
+		return
+	}
+
+	certificate, err = h.certificates.Issue(course, userID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error issuing certificate")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": certificate})
+}
+
+// GetCourseCertificates returns the certificates issued for a course
+// @Summary Get the certificates of a course
+// @Description Retrieve the completion certificates issued to the students of a course, newest first
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=[]model.Certificate}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/certificates [get]
+func (h *courseHandlerImpl) GetCourseCertificates(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	certificates, err := h.repo.GetCertificatesOfCourse(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving certificates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": certificates})
+}
+
+// GetCertificatePDF downloads a certificate of the current user as a PDF
+// @Summary Download a certificate
+// @Description Download a completion certificate of the current user as a PDF, with the address to verify it
+// @Tags certificates
+// @Produce application/pdf
+// @Param certificate_id path string true "Certificate ID"
+// @Success 200 {file} file
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /certificates/{certificate_id}/pdf [get]
+func (h *courseHandlerImpl) GetCertificatePDF(c *gin.Context) {
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	certificate, err := h.repo.GetCertificate(c.Param("certificate_id"))
+	// Certificates of other users are reported as missing, so their IDs are not confirmed
+	if errors.Is(err, utils.ErrCertificateNotFound) || (err == nil && certificate.UserID != userID) {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Certificate not found")
+		return
+	}
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving certificate")
+		return
+	}
+
+	name := fmt.Sprintf("certificado-%s.pdf", certificate.ID)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Data(http.StatusOK, "application/pdf", certificates.RenderPDF(certificate, h.certificateVerifyURL(c, certificate)))
+}
+
+// VerifyCertificate checks whether a certificate is genuine. It does not require authentication.
+// @Summary Verify a certificate
+// @Description Check that a certificate was issued by ClassConnect and the signature printed in it is its signature. Only genuine certificates are returned.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param certificate_id path string true "Certificate ID"
+// @Param signature query string true "Signature printed in the certificate"
+// @Success 200 {object} model.SuccessResponse{data=model.CertificateVerification}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /certificates/{certificate_id}/verify [get]
+func (h *courseHandlerImpl) VerifyCertificate(c *gin.Context) {
+	signature := c.Query("signature")
+	if signature == "" {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "The signature must be provided")
+		return
+	}
+
+	verification, err := h.certificates.Verify(c.Param("certificate_id"), signature)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error verifying certificate")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": verification})
+}
+
+// GetCertificatePublicKey returns the key that verifies the certificates. It does not require authentication.
+// @Summary Get the certificate public key
+// @Description Get the Ed25519 public key, in base64, that verifies the signatures of the certificates offline
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse
+// @Router /certificates/public-key [get]
+func (h *courseHandlerImpl) GetCertificatePublicKey(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"algorithm": "Ed25519", "public_key": h.certificates.PublicKey()}})
+}
+
+// issueCertificate issues the certificate of a student who was just approved. The approval stands without
+// it, since the student can get it later from the course.
+func (h *courseHandlerImpl) issueCertificate(course *model.Course, userID string) {
+	if _, err := h.certificates.Issue(course, userID); err != nil {
+		fmt.Printf("Error issuing the certificate of user %s for course %d: %v\n", userID, course.ID, err)
+	}
+}
+
+// certificateVerifyURL returns the address that verifies a certificate
+func (h *courseHandlerImpl) certificateVerifyURL(c *gin.Context, certificate *model.Certificate) string {
+	return h.publicURL(c, fmt.Sprintf("/certificates/%s/verify?signature=%s",
+		url.PathEscape(certificate.ID), url.QueryEscape(certificate.Signature)))
+}
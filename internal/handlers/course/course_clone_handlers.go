@@ -0,0 +1,95 @@
+package course
+
+import (
+	"fmt"
+	"net/http"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CloneCourse creates a new draft course from a course of the teacher
+// @Summary Clone a course
+// @Description Create a draft course with copies of the modules and resources (in the same order), rubrics and assignments with their files. Deadlines move as much as the new start date moves from the start of the course, and the course keeps its length, prerequisites and teaching assistants. The current user owns the clone. Archived courses can be cloned. (teacher only)
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param request body model.CloneCourseRequest true "Start date and optional title and capacity of the clone"
+// @Success 201 {object} model.SuccessResponse{data=model.CourseResponse}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/clone [post]
+func (h *courseHandlerImpl) CloneCourse(c *gin.Context) {
+	h.cloneCourse(c, false)
+}
+
+// InstantiateTemplate creates a new draft course from a template
+// @Summary Create a course from a template
+// @Description Create a draft course from a course marked as a template, with copies of its modules and resources (in the same order), rubrics and assignments with their files. Deadlines move as much as the new start date moves from the start of the template, and the course keeps its length and prerequisites but not the teaching assistants. The current user owns the new course.
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Template course ID"
+// @Param request body model.CloneCourseRequest true "Start date and optional title and capacity of the new course"
+// @Success 201 {object} model.SuccessResponse{data=model.CourseResponse}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /templates/{course_id} [post]
+func (h *courseHandlerImpl) InstantiateTemplate(c *gin.Context) {
+	h.cloneCourse(c, true)
+}
+
+// cloneCourse clones the course of the route for the current user, which must be a template when fromTemplate is set.
+// Only clones of the own courses keep the teaching assistants.
+func (h *courseHandlerImpl) cloneCourse(c *gin.Context, fromTemplate bool) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	userEmail, ok := h.getUserEmailFromToken(c)
+	if !ok {
+		return
+	}
+
+	var req model.CloneCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	source, ok := h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+	if fromTemplate && !source.IsTemplate {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Template not found")
+		return
+	}
+
+	clone := req.ToModel(source, userEmail, !fromTemplate)
+	if err := h.repo.CloneCourse(source, clone); err != nil {
+		if !h.prerequisitesError(c, err) {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error cloning course")
+		}
+		return
+	}
+
+	if h.metricsClient != nil {
+		tags := []string{fmt.Sprintf("source_course_id:%d", source.ID), fmt.Sprintf("template:%t", fromTemplate)}
+		if err := h.metricsClient.IncrementCounter("classconnect.courses.cloned", tags); err != nil {
+			fmt.Printf("Error sending course clone metric: %v\n", err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": formatCourseResponse(clone)})
+}
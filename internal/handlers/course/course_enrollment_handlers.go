@@ -12,7 +12,7 @@ import (
 
 // EnrollUserInCourse handles user enrollment in a course
 // @Summary Enroll the current user in a course
-// @Description Enroll the authenticated user in the specified course
+// @Description Enroll the authenticated user in the specified course. Users that do not meet the prerequisites of the course are rejected with 403, and courses not open for enrollment with 409.
 // @Tags enrollments
 // @Accept json
 // @Produce json
@@ -20,7 +20,9 @@ import (
 // @Success 200 {object} model.SuccessResponse{message=string}
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /{course_id}/enroll [post]
@@ -38,6 +40,14 @@ func (h *courseHandlerImpl) EnrollUserInCourse(c *gin.Context) {
 	if err := h.repo.EnrollUser(courseID, userID); err != nil {
 		if errors.Is(err, utils.ErrUserAlreadyEnrolled) {
 			utils.NewErrorResponse(c, http.StatusConflict, "Conflict", "User is already enrolled in this course")
+		} else if errors.Is(err, utils.ErrPrerequisitesNotMet) {
+			h.prerequisitesNotMet(c, courseID)
+		} else if errors.Is(err, utils.ErrEnrollmentClosed) {
+			h.enrollmentClosed(c)
+		} else if errors.Is(err, utils.ErrCourseFull) {
+			utils.NewErrorResponse(c, http.StatusConflict, "Course Full", "Course has reached its capacity, join the waitlist instead")
+		} else if errors.Is(err, utils.ErrCourseNotFound) {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Course not found")
 		} else {
 			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error enrolling user in course")
 		}
@@ -75,7 +85,7 @@ func (h *courseHandlerImpl) EnrollUserInCourse(c *gin.Context) {
 
 // UnenrollUserFromCourse handles user unenrollment from a course
 // @Summary Unenroll the current user from a course
-// @Description Remove the authenticated user's enrollment from the specified course
+// @Description Remove the authenticated user's enrollment from the specified course. The freed seat is given to the next user in the waitlist.
 // @Tags enrollments
 // @Accept json
 // @Produce json
@@ -83,6 +93,7 @@ func (h *courseHandlerImpl) EnrollUserInCourse(c *gin.Context) {
 // @Success 200 {object} model.SuccessResponse{message=string}
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -98,11 +109,20 @@ func (h *courseHandlerImpl) UnenrollUserFromCourse(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.UnenrollUser(courseID, userID); err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error unenrolling user from course")
+	promotedUserID, err := h.repo.UnenrollUser(courseID, userID)
+	if err != nil {
+		if errors.Is(err, utils.ErrCourseNotFound) {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Course not found")
+		} else {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error unenrolling user from course")
+		}
 		return
 	}
 
+	if promotedUserID != "" {
+		h.notifyWaitlistPromotions(courseID, []string{promotedUserID})
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully unenrolled"})
 
 }
@@ -143,13 +163,18 @@ func (h *courseHandlerImpl) GetEnrolledCourses(c *gin.Context) {
 
 // GetCourseMembers returns all users enrolled in a course
 // @Summary Retrieve members list for a course ID
-// @Description Get all members enrolled in a specific course
+// @Description Get a page of the members enrolled in a specific course
 // @Tags courses
 // @Accept json
 // @Produce json
 // @Param course_id path string true "Course ID"
-// @Success 200 {object} model.SuccessResponse
+// @Param cursor query string false "Cursor of the page, from next_cursor"
+// @Param limit query int false "Members per page, 20 by default" minimum(1) maximum(100)
+// @Param sort query string false "Sort key, prefixed with - for descending order" Enums(id, -id, user_id, -user_id)
+// @Success 200 {object} model.PageResponse
+// @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -160,11 +185,59 @@ func (h *courseHandlerImpl) GetCourseMembers(c *gin.Context) {
 		return
 	}
 
-	members, err := h.repo.GetCourseMembers(courseID)
+	page, ok := h.bindListQuery(c, nil)
+	if !ok {
+		return
+	}
+
+	enrollments, err := h.repo.ListCourseMembers(courseID, page)
+	if err != nil {
+		h.listError(c, err, "Error retrieving course members")
+		return
+	}
+
+	members := make([]gin.H, 0, len(enrollments.Items))
+	for _, e := range enrollments.Items {
+		members = append(members, gin.H{"user_id": e.UserID})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": members, "next_cursor": enrollments.NextCursor})
+}
+
+// GetCourseEligibility explains which prerequisites of a course the current user meets
+// @Summary Check the prerequisites of a course
+// @Description List every prerequisite group of the course, whether the authenticated user meets it and which of its courses they approved. The user can enroll when every group is met.
+// @Tags enrollments
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Eligibility}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/eligibility [get]
+func (h *courseHandlerImpl) GetCourseEligibility(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := h.getCourseByID(c, courseID); !ok {
+		return
+	}
+
+	eligibility, err := h.repo.GetCourseEligibility(courseID, userID)
 	if err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving course members")
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error checking course prerequisites")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": members})
+	c.JSON(http.StatusOK, gin.H{"data": eligibility})
 }
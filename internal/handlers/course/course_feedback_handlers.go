@@ -21,6 +21,7 @@ import (
 // @Success 201 {object} model.SuccessResponse{data=model.FeedbackResponse}
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -97,13 +98,22 @@ func (h *courseHandlerImpl) CreateCourseFeedback(c *gin.Context) {
 
 // GetCourseFeedbacks returns all feedback for a course
 // @Summary Get all feedback for a course
-// @Description Retrieve all feedback submitted for a specific course
+// @Description Retrieve a page of the feedback submitted for a specific course, newest first by default
 // @Tags feedback
 // @Accept json
 // @Produce json
 // @Param course_id path string true "Course ID"
-// @Success 200 {object} model.SuccessResponse
+// @Param cursor query string false "Cursor of the page, from next_cursor"
+// @Param limit query int false "Feedbacks per page, 20 by default" minimum(1) maximum(100)
+// @Param sort query string false "Sort key, prefixed with - for descending order" Enums(created_at, -created_at, rating, -rating)
+// @Param min_rating query int false "Minimum rating" minimum(1) maximum(5)
+// @Param max_rating query int false "Maximum rating" minimum(1) maximum(5)
+// @Param created_from query string false "Created at or after, RFC 3339" format(date-time)
+// @Param created_to query string false "Created at or before, RFC 3339" format(date-time)
+// @Success 200 {object} model.PageResponse{data=[]model.CourseFeedback}
+// @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -120,13 +130,19 @@ func (h *courseHandlerImpl) GetCourseFeedbacks(c *gin.Context) {
 		return
 	}
 
-	feedbackList, err := h.repo.GetFeedbacksForCourse(courseID)
+	var filter model.FeedbackFilter
+	page, ok := h.bindListQuery(c, &filter)
+	if !ok {
+		return
+	}
+
+	feedbackList, err := h.repo.ListCourseFeedbacks(courseID, filter, page)
 	if err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving feedback")
+		h.listError(c, err, "Error retrieving feedback")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": feedbackList})
+	c.JSON(http.StatusOK, gin.H{"data": feedbackList.Items, "next_cursor": feedbackList.NextCursor})
 }
 
 // GetAICourseFeedbackAnalysis returns AI-generated analysis of course feedback
@@ -138,6 +154,7 @@ func (h *courseHandlerImpl) GetCourseFeedbacks(c *gin.Context) {
 // @Param course_id path string true "Course ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -170,7 +187,7 @@ func (h *courseHandlerImpl) GetAICourseFeedbackAnalysis(c *gin.Context) {
 	}
 
 	// Use the AI analyzer to analyze the feedback
-	analysis, err := h.aiAnalyzer.GenerateCourseFeedbackAnalysis(course.Title, feedbacks)
+	analysis, err := h.aiAnalyzer.GenerateCourseFeedbackAnalysis(c.Request.Context(), course.Title, feedbacks)
 	if err != nil {
 		utils.NewErrorResponse(c, http.StatusInternalServerError, "AI Analysis Error", err.Error())
 		return
@@ -184,13 +201,14 @@ func (h *courseHandlerImpl) GetAICourseFeedbackAnalysis(c *gin.Context) {
 
 // GetAIUserFeedbackAnalysis returns AI-generated analysis of user feedback
 // @Summary Get AI-generated analysis of user feedbacks
-// @Description Get AI-powered analysis and insights from user feedback
+// @Description Get AI-powered analysis and insights from user feedback. Only the user, the staff of a course the user is enrolled in and admins may read it.
 // @Tags feedback
 // @Accept json
 // @Produce json
 // @Param user_id path string true "User ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -218,7 +236,7 @@ func (h *courseHandlerImpl) GetAIUserFeedbackAnalysis(c *gin.Context) {
 	}
 
 	// Use the AI analyzer to analyze the feedback
-	analysis, err := h.aiAnalyzer.GenerateUserFeedbackAnalysis(feedbacks)
+	analysis, err := h.aiAnalyzer.GenerateUserFeedbackAnalysis(c.Request.Context(), feedbacks)
 	if err != nil {
 		utils.NewErrorResponse(c, http.StatusInternalServerError, "AI Analysis Error", err.Error())
 		return
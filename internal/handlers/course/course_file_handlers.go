@@ -0,0 +1,198 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	middleware "templateGo/internal/middlewares"
+	"templateGo/internal/model"
+	"templateGo/internal/storage"
+	"templateGo/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAssignmentFile downloads a file of an assignment
+// @Summary Download a file of an assignment
+// @Description Stream the content of a file attached to an assignment. Range requests are supported to resume downloads or read part of the file.
+// @Tags assignments
+// @Produce octet-stream
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Param file_id path string true "File ID"
+// @Param Range header string false "Byte range to download, such as bytes=0-1023"
+// @Success 200 {file} file "File content"
+// @Success 206 {file} file "Requested range of the file content"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 416 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/file/{file_id} [get]
+func (h *courseHandlerImpl) GetAssignmentFile(c *gin.Context) {
+	fileID, ok := h.getFileID(c)
+	if !ok {
+		return
+	}
+	assignment, ok := h.getCourseAssignment(c)
+	if !ok {
+		return
+	}
+
+	for _, file := range assignment.Files {
+		if file.ID == fileID {
+			h.serveFile(c, file.Name, file.FileContent)
+			return
+		}
+	}
+	utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "File not found")
+}
+
+// GetSubmissionFile downloads a file of a submission
+// @Summary Download a file of a submission
+// @Description Stream the content of a file of any attempt of a submission. Students can only download the files of their own submissions. Range requests are supported to resume downloads or read part of the file.
+// @Tags submissions
+// @Produce octet-stream
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Param submission_id path string true "Submission ID"
+// @Param file_id path string true "File ID"
+// @Param Range header string false "Byte range to download, such as bytes=0-1023"
+// @Success 200 {file} file "File content"
+// @Success 206 {file} file "Requested range of the file content"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 416 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/submission/{submission_id}/file/{file_id} [get]
+func (h *courseHandlerImpl) GetSubmissionFile(c *gin.Context) {
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+	fileID, ok := h.getFileID(c)
+	if !ok {
+		return
+	}
+	submission, ok := h.getCourseSubmission(c)
+	if !ok {
+		return
+	}
+
+	isStaff := middleware.HasRole(c, middleware.RoleOwner, middleware.RoleTeachingAssistant, middleware.RoleAdmin)
+	if !isStaff && submission.UserID != userID {
+		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "You can only download the files of your own submissions")
+		return
+	}
+
+	file, err := h.repo.GetSubmissionFile(submission.ID, fileID)
+	if errors.Is(err, utils.ErrFileNotFound) {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "File not found")
+		return
+	}
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving file")
+		return
+	}
+
+	h.serveFile(c, file.Name, file.FileContent)
+}
+
+// serveFile streams a file from the blob store, answering Range and conditional requests
+func (h *courseHandlerImpl) serveFile(c *gin.Context, name string, content model.FileContent) {
+	blob, err := h.blobs.Open(c.Request.Context(), content.SHA256)
+	if errors.Is(err, utils.ErrBlobNotFound) {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "File content not found")
+		return
+	}
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error reading file")
+		return
+	}
+	defer blob.Close()
+
+	contentType := content.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	// Contents never change under a digest, so it is a strong validator for If-Range and If-None-Match
+	c.Header("ETag", `"`+content.SHA256+`"`)
+	c.Header("Cache-Control", "private, max-age=31536000, immutable")
+
+	http.ServeContent(c.Writer, c.Request, name, time.Time{}, blob)
+}
+
+// storeFileContent moves the content of an uploaded file to the blob store, keeping its digest, type and size
+func (h *courseHandlerImpl) storeFileContent(c *gin.Context, content *model.FileContent) bool {
+	digest, err := storage.SaveBytes(c.Request.Context(), h.blobs, content.Content)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error storing file")
+		return false
+	}
+
+	content.SHA256 = digest
+	content.Size = int64(len(content.Content))
+	content.ContentType = http.DetectContentType(content.Content)
+	content.Content = nil
+	return true
+}
+
+// storeAssignmentFiles stores the content of the uploaded files of an assignment. Files sent without content
+// but with the ID of a file the assignment already has keep that file.
+func (h *courseHandlerImpl) storeAssignmentFiles(c *gin.Context, files []model.File, existing []model.File) bool {
+	for i := range files {
+		if files[i].ID != 0 && len(files[i].Content) == 0 {
+			found := false
+			for _, file := range existing {
+				if file.ID == files[i].ID {
+					files[i] = file
+					found = true
+					break
+				}
+			}
+			if !found {
+				utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", fmt.Sprintf("File %d is not a file of the assignment", files[i].ID))
+				return false
+			}
+			continue
+		}
+
+		files[i].ID = 0
+		if !h.storeFileContent(c, &files[i].FileContent) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadFileContents reads the content of submission files from the blob store, for handlers that need the bytes
+func (h *courseHandlerImpl) loadFileContents(ctx context.Context, files []model.SubmissionFile) error {
+	for i := range files {
+		if err := h.loadFileContent(ctx, &files[i].FileContent); err != nil {
+			return fmt.Errorf("error reading file %s: %w", files[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func (h *courseHandlerImpl) loadFileContent(ctx context.Context, content *model.FileContent) error {
+	blob, err := h.blobs.Open(ctx, content.SHA256)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	content.Content, err = io.ReadAll(blob)
+	return err
+}
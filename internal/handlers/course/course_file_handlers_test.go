@@ -0,0 +1,84 @@
+package course
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	middleware "templateGo/internal/middlewares"
+	"templateGo/internal/model"
+	"templateGo/internal/repositories"
+	"templateGo/internal/storage"
+	"templateGo/internal/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeFileRepository keeps submissions and their files in memory
+type fakeFileRepository struct {
+	repositories.CourseRepository
+	submissions map[uint]*model.Submission
+	files       map[uint]*model.SubmissionFile
+}
+
+func (f *fakeFileRepository) GetSubmission(submissionID uint) (*model.Submission, error) {
+	submission, ok := f.submissions[submissionID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return submission, nil
+}
+
+func (f *fakeFileRepository) GetSubmissionFile(submissionID, fileID uint) (*model.SubmissionFile, error) {
+	file, ok := f.files[fileID]
+	if !ok {
+		return nil, utils.ErrFileNotFound
+	}
+	return file, nil
+}
+
+func TestGetSubmissionFile_ScopedToCourse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+	digest, err := storage.SaveBytes(context.Background(), store, []byte("solution"))
+	require.NoError(t, err)
+
+	repo := &fakeFileRepository{
+		submissions: map[uint]*model.Submission{
+			10: {ID: 10, CourseID: 1, AssignmentID: 5, UserID: "student-a"},
+			20: {ID: 20, CourseID: 2, AssignmentID: 6, UserID: "student-b"},
+		},
+		files: map[uint]*model.SubmissionFile{
+			7: {ID: 7, Name: "solution.txt", FileContent: model.FileContent{SHA256: digest, ContentType: "text/plain"}},
+		},
+	}
+	h := &courseHandlerImpl{repo: repo, blobs: store}
+
+	router := gin.New()
+	router.GET("/:course_id/assignment/:assignment_id/submission/:submission_id/file/:file_id", func(c *gin.Context) {
+		// Owner of course 1, as the authorization middleware grants it
+		c.Set("user_id", "teacher-a")
+		c.Set("user_roles", []middleware.Role{middleware.RoleOwner})
+		h.GetSubmissionFile(c)
+	})
+
+	for path, status := range map[string]int{
+		"/1/assignment/5/submission/10/file/7": http.StatusOK,
+		// Submission of course 2, reached through course 1 or with the assignment of course 2
+		"/1/assignment/6/submission/20/file/7": http.StatusNotFound,
+		"/1/assignment/5/submission/20/file/7": http.StatusNotFound,
+		// Submission of course 1 under another assignment
+		"/1/assignment/6/submission/10/file/7": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, status, w.Code, path)
+		if status == http.StatusOK {
+			assert.Equal(t, "solution", w.Body.String())
+		}
+	}
+}
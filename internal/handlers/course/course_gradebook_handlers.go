@@ -0,0 +1,320 @@
+package course
+
+import (
+	"errors"
+	"net/http"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateGradeCategory creates a category of the gradebook of a course
+// @Summary Create a grade category
+// @Description Create a category of assignments, such as homework or exams, with its weight in the final grade and how many of its lowest grades are dropped
+// @Tags gradebook
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param category body model.GradeCategoryRequest true "Category information"
+// @Success 201 {object} model.SuccessResponse{data=model.GradeCategory}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/gradebook/categories [post]
+func (h *courseHandlerImpl) CreateGradeCategory(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	var req model.GradeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	category := &model.GradeCategory{CourseID: courseID}
+	req.ApplyTo(category)
+	if err := h.repo.CreateGradeCategory(category); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error creating grade category")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": category})
+}
+
+// GetGradeCategories retrieves the categories of the gradebook of a course
+// @Summary Get the grade categories of a course
+// @Description Get every category of the gradebook of the course with its weight
+// @Tags gradebook
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=[]model.GradeCategory}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/gradebook/categories [get]
+func (h *courseHandlerImpl) GetGradeCategories(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	categories, err := h.repo.GetGradeCategories(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving grade categories")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": categories})
+}
+
+// UpdateGradeCategory replaces the name, weight and drop rule of a category
+// @Summary Update a grade category
+// @Description Replace the name, weight and number of lowest grades dropped of a category. Final grades are computed again on the next request.
+// @Tags gradebook
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param category_id path string true "Category ID"
+// @Param category body model.GradeCategoryRequest true "Updated category information"
+// @Success 200 {object} model.SuccessResponse{data=model.GradeCategory}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/gradebook/categories/{category_id} [patch]
+func (h *courseHandlerImpl) UpdateGradeCategory(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	categoryID, ok := h.getCategoryID(c)
+	if !ok {
+		return
+	}
+
+	category, ok := h.getGradeCategoryByID(c, courseID, categoryID)
+	if !ok {
+		return
+	}
+
+	var req model.GradeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	req.ApplyTo(category)
+	if err := h.repo.UpdateGradeCategory(category); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error updating grade category")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": category})
+}
+
+// DeleteGradeCategory removes a category of the gradebook
+// @Summary Delete a grade category
+// @Description Delete a category of the gradebook. Its assignments are left without category.
+// @Tags gradebook
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param category_id path string true "Category ID"
+// @Success 204 "Category deleted successfully"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/gradebook/categories/{category_id} [delete]
+func (h *courseHandlerImpl) DeleteGradeCategory(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	categoryID, ok := h.getCategoryID(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := h.getGradeCategoryByID(c, courseID, categoryID); !ok {
+		return
+	}
+
+	if err := h.repo.DeleteGradeCategory(categoryID); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error deleting grade category")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetGradebook computes the final grade of every student of a course
+// @Summary Get the gradebook of a course
+// @Description Get the final grade of every enrolled student, with the grade of each category and how each assignment counts
+// @Tags gradebook
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Gradebook}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/gradebook [get]
+func (h *courseHandlerImpl) GetGradebook(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+	userEmail, ok := h.getUserEmailFromToken(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	categories, assignments, ok := h.getGradebookAssignments(c, courseID, userID, userEmail)
+	if !ok {
+		return
+	}
+
+	members, err := h.repo.GetCourseMembers(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving course members")
+		return
+	}
+
+	// Submissions of each student by assignment
+	submissions := make(map[string]map[uint]*model.Submission, len(members))
+	for _, assignment := range assignments {
+		assignmentSubmissions, err := h.repo.GetSubmissions(courseID, assignment.ID)
+		if err != nil {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving submissions")
+			return
+		}
+		for i, submission := range assignmentSubmissions {
+			if submissions[submission.UserID] == nil {
+				submissions[submission.UserID] = make(map[uint]*model.Submission)
+			}
+			submissions[submission.UserID][assignment.ID] = &assignmentSubmissions[i]
+		}
+	}
+
+	now := time.Now()
+	gradebook := model.Gradebook{Categories: categories, Students: make([]model.StudentGrade, 0, len(members))}
+	for _, member := range members {
+		studentID, _ := member["user_id"].(string)
+		gradebook.Students = append(gradebook.Students,
+			model.ComputeStudentGrade(studentID, categories, assignments, submissions[studentID], now))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gradebook})
+}
+
+// GetGradebookOfCurrentUser computes the final grade of the current user in a course
+// @Summary Get my grades in a course
+// @Description Get the final grade of the current user, with the grade of each category and how each assignment counts
+// @Tags gradebook
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=model.StudentGrade}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/gradebook/me [get]
+func (h *courseHandlerImpl) GetGradebookOfCurrentUser(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+	userEmail, ok := h.getUserEmailFromToken(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	categories, assignments, ok := h.getGradebookAssignments(c, courseID, userID, userEmail)
+	if !ok {
+		return
+	}
+
+	submissions := make(map[uint]*model.Submission, len(assignments))
+	for _, assignment := range assignments {
+		submission, err := h.repo.GetSubmissionByUserID(courseID, assignment.ID, userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving submissions")
+			return
+		}
+		submissions[assignment.ID] = submission
+	}
+
+	grade := model.ComputeStudentGrade(userID, categories, assignments, submissions, time.Now())
+	c.JSON(http.StatusOK, gin.H{"data": grade})
+}
+
+// getGradebookAssignments returns the categories and assignments a gradebook is computed from
+func (h *courseHandlerImpl) getGradebookAssignments(c *gin.Context, courseID uint, userID, userEmail string) ([]model.GradeCategory, []model.AssignmentPreview, bool) {
+	categories, err := h.repo.GetGradeCategories(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving grade categories")
+		return nil, nil, false
+	}
+
+	assignments, err := h.repo.GetAssignmentsPreviews(courseID, userID, userEmail)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving assignments")
+		return nil, nil, false
+	}
+	return categories, assignments, true
+}
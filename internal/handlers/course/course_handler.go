@@ -1,11 +1,15 @@
 package course
 
 import (
+	"os"
+	"templateGo/internal/approval"
+	"templateGo/internal/certificates"
 	"templateGo/internal/handlers/ai"
 	"templateGo/internal/handlers/notification"
 	"templateGo/internal/metrics"
 	"templateGo/internal/queue"
 	"templateGo/internal/repositories"
+	"templateGo/internal/storage"
 )
 
 // courseHandlerImpl implements CourseHandler interface
@@ -15,6 +19,13 @@ type courseHandlerImpl struct {
 	aiAnalyzer        ai.FeedbackAnalyzer
 	metricsClient     *metrics.DatadogMetricsClient
 	statisticsService *queue.StatisticsService
+	blobs             storage.BlobStore
+	approvals         *approval.Engine
+	certificates      *certificates.Issuer
+	uploads           uploadLimits
+	// Public address of the service, for the links read outside of it such as certificates and calendar
+	// feeds. Taken from the request when it is not set.
+	publicBaseURL string
 }
 
 // NewCourseHandler creates a new CourseHandler
@@ -24,6 +35,9 @@ func NewCourseHandler(
 	aiAnalyzer ai.FeedbackAnalyzer,
 	metricsClient *metrics.DatadogMetricsClient,
 	statisticsService *queue.StatisticsService,
+	blobs storage.BlobStore,
+	approvals *approval.Engine,
+	certificates *certificates.Issuer,
 ) CourseHandler {
 	return &courseHandlerImpl{
 		repo:              repo,
@@ -31,5 +45,20 @@ func NewCourseHandler(
 		aiAnalyzer:        aiAnalyzer,
 		metricsClient:     metricsClient,
 		statisticsService: statisticsService,
+		blobs:             blobs,
+		approvals:         approvals,
+		certificates:      certificates,
+		uploads:           uploadLimitsFromEnv(),
+
+		publicBaseURL: publicBaseURLFromEnv(),
+	}
+}
+
+// publicBaseURLFromEnv reads the public address of the service from PUBLIC_BASE_URL, or from
+// CERTIFICATES_BASE_URL, the variable read when only certificates printed it
+func publicBaseURLFromEnv() string {
+	if baseURL := os.Getenv("PUBLIC_BASE_URL"); baseURL != "" {
+		return baseURL
 	}
+	return os.Getenv("CERTIFICATES_BASE_URL")
 }
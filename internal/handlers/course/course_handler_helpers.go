@@ -1,8 +1,11 @@
 package course
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"templateGo/internal/model"
 	"templateGo/internal/utils"
 
@@ -46,6 +49,51 @@ func (h *courseHandlerImpl) getModuleID(c *gin.Context) (uint, bool) {
 	return uint(id), true
 }
 
+func (h *courseHandlerImpl) getRubricID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("rubric_id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "Rubric ID must be a number")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *courseHandlerImpl) getCategoryID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "Category ID must be a number")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *courseHandlerImpl) getFileID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("file_id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "File ID must be a number")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *courseHandlerImpl) getFeedID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("feed_id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "Feed ID must be a number")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *courseHandlerImpl) getScheduleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("schedule_id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "Schedule ID must be a number")
+		return 0, false
+	}
+	return uint(id), true
+}
+
 func (h *courseHandlerImpl) getUserID(c *gin.Context) (string, bool) {
 	id := c.Param("user_id")
 	if id == "" {
@@ -102,6 +150,47 @@ func (h *courseHandlerImpl) getAssignmentByID(c *gin.Context, assignmentID uint)
 	return assignment, true
 }
 
+// getCourseAssignment returns the assignment of the path, only when it belongs to the course of the path
+func (h *courseHandlerImpl) getCourseAssignment(c *gin.Context) (*model.Assignment, bool) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return nil, false
+	}
+	assignmentID, ok := h.getAssignmentID(c)
+	if !ok {
+		return nil, false
+	}
+	assignment, ok := h.getAssignmentByID(c, assignmentID)
+	if !ok {
+		return nil, false
+	}
+	if assignment.CourseID != courseID {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Assignment not found")
+		return nil, false
+	}
+	return assignment, true
+}
+
+// getRubricByID only finds rubrics of the given course
+func (h *courseHandlerImpl) getRubricByID(c *gin.Context, courseID uint, rubricID uint) (*model.Rubric, bool) {
+	rubric, err := h.repo.GetRubricByID(rubricID)
+	if err != nil || rubric.CourseID != courseID {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Rubric not found")
+		return nil, false
+	}
+	return rubric, true
+}
+
+// getGradeCategoryByID only finds categories of the gradebook of the given course
+func (h *courseHandlerImpl) getGradeCategoryByID(c *gin.Context, courseID uint, categoryID uint) (*model.GradeCategory, bool) {
+	category, err := h.repo.GetGradeCategory(categoryID)
+	if err != nil || category.CourseID != courseID {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Grade category not found")
+		return nil, false
+	}
+	return category, true
+}
+
 // TODO: que hcemos con esto?
 func (h *courseHandlerImpl) getSubmissionByID(c *gin.Context, submissionID uint) (*model.Submission, bool) {
 	submission, err := h.repo.GetSubmission(submissionID)
@@ -112,6 +201,32 @@ func (h *courseHandlerImpl) getSubmissionByID(c *gin.Context, submissionID uint)
 	return submission, true
 }
 
+// getCourseSubmission returns the submission of the path, only when it belongs to the course and the assignment
+// of the path, so the roles granted in one course do not reach the submissions of another
+func (h *courseHandlerImpl) getCourseSubmission(c *gin.Context) (*model.Submission, bool) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return nil, false
+	}
+	assignmentID, ok := h.getAssignmentID(c)
+	if !ok {
+		return nil, false
+	}
+	submissionID, ok := h.getSubmissionID(c)
+	if !ok {
+		return nil, false
+	}
+	submission, ok := h.getSubmissionByID(c, submissionID)
+	if !ok {
+		return nil, false
+	}
+	if submission.CourseID != courseID || submission.AssignmentID != assignmentID {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Submission not found")
+		return nil, false
+	}
+	return submission, true
+}
+
 func (h *courseHandlerImpl) getModuleByID(c *gin.Context, moduleID uint) (*model.Module, bool) {
 	module, err := h.repo.GetModuleByID(moduleID)
 	if err != nil {
@@ -130,6 +245,57 @@ func (h *courseHandlerImpl) getResourceByID(c *gin.Context, resourceID string) (
 	return resource, true
 }
 
+// List helpers
+
+// bindListQuery binds the page and the filter of a list endpoint from the query string
+func (h *courseHandlerImpl) bindListQuery(c *gin.Context, filter any) (model.PageQuery, bool) {
+	var page model.PageQuery
+	if err := c.ShouldBindQuery(&page); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", err.Error())
+		return page, false
+	}
+	if filter != nil {
+		if err := c.ShouldBindQuery(filter); err != nil {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", err.Error())
+			return page, false
+		}
+	}
+	return page, true
+}
+
+// listError answers a failed list query, rejecting unknown cursors, sort keys and empty searches as bad requests
+func (h *courseHandlerImpl) listError(c *gin.Context, err error, detail string) {
+	if errors.Is(err, utils.ErrInvalidCursor) || errors.Is(err, utils.ErrInvalidSort) || errors.Is(err, utils.ErrEmptySearchQuery) {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", err.Error())
+		return
+	}
+	utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", detail)
+}
+
+// prerequisitesError answers a request whose prerequisites could not be saved, returning false for unexpected errors
+func (h *courseHandlerImpl) prerequisitesError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, utils.ErrPrerequisiteInvalid):
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+	case errors.Is(err, utils.ErrPrerequisiteCycle):
+		utils.NewErrorResponse(c, http.StatusConflict, "Prerequisite Cycle", err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// prerequisitesNotMet answers an enrollment of a user that does not meet the prerequisites of the course
+func (h *courseHandlerImpl) prerequisitesNotMet(c *gin.Context, courseID uint) {
+	utils.NewErrorResponse(c, http.StatusForbidden, "Prerequisites Not Met",
+		fmt.Sprintf("User does not meet the prerequisites of this course, see /%d/eligibility for the missing ones", courseID))
+}
+
+// enrollmentClosed answers an enrollment in a course that is not open for enrollment
+func (h *courseHandlerImpl) enrollmentClosed(c *gin.Context) {
+	utils.NewErrorResponse(c, http.StatusConflict, "Enrollment Closed", "Course is not open for enrollment")
+}
+
 // Response formatting helpers
 func formatCoursesResponse(courses []model.Course) []gin.H {
 	response := make([]gin.H, 0, len(courses))
@@ -141,35 +307,30 @@ func formatCoursesResponse(courses []model.Course) []gin.H {
 
 func formatCourseResponse(course *model.Course) gin.H {
 	return gin.H{
-		"id":                  strconv.FormatUint(uint64(course.ID), 10),
-		"title":               course.Title,
-		"description":         course.Description,
-		"createdBy":           course.CreatedBy,
-		"capacity":            course.Capacity,
-		"startDate":           course.StartDate.Format("2006-01-02"),
-		"endDate":             course.EndDate.Format("2006-01-02"),
-		"eligibilityCriteria": course.EligibilityCriteria,
-		"teachingAssistants":  course.TeachingAssistants,
-	}
-}
-
-func contains(slice []string, str string) bool {
-	for _, v := range slice {
-		if v == str {
-			return true
-		}
+		"id":                 strconv.FormatUint(uint64(course.ID), 10),
+		"title":              course.Title,
+		"description":        course.Description,
+		"createdBy":          course.CreatedBy,
+		"capacity":           course.Capacity,
+		"startDate":          course.StartDate.Format("2006-01-02"),
+		"endDate":            course.EndDate.Format("2006-01-02"),
+		"state":              course.State,
+		"isTemplate":         course.IsTemplate,
+		"clonedFromId":       course.ClonedFromID,
+		"prerequisites":      course.PrerequisiteGroups(),
+		"teachingAssistants": course.TeachingAssistants,
 	}
-	return false
 }
 
-func (h *courseHandlerImpl) isCourseCreatorOrAssistant(c *gin.Context, courseId uint) bool {
-	userEmail, ok := h.getUserEmailFromToken(c)
-	if !ok {
-		return false
-	}
-	course, ok := h.getCourseByID(c, courseId)
-	if !ok {
-		return false
+// publicURL returns the address of a path of the service as seen from outside of it
+func (h *courseHandlerImpl) publicURL(c *gin.Context, path string) string {
+	baseURL := h.publicBaseURL
+	if baseURL == "" {
+		scheme := "http"
+		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		baseURL = scheme + "://" + c.Request.Host
 	}
-	return course.CreatedBy == userEmail || contains(course.TeachingAssistants, userEmail)
+	return strings.TrimSuffix(baseURL, "/") + path
 }
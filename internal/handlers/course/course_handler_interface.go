@@ -13,12 +13,29 @@ type CourseHandler interface {
 	UpdateCourse(c *gin.Context)
 	DeleteCourse(c *gin.Context)
 	GetAvailableCourses(c *gin.Context)
+	SearchCourses(c *gin.Context)
+
+	// Course Lifecycle
+	ChangeCourseState(c *gin.Context)
+	GetCourseStateSchedules(c *gin.Context)
+	CancelCourseStateSchedule(c *gin.Context)
+
+	// Course Cloning
+	CloneCourse(c *gin.Context)
+	InstantiateTemplate(c *gin.Context)
 
 	// Enrollment Management
 	EnrollUserInCourse(c *gin.Context)
 	UnenrollUserFromCourse(c *gin.Context)
 	GetEnrolledCourses(c *gin.Context)
 	GetCourseMembers(c *gin.Context)
+	GetCourseEligibility(c *gin.Context)
+
+	// Waitlist Management
+	JoinWaitlist(c *gin.Context)
+	LeaveWaitlist(c *gin.Context)
+	GetWaitlistPosition(c *gin.Context)
+	GetWaitlist(c *gin.Context)
 
 	// Course Feedback Management
 	CreateCourseFeedback(c *gin.Context)
@@ -36,6 +53,22 @@ type CourseHandler interface {
 	DeleteAssignment(c *gin.Context)
 	GetAssignmentsPreviews(c *gin.Context)
 	GetAssignmentByID(c *gin.Context)
+	GetAssignmentFile(c *gin.Context)
+
+	// Rubric Management
+	CreateRubric(c *gin.Context)
+	GetRubrics(c *gin.Context)
+	GetRubric(c *gin.Context)
+	UpdateRubric(c *gin.Context)
+	DeleteRubric(c *gin.Context)
+
+	// Gradebook
+	CreateGradeCategory(c *gin.Context)
+	GetGradeCategories(c *gin.Context)
+	UpdateGradeCategory(c *gin.Context)
+	DeleteGradeCategory(c *gin.Context)
+	GetGradebook(c *gin.Context)
+	GetGradebookOfCurrentUser(c *gin.Context)
 
 	// Submission Management
 	PutSubmissionOfCurrentUser(c *gin.Context)
@@ -44,12 +77,40 @@ type CourseHandler interface {
 	GetSubmissionByUserID(c *gin.Context)
 	GetSubmissions(c *gin.Context)
 	GradeSubmission(c *gin.Context)
+	GradeSubmissionWithRubric(c *gin.Context)
+	ReturnSubmission(c *gin.Context)
 	GetAIGeneratedGradeAndFeedback(c *gin.Context)
+	AcceptAIGradeDraft(c *gin.Context)
+	GetAttemptsOfCurrentUser(c *gin.Context)
+	GetSubmissionAttempts(c *gin.Context)
+	DiffSubmissionAttempts(c *gin.Context)
+	GetSubmissionFile(c *gin.Context)
+	GetSubmissionSimilarity(c *gin.Context)
+	CheckSubmissionSimilarity(c *gin.Context)
 
 	// Course Approval
 	ApproveCourses(c *gin.Context)
 	GetApprovedCourses(c *gin.Context)
 	GetApprovedUsersForCourse(c *gin.Context) // New method
+	SaveApprovalRules(c *gin.Context)
+	GetApprovalRules(c *gin.Context)
+	PreviewApprovals(c *gin.Context)
+	RunApprovals(c *gin.Context)
+
+	// Certificates
+	GetMyCertificates(c *gin.Context)
+	GetCourseCertificate(c *gin.Context)
+	GetCourseCertificates(c *gin.Context)
+	GetCertificatePDF(c *gin.Context)
+	VerifyCertificate(c *gin.Context)
+	GetCertificatePublicKey(c *gin.Context)
+
+	// Calendar Feeds
+	CreateCalendarFeed(c *gin.Context)
+	CreateCourseCalendarFeed(c *gin.Context)
+	GetCalendarFeeds(c *gin.Context)
+	DeleteCalendarFeed(c *gin.Context)
+	GetCalendar(c *gin.Context)
 
 	// Course Favorites
 	ToggleFavoriteStatus(c *gin.Context)
@@ -62,9 +123,12 @@ type CourseHandler interface {
 	PatchResources(c *gin.Context)
 	DeleteResource(c *gin.Context)
 	DeleteModule(c *gin.Context)
+	CompleteResource(c *gin.Context)
 
 	// Statistics
 	GetCoursesStatistics(c *gin.Context)
 	GetCourseStatistics(c *gin.Context)
 	GetUserStatisticsForCourse(c *gin.Context)
+	GetFailedStatisticsTasks(c *gin.Context)
+	RequeueFailedStatisticsTask(c *gin.Context)
 }
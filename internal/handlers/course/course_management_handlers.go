@@ -12,7 +12,7 @@ import (
 
 // CreateCourse handles course creation
 // @Summary Create a new course
-// @Description Create a new course with the provided information
+// @Description Create a new course with the provided information. Prerequisites are groups of course IDs: every group is required, and a group is met by approving any of its courses.
 // @Tags courses
 // @Accept json
 // @Produce json
@@ -35,7 +35,9 @@ func (h *courseHandlerImpl) CreateCourse(c *gin.Context) {
 
 	course := request.ToModel()
 	if err := h.repo.Create(course); err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error creating course")
+		if !h.prerequisitesError(c, err) {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error creating course")
+		}
 		return
 	}
 
@@ -60,23 +62,38 @@ func (h *courseHandlerImpl) CreateCourse(c *gin.Context) {
 
 // GetAllCourses returns all courses
 // @Summary Get all courses
-// @Description Retrieve all courses of the current user
+// @Description Retrieve a page of the courses, following next_cursor for the next one
 // @Tags courses
 // @Accept json
 // @Produce json
-// @Success 200 {object} model.SuccessResponse{data=[]model.CourseResponse}
+// @Param cursor query string false "Cursor of the page, from next_cursor"
+// @Param limit query int false "Courses per page, 20 by default" minimum(1) maximum(100)
+// @Param sort query string false "Sort key, prefixed with - for descending order" Enums(id, -id, created_at, -created_at, title, -title, start_date, -start_date)
+// @Param title query string false "Part of the title"
+// @Param start_from query string false "Courses starting at or after, RFC 3339" format(date-time)
+// @Param start_to query string false "Courses starting at or before, RFC 3339" format(date-time)
+// @Param state query string false "Lifecycle state" Enums(draft, published, enrollment_open, in_progress, archived)
+// @Param template query bool false "Only templates, or only courses that are not templates"
+// @Success 200 {object} model.PageResponse{data=[]model.CourseResponse}
+// @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /courses [get]
 func (h *courseHandlerImpl) GetAllCourses(c *gin.Context) {
-	courses, err := h.repo.GetAll()
+	var filter model.CourseFilter
+	page, ok := h.bindListQuery(c, &filter)
+	if !ok {
+		return
+	}
+
+	courses, err := h.repo.ListCourses(filter, page)
 	if err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving courses")
+		h.listError(c, err, "Error retrieving courses")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": formatCoursesResponse(courses)})
+	c.JSON(http.StatusOK, gin.H{"data": formatCoursesResponse(courses.Items), "next_cursor": courses.NextCursor})
 
 }
 
@@ -109,16 +126,18 @@ func (h *courseHandlerImpl) GetCourseByID(c *gin.Context) {
 
 // UpdateCourse updates an existing course
 // @Summary Update a course by ID
-// @Description Update the details of an existing course
+// @Description Update the details of an existing course. Prerequisites that would make the course a prerequisite of itself are rejected with 409.
 // @Tags courses
 // @Accept json
 // @Produce json
 // @Param course_id path string true "Course ID"
-// @Param course body model.CreateCourseRequest true "Updated course information"
+// @Param course body model.UpdateCourseRequest true "Updated course information"
 // @Success 204 "Course updated successfully"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /{course_id} [patch]
@@ -139,6 +158,15 @@ func (h *courseHandlerImpl) UpdateCourse(c *gin.Context) {
 		return
 	}
 
+	if updateRequest.Prerequisites != nil {
+		if err := h.repo.SetCoursePrerequisites(courseID, *updateRequest.Prerequisites); err != nil {
+			if !h.prerequisitesError(c, err) {
+				utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error updating course prerequisites")
+			}
+			return
+		}
+	}
+
 	updateRequest.ApplyTo(existingCourse)
 
 	if err := h.repo.Update(existingCourse); err != nil {
@@ -146,6 +174,15 @@ func (h *courseHandlerImpl) UpdateCourse(c *gin.Context) {
 		return
 	}
 
+	// A capacity increase frees seats for waitlisted users
+	if updateRequest.Capacity != nil {
+		promoted, err := h.repo.PromoteFromWaitlist(courseID)
+		if err != nil {
+			fmt.Printf("Error promoting waitlisted users for course %d: %v\n", courseID, err)
+		}
+		h.notifyWaitlistPromotions(courseID, promoted)
+	}
+
 	c.Status(http.StatusNoContent)
 }
 
@@ -158,6 +195,7 @@ func (h *courseHandlerImpl) UpdateCourse(c *gin.Context) {
 // @Param course_id path string true "Course ID"
 // @Success 204 "Course deleted successfully"
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -179,11 +217,18 @@ func (h *courseHandlerImpl) DeleteCourse(c *gin.Context) {
 
 // GetAvailableCourses returns courses the user can enroll in based on eligibility criteria
 // @Summary Retrieve all available courses for the current user
-// @Description Returns all courses that the currently authenticated user is eligible to join
+// @Description Returns a page of the courses that the currently authenticated user is eligible to join: courses open for enrollment they are not enrolled in whose prerequisites they meet
 // @Tags courses
 // @Accept json
 // @Produce json
-// @Success 200 {object} model.SuccessResponse{data=[]model.CourseResponse}
+// @Param cursor query string false "Cursor of the page, from next_cursor"
+// @Param limit query int false "Courses per page, 20 by default" minimum(1) maximum(100)
+// @Param sort query string false "Sort key, prefixed with - for descending order" Enums(id, -id, created_at, -created_at, title, -title, start_date, -start_date)
+// @Param title query string false "Part of the title"
+// @Param start_from query string false "Courses starting at or after, RFC 3339" format(date-time)
+// @Param start_to query string false "Courses starting at or before, RFC 3339" format(date-time)
+// @Success 200 {object} model.PageResponse{data=[]model.CourseResponse}
+// @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -199,50 +244,70 @@ func (h *courseHandlerImpl) GetAvailableCourses(c *gin.Context) {
 		return
 	}
 
-	// Get all courses the user is not enrolled in
-	availableCourses, err := h.repo.GetAvailableCourses(userID)
-	if err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving available courses")
+	var filter model.CourseFilter
+	page, ok := h.bindListQuery(c, &filter)
+	if !ok {
 		return
 	}
 
-	// Get the user's approved courses/subjects
-	approvedSubjects, err := h.repo.GetApprovedCourses(userID)
+	// Get the courses the user is not enrolled in and meets the prerequisites of
+	availableCourses, err := h.repo.ListAvailableCourses(userID, filter, page)
 	if err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving user's approved subjects")
+		h.listError(c, err, "Error retrieving available courses")
 		return
 	}
 
-	// Filter courses based on eligibility criteria
-	var eligibleCourses []model.Course
-	for _, course := range availableCourses {
-		if meetsEligibilityCriteria(course, approvedSubjects) {
-			eligibleCourses = append(eligibleCourses, course)
-		}
-	}
-
-	c.JSON(http.StatusOK, gin.H{"data": formatCoursesResponse(eligibleCourses)})
+	c.JSON(http.StatusOK, gin.H{"data": formatCoursesResponse(availableCourses.Items), "next_cursor": availableCourses.NextCursor})
 }
 
-// meetsEligibilityCriteria checks if a user with the given approved subjects meets a course's requirements
-func meetsEligibilityCriteria(course model.Course, approvedSubjects []string) bool {
-	// If there are no eligibility criteria, anyone can enroll
-	if len(course.EligibilityCriteria) == 0 {
-		return true
+// SearchCourses searches the course catalog
+// @Summary Search the course catalog
+// @Description Full-text search over the titles, descriptions, module and resource names of the courses in the catalog, which leaves out drafts and archived courses. Every word must match, also as the prefix of a longer one, and courses are ranked by where they match: title first, then description, modules and resources. Matches are highlighted between <mark> tags.
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param q query string true "Words to search"
+// @Param cursor query string false "Cursor of the page, from next_cursor"
+// @Param limit query int false "Courses per page, 20 by default" minimum(1) maximum(100)
+// @Param sort query string false "Sort key, prefixed with - for descending order" Enums(-relevance, relevance, id, -id, title, -title, start_date, -start_date)
+// @Param start_from query string false "Courses starting at or after, RFC 3339" format(date-time)
+// @Param start_to query string false "Courses starting at or before, RFC 3339" format(date-time)
+// @Param has_seats query bool false "Only courses with free seats"
+// @Param eligible query bool false "Only courses whose prerequisites the user meets"
+// @Success 200 {object} model.PageResponse{data=[]model.CourseSearchResponse}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /search [get]
+func (h *courseHandlerImpl) SearchCourses(c *gin.Context) {
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	var search model.CourseSearchQuery
+	page, ok := h.bindListQuery(c, &search)
+	if !ok {
+		return
 	}
 
-	// Create a map for O(1) lookups of approved subjects
-	approvedMap := make(map[string]bool)
-	for _, subject := range approvedSubjects {
-		approvedMap[subject] = true
+	results, err := h.repo.SearchCourses(search, userID, page)
+	if err != nil {
+		h.listError(c, err, "Error searching courses")
+		return
 	}
 
-	// Check if all eligibility criteria are met
-	for _, requirement := range course.EligibilityCriteria {
-		if !approvedMap[requirement] {
-			return false // User doesn't meet this requirement
+	response := make([]gin.H, 0, len(results.Items))
+	for _, result := range results.Items {
+		course := formatCourseResponse(&result.Course)
+		course["rank"] = result.Rank
+		course["highlights"] = gin.H{
+			"title":       result.TitleHighlight,
+			"description": result.DescriptionHighlight,
 		}
+		response = append(response, course)
 	}
 
-	return true // All requirements are met
+	c.JSON(http.StatusOK, gin.H{"data": response, "next_cursor": results.NextCursor})
 }
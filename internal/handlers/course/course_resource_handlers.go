@@ -10,6 +10,7 @@ import (
 	"os"
 	"templateGo/internal/model"
 	"templateGo/internal/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,6 +27,7 @@ import (
 // @Success 201 {object} model.SuccessResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -70,6 +72,7 @@ func (h *courseHandlerImpl) CreateModule(c *gin.Context) {
 // @Success 201 {object} model.SuccessResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -200,6 +203,7 @@ func (h *courseHandlerImpl) postResource(fileHeader *multipart.FileHeader, cours
 // @Success 204 "Module updated successfully"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -236,6 +240,7 @@ func (h *courseHandlerImpl) PatchModule(c *gin.Context) {
 // @Param course_id path string true "Course ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -286,6 +291,7 @@ func (h *courseHandlerImpl) GetResources(c *gin.Context) {
 // @Param resource_id path string true "Resource ID"
 // @Success 204 "Resource deleted successfully"
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -314,6 +320,7 @@ func (h *courseHandlerImpl) DeleteResource(c *gin.Context) {
 // @Param module_id path string true "Module ID"
 // @Success 204 "Module deleted successfully"
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -346,6 +353,7 @@ func (h *courseHandlerImpl) DeleteModule(c *gin.Context) {
 // @Success 204 "Resources order updated successfully"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -419,3 +427,53 @@ func (h *courseHandlerImpl) PatchResources(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Modules and resources order updated successfully"})
 }
+
+// CompleteResource marks a resource as completed by the current user
+// @Summary Mark a resource as completed
+// @Description Record that the current user completed a resource of the course, which approval rules can require. Marking it again does nothing.
+// @Tags resources
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param module_id path string true "Module ID"
+// @Param resource_id path string true "Resource ID"
+// @Success 204 "Resource marked as completed"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/resource/module/{module_id}/{resource_id}/complete [post]
+func (h *courseHandlerImpl) CompleteResource(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	moduleID, ok := h.getModuleID(c)
+	if !ok {
+		return
+	}
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	module, ok := h.getModuleByID(c, moduleID)
+	if !ok {
+		return
+	}
+	resource, err := h.repo.GetResourceByID(c.Param("resource_id"))
+	if err != nil || module.CourseID != courseID || resource.ModuleID != moduleID {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Resource not found")
+		return
+	}
+
+	completion := &model.ResourceCompletion{ResourceID: resource.ID, UserID: userID, CompletedAt: time.Now()}
+	if err := h.repo.CompleteResource(completion); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error marking resource as completed")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,222 @@
+package course
+
+import (
+	"errors"
+	"net/http"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateRubric creates a reusable rubric for a course
+// @Summary Create a rubric for a course
+// @Description Create a rubric with weighted criteria and the points of each level. Rubrics can be attached to any assignment of the course.
+// @Tags rubrics
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param rubric body model.RubricRequest true "Rubric information"
+// @Success 201 {object} model.SuccessResponse{data=model.Rubric}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/rubric [post]
+func (h *courseHandlerImpl) CreateRubric(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	var req model.RubricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	rubric := req.ToModel(courseID)
+	if err := rubric.Validate(); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	if err := h.repo.CreateRubric(rubric); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error creating rubric")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": rubric})
+}
+
+// GetRubrics retrieves the rubrics of a course
+// @Summary Get the rubrics of a course
+// @Description Get every rubric defined in the course
+// @Tags rubrics
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=[]model.Rubric}
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/rubrics [get]
+func (h *courseHandlerImpl) GetRubrics(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	// Check if course exists
+	_, ok = h.getCourseByID(c, courseID)
+	if !ok {
+		return
+	}
+
+	rubrics, err := h.repo.GetRubricsByCourse(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving rubrics")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rubrics})
+}
+
+// GetRubric retrieves a rubric of a course
+// @Summary Get a rubric
+// @Description Get a rubric of the course with its criteria and levels
+// @Tags rubrics
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param rubric_id path string true "Rubric ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Rubric}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/rubric/{rubric_id} [get]
+func (h *courseHandlerImpl) GetRubric(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	rubricID, ok := h.getRubricID(c)
+	if !ok {
+		return
+	}
+
+	rubric, ok := h.getRubricByID(c, courseID, rubricID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rubric})
+}
+
+// UpdateRubric replaces the title and criteria of a rubric
+// @Summary Update a rubric
+// @Description Replace the title and criteria of a rubric. Submissions already graded keep the scores they were given.
+// @Tags rubrics
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param rubric_id path string true "Rubric ID"
+// @Param rubric body model.RubricRequest true "Updated rubric information"
+// @Success 200 {object} model.SuccessResponse{data=model.Rubric}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/rubric/{rubric_id} [patch]
+func (h *courseHandlerImpl) UpdateRubric(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	rubricID, ok := h.getRubricID(c)
+	if !ok {
+		return
+	}
+
+	rubric, ok := h.getRubricByID(c, courseID, rubricID)
+	if !ok {
+		return
+	}
+
+	var req model.RubricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	rubric.Title = req.Title
+	rubric.Criteria = req.Criteria
+	if err := rubric.Validate(); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	if err := h.repo.UpdateRubric(rubric); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error updating rubric")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rubric})
+}
+
+// DeleteRubric removes a rubric that no assignment uses
+// @Summary Delete a rubric
+// @Description Delete a rubric of the course. Rubrics attached to assignments cannot be deleted.
+// @Tags rubrics
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param rubric_id path string true "Rubric ID"
+// @Success 204 "Rubric deleted successfully"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/rubric/{rubric_id} [delete]
+func (h *courseHandlerImpl) DeleteRubric(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	rubricID, ok := h.getRubricID(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := h.getRubricByID(c, courseID, rubricID); !ok {
+		return
+	}
+
+	if err := h.repo.DeleteRubric(rubricID); err != nil {
+		if errors.Is(err, utils.ErrRubricInUse) {
+			utils.NewErrorResponse(c, http.StatusConflict, "Conflict", "The rubric is attached to an assignment")
+			return
+		}
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error deleting rubric")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
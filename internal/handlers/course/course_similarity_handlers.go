@@ -0,0 +1,86 @@
+package course
+
+import (
+	"net/http"
+	"strconv"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSubmissionSimilarity returns the similarity report of the submissions of an assignment
+// @Summary Get the similarity report of an assignment
+// @Description Retrieve the pairs of submissions of an assignment that share fragments, most similar first, with the matched fragments of each pair. Source code is compared ignoring comments, layout and identifier names, and fragments of the files of the assignment are not counted. The report is computed in the background a minute after the last submission.
+// @Tags submissions
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Param min_score query number false "Lowest score of the pairs returned, from 0 to 1"
+// @Success 200 {object} model.SuccessResponse{data=model.SimilarityReport}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/similarity [get]
+func (h *courseHandlerImpl) GetSubmissionSimilarity(c *gin.Context) {
+	assignment, ok := h.getCourseAssignment(c)
+	if !ok {
+		return
+	}
+
+	minScore := 0.0
+	if value := c.Query("min_score"); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil || score < 0 || score > 1 {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "min_score must be a number from 0 to 1")
+			return
+		}
+		minScore = score
+	}
+
+	similarities, err := h.repo.GetSubmissionSimilarities(assignment.ID, minScore)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving similarity report")
+		return
+	}
+
+	report := model.SimilarityReport{AssignmentID: assignment.ID, Pairs: similarities}
+	if len(similarities) > 0 {
+		report.CheckedAt = &similarities[0].CheckedAt
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// CheckSubmissionSimilarity requests a new similarity check of the submissions of an assignment
+// @Summary Check the similarity of the submissions of an assignment
+// @Description Enqueue a new comparison of every pair of submissions of an assignment, which replaces its similarity report once finished
+// @Tags submissions
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Success 202 {object} model.SuccessResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/similarity [post]
+func (h *courseHandlerImpl) CheckSubmissionSimilarity(c *gin.Context) {
+	assignment, ok := h.getCourseAssignment(c)
+	if !ok {
+		return
+	}
+
+	if err := h.statisticsService.EnqueueSubmissionSimilarityCheck(assignment.ID); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error enqueueing similarity check")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Similarity check enqueued"})
+}
@@ -1,6 +1,7 @@
 package course
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -20,6 +21,7 @@ import (
 // @Success 200 {object} model.SuccessResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -46,7 +48,7 @@ func (h *courseHandlerImpl) ApproveCourses(c *gin.Context) {
 
 	// Now use both the course ID and name
 	if err := h.repo.ApproveCourse(userID, uint(courseID), course.Title); err != nil {
-		if strings.Contains(err.Error(), "User already approved") {
+		if errors.Is(err, utils.ErrAlreadyApproved) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "User already approved"})
 			return
 		}
@@ -61,6 +63,7 @@ func (h *courseHandlerImpl) ApproveCourses(c *gin.Context) {
 	}
 
 	h.notification.SendNotification(userID, course.Title, "course_approve")
+	h.issueCertificate(course, userID)
 	c.JSON(http.StatusOK, gin.H{"message": "Course approved successfully"})
 }
 
@@ -99,6 +102,7 @@ func (h *courseHandlerImpl) GetApprovedCourses(c *gin.Context) {
 // @Param course_id path string true "Course ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -133,6 +137,7 @@ func (h *courseHandlerImpl) ToggleFavoriteStatus(c *gin.Context) {
 // @Param course_id path string true "Course ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
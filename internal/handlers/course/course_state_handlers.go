@@ -0,0 +1,155 @@
+package course
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeCourseState moves a course to another state of its lifecycle, now or at a scheduled time
+// @Summary Change the state of a course
+// @Description Move a course to another state: draft, published, enrollment_open, in_progress or archived. With a future at, the change is scheduled and applied by the background scheduler, checking the transition against the state the course has then. Transitions the current state does not allow are rejected with 409, and archived courses cannot change. (owner only)
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param request body model.ChangeCourseStateRequest true "New state and when to apply it"
+// @Success 200 {object} model.SuccessResponse{data=model.CourseResponse}
+// @Success 202 {object} model.SuccessResponse{data=model.CourseStateSchedule}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/state [post]
+func (h *courseHandlerImpl) ChangeCourseState(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	var req model.ChangeCourseStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	if req.At != nil && req.At.After(time.Now()) {
+		if _, ok := h.getCourseByID(c, courseID); !ok {
+			return
+		}
+
+		schedule := model.CourseStateSchedule{CourseID: courseID, State: req.State, RunAt: *req.At, CreatedBy: userID}
+		if err := h.repo.ScheduleCourseState(&schedule); err != nil {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error scheduling course state")
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"data": schedule})
+		return
+	}
+
+	course, err := h.repo.ChangeCourseState(courseID, req.State)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrCourseNotFound):
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Course not found")
+		case errors.Is(err, utils.ErrInvalidTransition):
+			utils.NewErrorResponse(c, http.StatusConflict, "Invalid Transition", err.Error())
+		default:
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error changing course state")
+		}
+		return
+	}
+
+	// Seats freed while enrollment was closed go to waitlisted users first
+	if course.AcceptsEnrollments() {
+		promoted, err := h.repo.PromoteFromWaitlist(courseID)
+		if err != nil {
+			fmt.Printf("Error promoting waitlisted users for course %d: %v\n", courseID, err)
+		}
+		h.notifyWaitlistPromotions(courseID, promoted)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": formatCourseResponse(course)})
+}
+
+// GetCourseStateSchedules returns the scheduled changes of state of a course
+// @Summary Get the scheduled changes of state of a course
+// @Description Retrieve the changes of state scheduled for a course, the next one first, with the ones already processed and why any failed (teacher only)
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse{data=[]model.CourseStateSchedule}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/state/schedules [get]
+func (h *courseHandlerImpl) GetCourseStateSchedules(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	schedules, err := h.repo.GetCourseStateSchedules(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving scheduled states")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedules})
+}
+
+// CancelCourseStateSchedule cancels a pending change of state of a course
+// @Summary Cancel a scheduled change of state
+// @Description Delete a change of state of a course that was not applied yet (owner only)
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param schedule_id path string true "Schedule ID"
+// @Success 204 "Scheduled change cancelled"
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/state/schedules/{schedule_id} [delete]
+func (h *courseHandlerImpl) CancelCourseStateSchedule(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	scheduleID, ok := h.getScheduleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.CancelCourseStateSchedule(courseID, scheduleID); err != nil {
+		if errors.Is(err, utils.ErrScheduleNotFound) {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "No pending change of state with that ID")
+		} else {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error cancelling scheduled state")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
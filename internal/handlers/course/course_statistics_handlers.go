@@ -1,6 +1,7 @@
 package course
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"templateGo/internal/model"
@@ -52,6 +53,7 @@ func (h *courseHandlerImpl) GetCoursesStatistics(c *gin.Context) {
 // @Param user_id path string true "User ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -66,10 +68,6 @@ func (h *courseHandlerImpl) GetUserStatisticsForCourse(c *gin.Context) {
 	if !ok {
 		return
 	}
-	if !h.isCourseCreatorOrAssistant(c, courseID) {
-		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "You are not authorized to access this course statistics")
-		return
-	}
 	statistics, err := h.repo.GetUserCourseStatistics(courseID, userID)
 	if err != nil {
 		statistics = model.UserCourseStatistics{
@@ -103,11 +101,6 @@ func (h *courseHandlerImpl) GetCourseStatistics(c *gin.Context) {
 		return
 	}
 
-	if !h.isCourseCreatorOrAssistant(c, courseID) {
-		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "You are not authorized to access this course statistics")
-		return
-	}
-
 	courseStatistics, err := h.repo.GetCourseStatistics(courseID)
 	if err != nil {
 		// Get course info for the response even if no statistics are available
@@ -137,24 +130,33 @@ func calculateTendencyAndAverageGrade(stats []model.StatisticsForAssignment) (st
 	if n == 0 {
 		return "stable", "stable", 0
 	}
-	// calculate the average first
+	// calculate the average first, only assignments with graded submissions have a grade
 	averageGrade := 0.0
+	xGrade := make([]float64, 0, n)
+	yGrade := make([]float64, 0, n)
 	for _, stat := range stats {
-		averageGrade += stat.AverageGrade
+		if stat.GradedCount > 0 {
+			averageGrade += stat.AverageGrade
+			xGrade = append(xGrade, float64(len(xGrade)))
+			yGrade = append(yGrade, stat.AverageGrade)
+		}
+	}
+	if len(yGrade) > 0 {
+		averageGrade /= float64(len(yGrade))
 	}
-	averageGrade /= float64(n)
 
 	x := make([]float64, n)
-	yGrade := make([]float64, n)
 	ySubmission := make([]float64, n)
 
 	for i := 0; i < n; i++ {
 		x[i] = float64(i)
-		yGrade[i] = stats[i].AverageGrade
 		ySubmission[i] = stats[i].SubmissionRate
 	}
 
-	_, slopeGrade := stat.LinearRegression(x, yGrade, nil, false)
+	slopeGrade := 0.0
+	if len(yGrade) > 1 {
+		_, slopeGrade = stat.LinearRegression(xGrade, yGrade, nil, false)
+	}
 	_, slopeSubmission := stat.LinearRegression(x, ySubmission, nil, false)
 
 	classify := func(slope float64) string {
@@ -186,6 +188,7 @@ func (h *courseHandlerImpl) CalculateAndStoreCourseStatistics(courseID uint, use
 	globalTotalAverageGrade := 0.0
 	globalTotalSubmissionRate := 0.0
 	globalAssignmentsWithGradesCount := 0.0
+	gradedSubmissionsCount := 0
 	statisticsForAssignments := make([]model.StatisticsForAssignment, 0)
 	assignments, err := h.repo.GetAssignmentsPreviews(course.ID, userID, userEmail)
 	if err != nil && err.Error() != "record not found" {
@@ -203,8 +206,9 @@ func (h *courseHandlerImpl) CalculateAndStoreCourseStatistics(courseID uint, use
 		ratedSubmissionsCount := 0.0
 		for _, submission := range submissions {
 			submissionsCount += 1
-			if submission.Grade > 0 {
-				totalGrade += float64(submission.Grade)
+			// The grade that counts follows the attempt policy of the assignment
+			if submission.FinalGrade != nil {
+				totalGrade += float64(*submission.FinalGrade)
 				ratedSubmissionsCount += 1
 			}
 		}
@@ -221,7 +225,9 @@ func (h *courseHandlerImpl) CalculateAndStoreCourseStatistics(courseID uint, use
 			Date:           assignment.CreatedAt,
 			AverageGrade:   averageGrade,
 			SubmissionRate: submissionRate,
+			GradedCount:    int(ratedSubmissionsCount),
 		})
+		gradedSubmissionsCount += int(ratedSubmissionsCount)
 		globalTotalAverageGrade += averageGrade
 		globalTotalSubmissionRate += submissionRate
 	}
@@ -239,13 +245,19 @@ func (h *courseHandlerImpl) CalculateAndStoreCourseStatistics(courseID uint, use
 	Last10AssignmentsAverageGradeTendency, Last10AssignmentsSubmissionRateTendency, Last10AssignmentsAverageGrade :=
 		calculateTendencyAndAverageGrade(last10Statistics)
 
-	suggestions, _ := h.aiAnalyzer.GenerateCourseSuggestionsBasedOnStats(Last10AssignmentsAverageGradeTendency, Last10AssignmentsSubmissionRateTendency, Last10AssignmentsAverageGrade)
+	last10GradedCount := 0
+	for _, stat := range last10Statistics {
+		last10GradedCount += stat.GradedCount
+	}
+
+	suggestions, _ := h.aiAnalyzer.GenerateCourseSuggestionsBasedOnStats(context.Background(), Last10AssignmentsAverageGradeTendency, Last10AssignmentsSubmissionRateTendency, Last10AssignmentsAverageGrade, last10GradedCount)
 
 	statistics := model.CourseStatistics{
 		CourseID:                                course.ID,
 		CourseName:                              course.Title,
 		GlobalAverageGrade:                      globalTotalAverageGrade,
 		GlobalSubmissionRate:                    globalTotalSubmissionRate,
+		GradedSubmissionsCount:                  gradedSubmissionsCount,
 		Last10AssignmentsAverageGradeTendency:   Last10AssignmentsAverageGradeTendency,
 		Last10AssignmentsSubmissionRateTendency: Last10AssignmentsSubmissionRateTendency,
 		Suggestions:                             suggestions,
@@ -282,14 +294,21 @@ func (h *courseHandlerImpl) CalculateAndStoreUserCourseStatistics(courseID uint,
 			continue
 		}
 		totalSubmissionsCount += 1
-		if submission.Grade > 0 {
-			totalGrades += float64(submission.Grade)
-			totalRatedSubmissionsCount += 1
+		if submission.FinalGrade == nil {
+			statisticsForDates = append(statisticsForDates, model.StatisticsForAssignment{
+				Date:           assignment.CreatedAt,
+				AverageGrade:   0.0,
+				SubmissionRate: 1.0,
+			})
+			continue
 		}
+		totalGrades += float64(*submission.FinalGrade)
+		totalRatedSubmissionsCount += 1
 		statisticsForDates = append(statisticsForDates, model.StatisticsForAssignment{
 			Date:           assignment.CreatedAt,
-			AverageGrade:   float64(submission.Grade),
+			AverageGrade:   float64(*submission.FinalGrade),
 			SubmissionRate: 1.0,
+			GradedCount:    1,
 		})
 	}
 	averageGrade := 0.0
@@ -339,6 +358,7 @@ func (h *courseHandlerImpl) CalculateAndStoreGlobalStatistics(teacherEmail strin
 	var totalAverageGrade float64
 	var totalSubmissionRate float64
 	validCourses := 0
+	gradedCourses := 0
 
 	// Calculate averages across all courses
 	for _, course := range courses {
@@ -347,7 +367,11 @@ func (h *courseHandlerImpl) CalculateAndStoreGlobalStatistics(teacherEmail strin
 			continue // Skip courses without statistics
 		}
 
-		totalAverageGrade += courseStats.GlobalAverageGrade
+		// Courses without graded submissions have no average grade
+		if courseStats.GradedSubmissionsCount > 0 {
+			totalAverageGrade += courseStats.GlobalAverageGrade
+			gradedCourses++
+		}
 		totalSubmissionRate += courseStats.GlobalSubmissionRate
 		validCourses++
 	}
@@ -357,7 +381,10 @@ func (h *courseHandlerImpl) CalculateAndStoreGlobalStatistics(teacherEmail strin
 	}
 
 	// Calculate global averages (average of averages)
-	globalAverageGrade := totalAverageGrade / float64(validCourses)
+	globalAverageGrade := 0.0
+	if gradedCourses > 0 {
+		globalAverageGrade = totalAverageGrade / float64(gradedCourses)
+	}
 	globalSubmissionRate := totalSubmissionRate / float64(validCourses)
 
 	// Create global statistics
@@ -391,7 +418,8 @@ func (h *courseHandlerImpl) getAllTeachersForCourse(courseID uint) ([]string, er
 	return teachers, nil
 }
 
-// enqueueGlobalStatisticsForAllTeachers enqueues global statistics calculation for all teachers of a course
+// enqueueGlobalStatisticsForAllTeachers enqueues global statistics calculation for all teachers of a course,
+// to run once the statistics of the course are up to date
 func (h *courseHandlerImpl) enqueueGlobalStatisticsForAllTeachers(courseID uint) {
 	teachers, err := h.getAllTeachersForCourse(courseID)
 	if err != nil {
@@ -399,6 +427,6 @@ func (h *courseHandlerImpl) enqueueGlobalStatisticsForAllTeachers(courseID uint)
 	}
 
 	for _, teacherEmail := range teachers {
-		h.statisticsService.EnqueueGlobalStatisticsCalculation(teacherEmail)
+		h.statisticsService.EnqueueGlobalStatisticsCalculation(teacherEmail, courseID)
 	}
 }
@@ -1,6 +1,8 @@
 package course
 
 import (
+	"errors"
+	"io"
 	"log"
 	"net/http"
 	"templateGo/internal/model"
@@ -12,17 +14,23 @@ import (
 
 // PutSubmissionOfCurrentUser creates or updates a submission
 // @Summary Submit or update current user's assignment submission
-// @Description Submit or update the current user's submission for an assignment
+// @Description Submit a new attempt of the current user's submission for an assignment, up to the maximum attempts of the assignment. Previous attempts are kept in the submission history. Submissions after the deadline and its grace period are rejected or flagged as late following the late policy of the assignment, and submissions of timed assignments are rejected once the time limit since the student opened the assignment is over.
+// @Description Files can be sent as multipart/form-data, with the submission JSON in a data part and each file in a files part, instead of inline in the JSON. Files must be of the types allowed by the assignment and within its size limit, every rejected file is listed in the invalid_params of the error.
 // @Tags submissions
 // @Accept json
+// @Accept mpfd
 // @Produce json
 // @Param course_id path string true "Course ID"
 // @Param assignment_id path string true "Assignment ID"
 // @Param submission body model.SubmissionRequest true "Submission content"
+// @Param data formData string false "Submission content as JSON, for multipart uploads"
+// @Param files formData file false "Files of the submission, for multipart uploads"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 413 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /{course_id}/assignment/{assignment_id}/submission [put]
@@ -48,35 +56,79 @@ func (h *courseHandlerImpl) PutSubmissionOfCurrentUser(c *gin.Context) {
 	if !ok {
 		return
 	}
-	var req model.CreateSubmissionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+	assignment, ok := h.getAssignmentByID(c, assignmentID)
+	if !ok {
 		return
 	}
+	// Check if assignment belongs to the course, submissions are later scoped by both
+	if assignment.CourseID != courseID {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "Assignment does not belong to this course")
+		return
+	}
+	now := time.Now()
+	if assignment.TimeLimit > 0 {
+		session, err := h.repo.GetOrCreateAssignmentSession(userID, assignmentID)
+		if err != nil {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error tracking assignment session")
+			return
+		}
+		if assignment.SessionExpired(session.StartedAt, now) {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "The time limit of the assignment has been exceeded")
+			return
+		}
+	}
+
 	submission := &model.Submission{
 		CourseID:     courseID,
 		AssignmentID: assignmentID,
 		UserID:       userID,
-		Content:      req.Content,
-		Files:        req.Files,
+		Status:       model.SubmissionStatusSubmitted,
+	}
+	submission.MarkSubmitted(assignment, now)
+	if submission.Late && assignment.LatePolicy == model.LatePolicyReject {
+		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "The deadline of the assignment has passed")
+		return
 	}
 
-	if err := h.repo.PutSubmission(submission); err != nil {
+	// The request is read once the submission is known to be accepted, so no file is stored otherwise
+	var req model.CreateSubmissionRequest
+	uploads, ok := h.bindUpload(c, &req, assignment)
+	if !ok {
+		return
+	}
+	if !h.storeSubmissionFiles(c, req.Files, assignment) {
+		return
+	}
+	submission.Content = req.Content
+	submission.Files = req.Files
+	for _, file := range uploads {
+		submission.Files = append(submission.Files, model.SubmissionFile{Name: file.Name, FileContent: file.FileContent})
+	}
+
+	if err := h.repo.PutSubmission(submission, assignment); err != nil {
+		if errors.Is(err, utils.ErrMaxAttemptsReached) {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "No attempts left for the assignment")
+			return
+		}
 		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error creating submission")
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Submission created/updated successfully"})
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "Submission created/updated successfully",
+		"late":          submission.Late,
+		"attempt":       submission.Attempt,
+		"attempts_left": assignment.AttemptsLeft(submission.Attempt),
+	})
 
 	// Enqueue statistics calculation tasks
 	h.statisticsService.EnqueueCourseStatisticsCalculation(courseID, userID, userEmail)
 	h.statisticsService.EnqueueUserCourseStatisticsCalculation(courseID, userID, userEmail)
 
-	// Enqueue global statistics calculation with a delay to ensure it runs after course/user stats
-	go func() {
-		time.Sleep(3 * time.Second) // Wait for course and user statistics to complete
-		h.enqueueGlobalStatisticsForAllTeachers(courseID)
-	}()
+	// Global statistics depend on the course statistics, the queue runs them afterwards
+	h.enqueueGlobalStatisticsForAllTeachers(courseID)
+
+	h.statisticsService.EnqueueSubmissionSimilarityCheck(assignmentID)
 }
 
 // DeleteSubmissionOfCurrentUser removes a user's submission
@@ -89,6 +141,7 @@ func (h *courseHandlerImpl) PutSubmissionOfCurrentUser(c *gin.Context) {
 // @Param assignment_id path string true "Assignment ID"
 // @Success 204 "Submission deleted successfully"
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -143,11 +196,8 @@ func (h *courseHandlerImpl) DeleteSubmissionOfCurrentUser(c *gin.Context) {
 	h.statisticsService.EnqueueCourseStatisticsCalculation(courseID, userID, userEmail)
 	h.statisticsService.EnqueueUserCourseStatisticsCalculation(courseID, userID, userEmail)
 
-	// Enqueue global statistics calculation with a delay to ensure it runs after course/user stats
-	go func() {
-		time.Sleep(3 * time.Second) // Wait for course and user statistics to complete
-		h.enqueueGlobalStatisticsForAllTeachers(courseID)
-	}()
+	// Global statistics depend on the course statistics, the queue runs them afterwards
+	h.enqueueGlobalStatisticsForAllTeachers(courseID)
 }
 
 // GetSubmissionOfCurrentUser returns the current user's submission
@@ -160,6 +210,7 @@ func (h *courseHandlerImpl) DeleteSubmissionOfCurrentUser(c *gin.Context) {
 // @Param assignment_id path string true "Assignment ID"
 // @Success 200 {object} model.SuccessResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -210,14 +261,24 @@ func (h *courseHandlerImpl) GetSubmissionByUserID(c *gin.Context) {
 
 // GetSubmissions returns all submissions for an assignment
 // @Summary Get all submissions for an assignment
-// @Description Retrieve all submissions for a specific assignment (teacher only)
+// @Description Retrieve a page of the submissions for a specific assignment (teacher only)
 // @Tags submissions
 // @Accept json
 // @Produce json
 // @Param course_id path string true "Course ID"
 // @Param assignment_id path string true "Assignment ID"
-// @Success 200 {object} model.SuccessResponse
+// @Param cursor query string false "Cursor of the page, from next_cursor"
+// @Param limit query int false "Submissions per page, 20 by default" minimum(1) maximum(100)
+// @Param sort query string false "Sort key, prefixed with - for descending order" Enums(id, -id, submitted_at, -submitted_at, grade, -grade, user_id, -user_id)
+// @Param graded query bool false "Only graded or ungraded submissions"
+// @Param late query bool false "Only late or on time submissions"
+// @Param status query string false "Only submissions in this status" Enums(submitted, graded, returned, regraded)
+// @Param submitted_from query string false "Submitted at or after, RFC 3339" format(date-time)
+// @Param submitted_to query string false "Submitted at or before, RFC 3339" format(date-time)
+// @Success 200 {object} model.PageResponse{data=[]model.Submission}
+// @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -227,25 +288,26 @@ func (h *courseHandlerImpl) GetSubmissions(c *gin.Context) {
 	if !ok {
 		return
 	}
-	if !h.isCourseCreatorOrAssistant(c, courseID) {
-		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", "You do not have permission to access this resource")
+	assignmentID, ok := h.getAssignmentID(c)
+	if !ok {
 		return
 	}
-	assignmentID, ok := h.getAssignmentID(c)
+	var filter model.SubmissionFilter
+	page, ok := h.bindListQuery(c, &filter)
 	if !ok {
 		return
 	}
-	submissions, err := h.repo.GetSubmissions(courseID, assignmentID)
+	submissions, err := h.repo.ListSubmissions(courseID, assignmentID, filter, page)
 	if err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving submissions")
+		h.listError(c, err, "Error retrieving submissions")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": submissions})
+	c.JSON(http.StatusOK, gin.H{"data": submissions.Items, "next_cursor": submissions.NextCursor})
 }
 
 // GradeSubmission allows grading a submission with feedback
 // @Summary Grade and provide feedback on a submission
-// @Description Grade a student's submission and provide feedback
+// @Description Grade a student's submission and provide feedback. Late submissions lose the penalty of the assignment late policy.
 // @Tags submissions
 // @Accept json
 // @Produce json
@@ -256,6 +318,7 @@ func (h *courseHandlerImpl) GetSubmissions(c *gin.Context) {
 // @Success 204 "Submission graded successfully"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -273,13 +336,12 @@ func (h *courseHandlerImpl) GradeSubmission(c *gin.Context) {
 	if !ok {
 		return
 	}
-	submissionID, ok := h.getSubmissionID(c)
+	submission, ok := h.getCourseSubmission(c)
 	if !ok {
 		return
 	}
-	submission, err := h.repo.GetSubmission(submissionID)
-	if err != nil {
-		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Submission not found")
+	assignment, ok := h.getAssignmentByID(c, submission.AssignmentID)
+	if !ok {
 		return
 	}
 	studentID := submission.UserID
@@ -288,10 +350,14 @@ func (h *courseHandlerImpl) GradeSubmission(c *gin.Context) {
 		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
 		return
 	}
-	submission.Grade = req.Grade
+	submission.ApplyGrade(req.Grade, assignment)
 	submission.Feedback = req.Feedback
+	// The grade no longer comes from a rubric
+	submission.RubricID = nil
+	submission.Scores = nil
+	submission.MarkGraded(userEmail, time.Now())
 
-	if err := h.repo.PutSubmission(submission); err != nil {
+	if err := h.repo.SaveSubmissionGrade(submission); err != nil {
 		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error grading submission")
 		return
 	}
@@ -302,50 +368,180 @@ func (h *courseHandlerImpl) GradeSubmission(c *gin.Context) {
 	h.statisticsService.EnqueueCourseStatisticsCalculation(courseID, userID, userEmail)
 	h.statisticsService.EnqueueUserCourseStatisticsCalculation(courseID, studentID, userEmail)
 
-	// Enqueue global statistics calculation with a delay to ensure it runs after course/user stats
-	go func() {
-		time.Sleep(3 * time.Second) // Wait for course and user statistics to complete
-		h.enqueueGlobalStatisticsForAllTeachers(courseID)
-	}()
+	// Global statistics depend on the course statistics, the queue runs them afterwards
+	h.enqueueGlobalStatisticsForAllTeachers(courseID)
+}
+
+// GradeSubmissionWithRubric grades a submission criterion by criterion
+// @Summary Grade a submission with the rubric of its assignment
+// @Description Score every criterion of the assignment rubric. The grade of the submission is the weighted total of the scores, from 0 to 100, minus the late penalty of the assignment.
+// @Tags submissions
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Param submission_id path string true "Submission ID"
+// @Param grade body model.RubricGradeSubmissionRequest true "Criterion scores and feedback"
+// @Success 200 {object} model.SuccessResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/submission/{submission_id}/rubric-grade [post]
+func (h *courseHandlerImpl) GradeSubmissionWithRubric(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+	userEmail, ok := h.getUserEmailFromToken(c)
+	if !ok {
+		return
+	}
+	assignment, ok := h.getCourseAssignment(c)
+	if !ok {
+		return
+	}
+	submission, ok := h.getCourseSubmission(c)
+	if !ok {
+		return
+	}
+	if assignment.Rubric == nil {
+		utils.NewErrorResponse(c, http.StatusConflict, "Conflict", "The assignment has no rubric")
+		return
+	}
+
+	var req model.RubricGradeSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+	if err := assignment.Rubric.ValidateScores(req.Criteria); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	submission.ApplyGrade(assignment.Rubric.Total(req.Criteria), assignment)
+	submission.Feedback = req.Feedback
+	submission.RubricID = &assignment.Rubric.ID
+	submission.Scores = req.Criteria
+	submission.MarkGraded(userEmail, time.Now())
+
+	if err := h.repo.SaveSubmissionGrade(submission); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error grading submission")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"grade":        submission.Grade,
+		"late_penalty": submission.LatePenalty,
+		"feedback":     submission.Feedback,
+		"rubric_id":    submission.RubricID,
+		"criteria":     submission.Scores,
+		"status":       submission.Status,
+	}})
+
+	// Enqueue statistics calculation tasks
+	h.statisticsService.EnqueueCourseStatisticsCalculation(courseID, userID, userEmail)
+	h.statisticsService.EnqueueUserCourseStatisticsCalculation(courseID, submission.UserID, userEmail)
+
+	// Global statistics depend on the course statistics, the queue runs them afterwards
+	h.enqueueGlobalStatisticsForAllTeachers(courseID)
+}
+
+// ReturnSubmission returns a graded submission to its student
+// @Summary Return a graded submission to the student
+// @Description Mark a graded submission as returned, meaning its grade and feedback were handed back to the student
+// @Tags submissions
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Param submission_id path string true "Submission ID"
+// @Success 200 {object} model.SuccessResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/submission/{submission_id}/return [post]
+func (h *courseHandlerImpl) ReturnSubmission(c *gin.Context) {
+	submission, ok := h.getCourseSubmission(c)
+	if !ok {
+		return
+	}
+	if !submission.IsGraded() {
+		utils.NewErrorResponse(c, http.StatusConflict, "Conflict", "Only graded submissions can be returned")
+		return
+	}
+
+	submission.Status = model.SubmissionStatusReturned
+	if err := h.repo.SaveSubmissionGrade(submission); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error returning submission")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Submission returned successfully"})
 }
 
 // GetAIGeneratedGrade retrieves AI-generated grade for a submission
 // @Summary Get AI generated grade and feedback for a submission
-// @Description Get AI-powered grade and feedback suggestions for a submission
+// @Description Get the AI grade draft of a submission, generating it when there is none. When the assignment has a rubric the draft contains a score and justification per criterion and the grade is the weighted total. The draft does not change the submission until a teacher accepts it.
 // @Tags submissions
 // @Accept json
 // @Produce json
 // @Param course_id path string true "Course ID"
 // @Param assignment_id path string true "Assignment ID"
 // @Param submission_id path string true "Submission ID"
-// @Success 200 {object} model.SuccessResponse
+// @Param regenerate query bool false "Discard the stored draft and generate a new one"
+// @Success 200 {object} model.SuccessResponse{data=model.GradeDraft}
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
 // @Router /{course_id}/assignment/{assignment_id}/submission/{submission_id}/ai-grade [get]
 func (h *courseHandlerImpl) GetAIGeneratedGradeAndFeedback(c *gin.Context) {
-	submissionID, ok := h.getSubmissionID(c)
+	assignment, ok := h.getCourseAssignment(c)
 	if !ok {
 		return
 	}
-	submission, err := h.repo.GetSubmission(submissionID)
-	if err != nil {
-		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Submission not found")
+	submission, ok := h.getCourseSubmission(c)
+	if !ok {
 		return
 	}
-	assignmentID, ok := h.getAssignmentID(c)
-	if !ok {
+
+	if c.Query("regenerate") != "true" {
+		draft, err := h.repo.GetGradeDraft(submission.ID)
+		if err == nil {
+			c.JSON(http.StatusOK, gin.H{"data": draft})
+			return
+		}
+		if !errors.Is(err, utils.ErrGradeDraftNotFound) {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving AI grade draft")
+			return
+		}
+	}
+
+	if err := h.loadFileContents(c.Request.Context(), submission.Files); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error reading submission files")
 		return
 	}
-	// Check if assignment exists
-	assignment, ok := h.getAssignmentByID(c, assignmentID)
-	if !ok {
-		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Assignment not found")
+
+	draft, err := h.aiAnalyzer.GenerateGradeDraft(c.Request.Context(), assignment.Description, assignment.Rubric, submission.Content, submission.Files)
+	if errors.Is(err, utils.ErrSubmissionTooLarge) {
+		utils.NewErrorResponse(c, http.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
 		return
 	}
-	// Simulate AI-generated grade retrieval
-	aiGrade, aiFeedback, err := h.aiAnalyzer.GenerateGradeAndFeedback(assignment.Description, submission.Files)
 	if err != nil {
 		// print it to the console for debugging
 		log.Printf("Error generating AI grade/feedback: %v", err)
@@ -354,8 +550,133 @@ func (h *courseHandlerImpl) GetAIGeneratedGradeAndFeedback(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": gin.H{
-		"grade":    aiGrade,
-		"feedback": aiFeedback,
-	}})
+	draft.SubmissionID = submission.ID
+	if err := h.repo.SaveGradeDraft(draft); err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error saving AI grade draft")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": draft})
+}
+
+// AcceptAIGradeDraft applies the AI grade draft of a submission, optionally edited by the teacher
+// @Summary Accept the AI grade draft of a submission
+// @Description Grade a submission with its AI grade draft. The teacher may edit the feedback and the score of any rubric criterion, in which case the grade is recomputed from the rubric, or the grade itself for assignments without rubric.
+// @Tags submissions
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Param assignment_id path string true "Assignment ID"
+// @Param submission_id path string true "Submission ID"
+// @Param edits body model.AcceptGradeDraftRequest false "Edits to the draft"
+// @Success 200 {object} model.SuccessResponse{data=model.GradeDraft}
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/assignment/{assignment_id}/submission/{submission_id}/ai-grade/accept [post]
+func (h *courseHandlerImpl) AcceptAIGradeDraft(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+	userEmail, ok := h.getUserEmailFromToken(c)
+	if !ok {
+		return
+	}
+	submission, ok := h.getCourseSubmission(c)
+	if !ok {
+		return
+	}
+
+	var req model.AcceptGradeDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return
+	}
+
+	draft, err := h.repo.GetGradeDraft(submission.ID)
+	if err != nil {
+		if errors.Is(err, utils.ErrGradeDraftNotFound) {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "The submission has no AI grade draft")
+		} else {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving AI grade draft")
+		}
+		return
+	}
+
+	if draft.RubricID != nil {
+		rubric, err := h.repo.GetRubricByID(*draft.RubricID)
+		if err != nil {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving rubric")
+			return
+		}
+		if req.Grade != nil {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", "The grade is computed from the rubric, edit the criteria scores instead")
+			return
+		}
+		draft.Scores = mergeCriterionScores(draft.Scores, req.Criteria)
+		if err := rubric.ValidateScores(draft.Scores); err != nil {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+			return
+		}
+		draft.Grade = rubric.Total(draft.Scores)
+	} else {
+		if len(req.Criteria) > 0 {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", "The assignment has no rubric to score criteria")
+			return
+		}
+		if req.Grade != nil {
+			draft.Grade = *req.Grade
+		}
+	}
+	if req.Feedback != nil {
+		draft.Feedback = *req.Feedback
+	}
+	draft.AcceptedBy = userEmail
+
+	if err := h.repo.AcceptGradeDraft(draft); err != nil {
+		if errors.Is(err, utils.ErrGradeDraftAccepted) {
+			utils.NewErrorResponse(c, http.StatusConflict, "Conflict", "The AI grade draft was already accepted")
+		} else {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error grading submission")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": draft})
+
+	// Enqueue statistics calculation tasks
+	h.statisticsService.EnqueueCourseStatisticsCalculation(courseID, userID, userEmail)
+	h.statisticsService.EnqueueUserCourseStatisticsCalculation(courseID, submission.UserID, userEmail)
+
+	// Global statistics depend on the course statistics, the queue runs them afterwards
+	h.enqueueGlobalStatisticsForAllTeachers(courseID)
+}
+
+// mergeCriterionScores replaces the scores of the edited criteria, keeping the others.
+// Edits of criteria that are not scored yet are appended so that validation reports them.
+func mergeCriterionScores(scores []model.CriterionScore, edits []model.CriterionScore) []model.CriterionScore {
+	merged := append([]model.CriterionScore{}, scores...)
+	for _, edit := range edits {
+		found := false
+		for i := range merged {
+			if merged[i].Criterion == edit.Criterion {
+				merged[i] = edit
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, edit)
+		}
+	}
+	return merged
 }
@@ -0,0 +1,86 @@
+package course
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"templateGo/internal/model"
+	"templateGo/internal/queue"
+	"templateGo/internal/repositories"
+	"templateGo/internal/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeGradeDraftRepository keeps a submission and its AI grade draft in memory, accepting the draft the way
+// the repository does
+type fakeGradeDraftRepository struct {
+	repositories.CourseRepository
+	submission *model.Submission
+	draft      *model.GradeDraft
+	graded     int
+}
+
+func (f *fakeGradeDraftRepository) GetByID(id uint) (*model.Course, error) {
+	course := &model.Course{CreatedBy: "teacher-a@example.com"}
+	course.ID = id
+	return course, nil
+}
+
+func (f *fakeGradeDraftRepository) GetSubmission(submissionID uint) (*model.Submission, error) {
+	if submissionID != f.submission.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.submission, nil
+}
+
+func (f *fakeGradeDraftRepository) GetGradeDraft(submissionID uint) (*model.GradeDraft, error) {
+	if submissionID != f.draft.SubmissionID {
+		return nil, utils.ErrGradeDraftNotFound
+	}
+	draft := *f.draft
+	return &draft, nil
+}
+
+func (f *fakeGradeDraftRepository) AcceptGradeDraft(draft *model.GradeDraft) error {
+	if f.draft.Status != model.GradeDraftStatusDraft {
+		return utils.ErrGradeDraftAccepted
+	}
+	draft.Status = model.GradeDraftStatusAccepted
+	*f.draft = *draft
+	f.graded++
+	return nil
+}
+
+func TestAcceptAIGradeDraft_OnlyOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &fakeGradeDraftRepository{
+		submission: &model.Submission{ID: 10, CourseID: 1, AssignmentID: 5, UserID: "student-a"},
+		draft:      &model.GradeDraft{ID: 3, SubmissionID: 10, Grade: 80, Status: model.GradeDraftStatusDraft},
+	}
+	// Not started, so the statistics tasks enqueued after grading are dropped
+	h := &courseHandlerImpl{repo: repo, statisticsService: queue.NewStatisticsService(repo, nil, nil)}
+
+	router := gin.New()
+	router.POST("/:course_id/assignment/:assignment_id/submission/:submission_id/ai-grade/accept", func(c *gin.Context) {
+		c.Set("user_id", "teacher-a")
+		c.Set("user_email", "teacher-a@example.com")
+		h.AcceptAIGradeDraft(c)
+	})
+
+	accept := func() int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/1/assignment/5/submission/10/ai-grade/accept", strings.NewReader(`{"grade": 90}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, accept())
+	assert.Equal(t, http.StatusConflict, accept())
+	assert.Equal(t, 1, repo.graded)
+	assert.Equal(t, uint(90), repo.draft.Grade)
+}
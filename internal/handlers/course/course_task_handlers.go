@@ -0,0 +1,90 @@
+package course
+
+import (
+	"errors"
+	"net/http"
+	"templateGo/internal/queue"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFailedStatisticsTasks lists the statistics tasks that exhausted their retries
+// @Summary List failed statistics tasks
+// @Description Retrieve the statistics calculation tasks moved to the dead-letter state after exhausting their retries (admin only)
+// @Tags statistics
+// @Accept json
+// @Produce json
+// @Param type query string false "Task type (course_statistics, user_course_statistics or global_statistics)"
+// @Success 200 {object} model.SuccessResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure 501 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /statistics/tasks/failed [get]
+func (h *courseHandlerImpl) GetFailedStatisticsTasks(c *gin.Context) {
+	var types []queue.TaskType
+	if taskType := c.Query("type"); taskType != "" {
+		if !isStatisticsTaskType(queue.TaskType(taskType)) {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "Unknown statistics task type")
+			return
+		}
+		types = append(types, queue.TaskType(taskType))
+	}
+
+	tasks, err := h.statisticsService.ListFailedTasks(types...)
+	if err != nil {
+		if errors.Is(err, utils.ErrDeadLetterDisabled) {
+			utils.NewErrorResponse(c, http.StatusNotImplemented, "Not Implemented", "The task queue does not keep failed tasks")
+		} else {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving failed tasks")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tasks})
+}
+
+// RequeueFailedStatisticsTask schedules a failed statistics task to run again
+// @Summary Requeue a failed statistics task
+// @Description Move a statistics task out of the dead-letter state so that it is retried with a fresh retry budget (admin only)
+// @Tags statistics
+// @Accept json
+// @Produce json
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} model.SuccessResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure 501 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /statistics/tasks/{task_id}/requeue [post]
+func (h *courseHandlerImpl) RequeueFailedStatisticsTask(c *gin.Context) {
+	taskID := c.Param("task_id")
+
+	if err := h.statisticsService.RequeueFailedTask(taskID); err != nil {
+		switch {
+		case errors.Is(err, utils.ErrTaskNotFound):
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Failed task not found")
+		case errors.Is(err, utils.ErrDeadLetterDisabled):
+			utils.NewErrorResponse(c, http.StatusNotImplemented, "Not Implemented", "The task queue does not keep failed tasks")
+		default:
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error requeueing task")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task requeued successfully"})
+}
+
+func isStatisticsTaskType(taskType queue.TaskType) bool {
+	for _, statisticsType := range queue.StatisticsTaskTypes {
+		if statisticsType == taskType {
+			return true
+		}
+	}
+	return false
+}
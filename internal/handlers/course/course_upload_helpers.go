@@ -0,0 +1,246 @@
+package course
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"templateGo/internal/model"
+	"templateGo/internal/storage"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Default size limits of uploads in bytes, overridden by UPLOAD_MAX_FILE_SIZE and UPLOAD_MAX_REQUEST_SIZE
+const (
+	defaultMaxFileSize    = 50 << 20
+	defaultMaxRequestSize = 200 << 20
+)
+
+// maxDataPartSize bounds the JSON request sent in the data part of a multipart upload
+const maxDataPartSize = 1 << 20
+
+// sniffLen is the number of bytes http.DetectContentType looks at
+const sniffLen = 512
+
+// Parts of a multipart upload
+const (
+	uploadDataPart  = "data"
+	uploadFilesPart = "files"
+)
+
+// uploadLimits bound the size of upload requests and of each of their files
+type uploadLimits struct {
+	maxFileSize    int64
+	maxRequestSize int64
+}
+
+func uploadLimitsFromEnv() uploadLimits {
+	return uploadLimits{
+		maxFileSize:    sizeFromEnv("UPLOAD_MAX_FILE_SIZE", defaultMaxFileSize),
+		maxRequestSize: sizeFromEnv("UPLOAD_MAX_REQUEST_SIZE", defaultMaxRequestSize),
+	}
+}
+
+func sizeFromEnv(key string, fallback int64) int64 {
+	size, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil || size <= 0 {
+		return fallback
+	}
+	return size
+}
+
+// uploadedFile is a file of a multipart upload, already in the blob store
+type uploadedFile struct {
+	Name string
+	model.FileContent
+}
+
+// bindUpload binds the request of an upload, sent either as JSON or as multipart/form-data with the JSON
+// in a data part and each file in a files part. Files of multipart uploads are streamed to the blob store
+// as they are read and returned. The files must be accepted by the assignment, an empty one accepts any file.
+func (h *courseHandlerImpl) bindUpload(c *gin.Context, req any, assignment *model.Assignment) ([]uploadedFile, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.uploads.maxRequestSize)
+
+	if c.ContentType() != binding.MIMEMultipartPOSTForm {
+		if err := c.ShouldBindJSON(req); err != nil {
+			if !h.requestTooLarge(c, err) {
+				utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+			}
+			return nil, false
+		}
+		return nil, true
+	}
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", "Invalid multipart body")
+		return nil, false
+	}
+
+	var files []uploadedFile
+	var invalid []utils.InvalidParam
+	hasData := false
+	for index := 0; ; {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if !h.requestTooLarge(c, err) {
+				utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", "Invalid multipart body")
+			}
+			return nil, false
+		}
+
+		switch part.FormName() {
+		case uploadDataPart:
+			if err := json.NewDecoder(io.LimitReader(part, maxDataPartSize)).Decode(req); err != nil {
+				utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", fmt.Sprintf("Invalid data part: %s", err))
+				return nil, false
+			}
+			hasData = true
+		case uploadFilesPart:
+			param := utils.InvalidParam{Name: fmt.Sprintf("%s[%d]", uploadFilesPart, index), File: part.FileName()}
+			index++
+			file, reason, err := h.storeUploadedFile(c, part, assignment)
+			if err != nil {
+				if !h.requestTooLarge(c, err) {
+					utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error storing file")
+				}
+				return nil, false
+			}
+			if reason != "" {
+				param.Reason = reason
+				invalid = append(invalid, param)
+				break
+			}
+			files = append(files, file)
+		}
+		// Skips what is left of rejected and unknown parts
+		if _, err := io.Copy(io.Discard, part); err != nil {
+			if !h.requestTooLarge(c, err) {
+				utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", "Invalid multipart body")
+			}
+			return nil, false
+		}
+	}
+
+	if !hasData {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", "The request must be sent in the data part")
+		return nil, false
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation Error", err.Error())
+		return nil, false
+	}
+	if len(invalid) > 0 {
+		utils.NewInvalidParamsResponse(c, http.StatusBadRequest, "Validation Error", "Some files were rejected", invalid)
+		return nil, false
+	}
+	return files, true
+}
+
+// storeUploadedFile streams a file part to the blob store. Files the assignment does not accept
+// are not stored and the reason is returned instead.
+func (h *courseHandlerImpl) storeUploadedFile(c *gin.Context, part *multipart.Part, assignment *model.Assignment) (uploadedFile, string, error) {
+	name := part.FileName()
+	if name == "" {
+		return uploadedFile{}, "The file has no name", nil
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(part, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return uploadedFile{}, "", err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if reason := h.checkFileType(name, contentType, assignment); reason != "" {
+		return uploadedFile{}, reason, nil
+	}
+
+	maxSize := assignment.FileSizeLimit(h.uploads.maxFileSize)
+	digest, size, err := storage.Save(c.Request.Context(), h.blobs, io.MultiReader(bytes.NewReader(head), part), maxSize)
+	if errors.Is(err, utils.ErrFileTooLarge) {
+		return uploadedFile{}, fileTooLarge(maxSize), nil
+	}
+	if err != nil {
+		return uploadedFile{}, "", err
+	}
+
+	return uploadedFile{
+		Name:        name,
+		FileContent: model.FileContent{SHA256: digest, ContentType: contentType, Size: size},
+	}, "", nil
+}
+
+// storeSubmissionFiles checks the files sent in the JSON of a submission against the assignment,
+// reporting every rejected file, and stores their content
+func (h *courseHandlerImpl) storeSubmissionFiles(c *gin.Context, files []model.SubmissionFile, assignment *model.Assignment) bool {
+	maxSize := assignment.FileSizeLimit(h.uploads.maxFileSize)
+
+	var invalid []utils.InvalidParam
+	for i, file := range files {
+		reason := ""
+		switch {
+		case file.Name == "":
+			reason = "The file has no name"
+		case int64(len(file.Content)) > maxSize:
+			reason = fileTooLarge(maxSize)
+		default:
+			reason = h.checkFileType(file.Name, http.DetectContentType(file.Content), assignment)
+		}
+		if reason != "" {
+			invalid = append(invalid, utils.InvalidParam{Name: fmt.Sprintf("%s[%d]", uploadFilesPart, i), File: file.Name, Reason: reason})
+		}
+	}
+	if len(invalid) > 0 {
+		utils.NewInvalidParamsResponse(c, http.StatusBadRequest, "Validation Error", "Some files were rejected", invalid)
+		return false
+	}
+
+	for i := range files {
+		files[i].ID = 0
+		if !h.storeFileContent(c, &files[i].FileContent) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkFileType returns why the assignment does not accept a file, or an empty string
+func (h *courseHandlerImpl) checkFileType(name, contentType string, assignment *model.Assignment) string {
+	if assignment.AllowsFile(name, contentType) {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	return fmt.Sprintf("%s files are not allowed", mediaType)
+}
+
+// requestTooLarge answers the request when err comes from reading past the size limit of uploads
+func (h *courseHandlerImpl) requestTooLarge(c *gin.Context, err error) bool {
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		return false
+	}
+	utils.NewErrorResponse(c, http.StatusRequestEntityTooLarge, "Request Too Large",
+		fmt.Sprintf("The request is larger than %d bytes", tooLarge.Limit))
+	return true
+}
+
+func fileTooLarge(maxSize int64) string {
+	return fmt.Sprintf("The file is larger than %d bytes", maxSize)
+}
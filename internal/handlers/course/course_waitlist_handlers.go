@@ -0,0 +1,199 @@
+package course
+
+import (
+	"errors"
+	"net/http"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const WAITLIST_PROMOTION_NOTIFICATION = "waitlist_promotion"
+
+// JoinWaitlist adds the current user to the waitlist of a full course
+// @Summary Join the waitlist of a full course
+// @Description Add the authenticated user to the waitlist of a course that reached its capacity. Waitlisted users are enrolled automatically, in arrival order, when a seat is freed while the course is open for enrollment.
+// @Tags enrollments
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 201 {object} model.SuccessResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/waitlist [post]
+func (h *courseHandlerImpl) JoinWaitlist(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	position, err := h.repo.JoinWaitlist(courseID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrCourseNotFound):
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Course not found")
+		case errors.Is(err, utils.ErrUserAlreadyEnrolled):
+			utils.NewErrorResponse(c, http.StatusConflict, "Conflict", "User is already enrolled in this course")
+		case errors.Is(err, utils.ErrPrerequisitesNotMet):
+			h.prerequisitesNotMet(c, courseID)
+		case errors.Is(err, utils.ErrEnrollmentClosed):
+			h.enrollmentClosed(c)
+		case errors.Is(err, utils.ErrCourseHasSeats):
+			utils.NewErrorResponse(c, http.StatusConflict, "Conflict", "Course still has available seats, enroll instead")
+		case errors.Is(err, utils.ErrAlreadyWaitlisted):
+			utils.NewErrorResponse(c, http.StatusConflict, "Conflict", "User is already in the waitlist of this course")
+		default:
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error joining the waitlist")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Successfully joined the waitlist", "position": position})
+}
+
+// LeaveWaitlist removes the current user from the waitlist of a course
+// @Summary Leave the waitlist of a course
+// @Description Remove the authenticated user from the waitlist of the specified course
+// @Tags enrollments
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/waitlist [delete]
+func (h *courseHandlerImpl) LeaveWaitlist(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.LeaveWaitlist(courseID, userID); err != nil {
+		if errors.Is(err, utils.ErrNotWaitlisted) {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "User is not in the waitlist of this course")
+		} else {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error leaving the waitlist")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully left the waitlist"})
+}
+
+// GetWaitlistPosition returns the position of the current user in the waitlist
+// @Summary Get current user's position in the waitlist
+// @Description Retrieve the 1-based position of the authenticated user in the waitlist of a course, along with the waitlist size
+// @Tags enrollments
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/waitlist/position [get]
+func (h *courseHandlerImpl) GetWaitlistPosition(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := h.getUserIDFromToken(c)
+	if !ok {
+		return
+	}
+
+	position, size, err := h.repo.GetWaitlistPosition(courseID, userID)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotWaitlisted) {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "User is not in the waitlist of this course")
+		} else {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving waitlist position")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"position":      position,
+		"waitlist_size": size,
+	}})
+}
+
+// GetWaitlist returns the waitlist of a course
+// @Summary Get the waitlist of a course
+// @Description Retrieve the users waiting for a seat in a course, in promotion order (teacher only)
+// @Tags enrollments
+// @Accept json
+// @Produce json
+// @Param course_id path string true "Course ID"
+// @Success 200 {object} model.SuccessResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Router /{course_id}/waitlist [get]
+func (h *courseHandlerImpl) GetWaitlist(c *gin.Context) {
+	courseID, ok := h.getCourseID(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.repo.GetWaitlist(courseID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving waitlist")
+		return
+	}
+
+	response := make([]gin.H, 0, len(entries))
+	for i, entry := range entries {
+		response = append(response, gin.H{
+			"position":  i + 1,
+			"user_id":   entry.UserID,
+			"joined_at": entry.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// notifyWaitlistPromotions tells every promoted user that they got a seat in the course.
+// The notifications are sent in the background, so that a slow notification service does not hold back
+// the request that freed the seats.
+func (h *courseHandlerImpl) notifyWaitlistPromotions(courseID uint, promotedUserIDs []string) {
+	if len(promotedUserIDs) == 0 {
+		return
+	}
+
+	course, err := h.repo.GetByID(courseID)
+	if err != nil {
+		return
+	}
+
+	go func() {
+		for _, userID := range promotedUserIDs {
+			h.notification.SendNotification(userID, course.Title, WAITLIST_PROMOTION_NOTIFICATION)
+		}
+	}()
+}
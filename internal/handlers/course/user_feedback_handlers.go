@@ -20,6 +20,7 @@ import (
 // @Success 201 {object} model.SuccessResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -83,13 +84,23 @@ func (h *courseHandlerImpl) CreateUserFeedback(c *gin.Context) {
 
 // GetUserFeedbacks retrieves all feedback for a specific user
 // @Summary Get all feedback for a user
-// @Description Retrieve all feedback submitted for a specific user
+// @Description Retrieve a page of the feedback submitted for a specific user, newest first by default. Only the user, the staff of a course the user is enrolled in and admins may read it.
 // @Tags feedback
 // @Accept json
 // @Produce json
 // @Param user_id path string true "User ID"
-// @Success 200 {object} model.SuccessResponse
+// @Param cursor query string false "Cursor of the page, from next_cursor"
+// @Param limit query int false "Feedbacks per page, 20 by default" minimum(1) maximum(100)
+// @Param sort query string false "Sort key, prefixed with - for descending order" Enums(created_at, -created_at, rating, -rating)
+// @Param course_id query int false "Only feedback of this course"
+// @Param min_rating query int false "Minimum rating" minimum(1) maximum(5)
+// @Param max_rating query int false "Maximum rating" minimum(1) maximum(5)
+// @Param created_from query string false "Created at or after, RFC 3339" format(date-time)
+// @Param created_to query string false "Created at or before, RFC 3339" format(date-time)
+// @Success 200 {object} model.PageResponse
+// @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security BearerAuth
@@ -100,15 +111,21 @@ func (h *courseHandlerImpl) GetUserFeedbacks(c *gin.Context) {
 		return
 	}
 
-	feedbacks, err := h.repo.GetUserFeedbacks(userID)
+	var filter model.FeedbackFilter
+	page, ok := h.bindListQuery(c, &filter)
+	if !ok {
+		return
+	}
+
+	feedbacks, err := h.repo.ListUserFeedbacks(userID, filter, page)
 	if err != nil {
-		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error retrieving user feedbacks")
+		h.listError(c, err, "Error retrieving user feedbacks")
 		return
 	}
 
 	// Format the response
-	response := make([]gin.H, 0, len(feedbacks))
-	for _, feedback := range feedbacks {
+	response := make([]gin.H, 0, len(feedbacks.Items))
+	for _, feedback := range feedbacks.Items {
 		response = append(response, gin.H{
 			"id":           feedback.ID,
 			"course_id":    feedback.CourseID,
@@ -121,6 +138,7 @@ func (h *courseHandlerImpl) GetUserFeedbacks(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": response,
+		"data":        response,
+		"next_cursor": feedbacks.NextCursor,
 	})
 }
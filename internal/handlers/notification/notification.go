@@ -66,6 +66,36 @@ Se ha creado una nueva tarea para el curso %s.`
   <p>Hola %s!<br>
   Se ha creado una nueva tarea para el curso %s.</p>
 </body>
+</html>`
+
+	waitlistPromotionSubjectTemplate = "ClassConnect - Lugar disponible"
+	textWaitlistPromotionTemplate    = `Buenas noticias %s!.
+Se liberó un lugar y ya estás inscripto en el curso %s.`
+	htmlWaitlistPromotionTemplate = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="UTF-8">
+  <title>Lugar disponible</title>
+</head>
+<body>
+  <p>Buenas noticias %s!<br>
+  Se liberó un lugar y ya estás inscripto en el curso %s.</p>
+</body>
+</html>`
+
+	deadlineReminderSubjectTemplate = "ClassConnect - Entrega próxima"
+	textDeadlineReminderTemplate    = `Hola %s!.
+La tarea %s del curso %s vence el %s y todavía no la entregaste.`
+	htmlDeadlineReminderTemplate = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="UTF-8">
+  <title>Entrega próxima</title>
+</head>
+<body>
+  <p>Hola %s!<br>
+  La tarea %s del curso %s vence el %s y todavía no la entregaste.</p>
+</body>
 </html>`
 )
 
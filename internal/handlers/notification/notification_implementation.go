@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
 	"log"
 	"net/http"
 	"os"
+	"time"
 )
 
 // NewNotificationClient creates a new notification client
@@ -128,19 +130,47 @@ func (sender *NotificationClient) SendNotification(userId, courseName, notificat
 		subject = newAssigmentSubjectTemplate
 		TextTemplate = textNewAssigmentTemplate
 		HtmlTemplate = htmlNewAssigmentTemplate
+	case "waitlist_promotion":
+		subject = waitlistPromotionSubjectTemplate
+		TextTemplate = textWaitlistPromotionTemplate
+		HtmlTemplate = htmlWaitlistPromotionTemplate
 	default:
 		subject, TextTemplate, HtmlTemplate = "", "", ""
 	}
 
-	payload := NotificationPayload{
+	sender.send(NotificationPayload{
 		ID:               userId,
 		ReceiverEmail:    userEmail,
 		NotificationType: notification_type,
 		Subject:          subject,
 		Text:             fmt.Sprintf(TextTemplate, userName, courseName),
 		HTML:             fmt.Sprintf(HtmlTemplate, userName, courseName),
+	})
+}
+
+// SendDeadlineReminder reminds a student that an assignment not submitted yet is due soon
+func (sender *NotificationClient) SendDeadlineReminder(userId, courseName, assignmentTitle string, deadline time.Time) {
+	userEmail, userName := sender.getUserEmailFromService(userId)
+	if userEmail == "" {
+		log.Printf("failed to get user email for userId: %s", userId)
+		return
 	}
 
+	// The users service does not know the time zone of each user
+	due := deadline.UTC().Format("02/01/2006 15:04") + " UTC"
+	sender.send(NotificationPayload{
+		ID:               userId,
+		ReceiverEmail:    userEmail,
+		NotificationType: "deadline_reminder",
+		Subject:          deadlineReminderSubjectTemplate,
+		Text:             fmt.Sprintf(textDeadlineReminderTemplate, userName, assignmentTitle, courseName, due),
+		HTML: fmt.Sprintf(htmlDeadlineReminderTemplate, html.EscapeString(userName), html.EscapeString(assignmentTitle),
+			html.EscapeString(courseName), due),
+	})
+}
+
+// send posts a notification to the notification service
+func (sender *NotificationClient) send(payload NotificationPayload) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		log.Printf("failed to marshal payload: %v", err)
@@ -174,3 +204,9 @@ func (sender *NotificationClient) SendNotificationToAll(allUsers []map[string]an
 		}
 	}
 }
+
+// GetUserName retrieves the name of a user from the users service, or an empty string if it fails
+func (sender *NotificationClient) GetUserName(userId string) string {
+	_, name := sender.getUserEmailFromService(userId)
+	return name
+}
@@ -2,12 +2,14 @@ package notification
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -130,3 +132,38 @@ func TestSendNotificationEmail_PostUnexpectedStatus(t *testing.T) {
 
 	assert.Contains(t, logs, "unexpected status code from notification", "Debe loguear status inesperado de POST")
 }
+
+func TestSendDeadlineReminder_Success(t *testing.T) {
+	os.Setenv("URL_NOTIFICATION", "http://notification")
+	os.Setenv("URL_USERS", "http://users")
+
+	var payload NotificationPayload
+	mock := &mockDoer{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if strings.Contains(req.URL.String(), "/users/profile/") {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"email":"test@example.com","name":"Ana"}`)),
+				}, nil
+			}
+			assert.Equal(t, "http://notification/notifications/send", req.URL.String())
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+			}, nil
+		},
+	}
+
+	client := NewNotificationClient(mock)
+	deadline := time.Date(2025, 5, 10, 23, 59, 0, 0, time.FixedZone("ART", -3*60*60))
+	logs := captureLogs(func() {
+		client.SendDeadlineReminder("123", "Algoritmos", "TP <1>", deadline)
+	})
+
+	assert.Empty(t, logs, "No se esperan logs en caso éxito")
+	assert.Equal(t, "deadline_reminder", payload.NotificationType)
+	assert.Equal(t, "test@example.com", payload.ReceiverEmail)
+	assert.Contains(t, payload.Text, "La tarea TP <1> del curso Algoritmos vence el 11/05/2025 02:59 UTC")
+	assert.Contains(t, payload.HTML, "TP &lt;1&gt;")
+}
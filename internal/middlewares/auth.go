@@ -58,6 +58,11 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// The role claim is optional, only administrators carry it
+		if role, ok := claims["role"].(string); ok {
+			c.Set("user_role", role)
+		}
+
 		c.Set("user_id", userID)
 		c.Set("user_email", userEmail)
 		c.Next()
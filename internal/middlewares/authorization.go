@@ -0,0 +1,271 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Role is the relationship between the authenticated user and the platform or a course
+type Role string
+
+const (
+	// RoleAdmin is a platform administrator, allowed everywhere
+	RoleAdmin Role = "admin"
+	// RoleOwner is the teacher that created the course
+	RoleOwner Role = "owner"
+	// RoleTeachingAssistant is a teacher listed in the course teaching assistants
+	RoleTeachingAssistant Role = "teaching_assistant"
+	// RoleStudent is a user enrolled in the course
+	RoleStudent Role = "student"
+	// RoleSelf is the user named by the :user_id of the route
+	RoleSelf Role = "self"
+	// RoleUserTeacher is a teacher or teaching assistant of a course the user named by :user_id is enrolled in
+	RoleUserTeacher Role = "user_teacher"
+)
+
+// Role groups used by the permission table
+var (
+	CourseStaff   = []Role{RoleOwner, RoleTeachingAssistant}
+	CourseMembers = []Role{RoleOwner, RoleTeachingAssistant, RoleStudent}
+)
+
+// CourseMembershipResolver provides the data needed to resolve the roles of a user in a course.
+// repositories.CourseRepository satisfies it.
+type CourseMembershipResolver interface {
+	GetByID(id uint) (*model.Course, error)
+	IsUserEnrolled(courseID uint, userID string) (bool, error)
+	IsTeacherOfStudent(userEmail, studentID string) (bool, error)
+}
+
+// Permission declares which roles may call an endpoint.
+// An empty Roles and SelfRoles list means that any authenticated user may call it.
+// SelfRoles are allowed only when the :user_id of the route is the current user.
+// Admins are always allowed.
+// Archived courses are read-only: requests that change a course are rejected unless AllowArchived is set.
+type Permission struct {
+	Method        string
+	Path          string
+	Roles         []Role
+	SelfRoles     []Role
+	AllowArchived bool
+}
+
+// PermissionTable is the declarative list of per-route permissions
+type PermissionTable []Permission
+
+// Lookup finds the permission declared for a method and a gin route path
+func (t PermissionTable) Lookup(method, path string) (Permission, bool) {
+	for _, permission := range t {
+		if permission.Method == method && permission.Path == path {
+			return permission, true
+		}
+	}
+	return Permission{}, false
+}
+
+// Allows reports whether any of the given roles satisfies the permission
+func (p Permission) Allows(roles []Role) bool {
+	if !p.restricted() {
+		return true
+	}
+	self := hasAny(roles, RoleSelf)
+	for _, role := range roles {
+		if role == RoleAdmin || hasAny(p.Roles, role) || (self && hasAny(p.SelfRoles, role)) {
+			return true
+		}
+	}
+	return false
+}
+
+// restricted reports whether the permission is limited to some roles
+func (p Permission) restricted() bool {
+	return len(p.Roles) > 0 || len(p.SelfRoles) > 0
+}
+
+// hasAny reports whether roles contains any of the wanted roles
+func hasAny(roles []Role, wanted ...Role) bool {
+	for _, role := range roles {
+		for _, w := range wanted {
+			if role == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Authorize enforces the permission table on every route of the group it is attached to.
+// It must run after AuthMiddleware. Routes without a declared permission are rejected.
+// The resolved roles are stored in the context under "user_roles".
+func Authorize(resolver CourseMembershipResolver, permissions PermissionTable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permission, ok := permissions.Lookup(c.Request.Method, c.FullPath())
+		if !ok {
+			forbidden(c, "No permission is declared for this endpoint")
+			return
+		}
+
+		roles := []Role{}
+		if isAdmin(c) {
+			roles = append(roles, RoleAdmin)
+		}
+
+		checksArchived := !permission.AllowArchived && !isReadOnly(c.Request.Method)
+		var course *model.Course
+		if c.Param("course_id") != "" && (permission.restricted() || checksArchived) {
+			if course, ok = loadCourse(c, resolver); !ok {
+				return
+			}
+		}
+
+		if course != nil && permission.restricted() {
+			courseRoles, ok := resolveCourseRoles(c, resolver, course)
+			if !ok {
+				return
+			}
+			roles = append(roles, courseRoles...)
+		}
+
+		if c.Param("user_id") != "" && permission.restricted() {
+			userRoles, ok := resolveUserRoles(c, resolver, permission)
+			if !ok {
+				return
+			}
+			roles = append(roles, userRoles...)
+		}
+
+		if !permission.Allows(roles) {
+			forbidden(c, "You do not have permission to access this resource")
+			return
+		}
+
+		if course != nil && checksArchived && course.IsArchived() {
+			utils.NewErrorResponse(c, http.StatusConflict, "Course Archived", "Archived courses are read-only")
+			c.Abort()
+			return
+		}
+
+		c.Set("user_roles", roles)
+		c.Next()
+	}
+}
+
+// HasRole reports whether the authorization middleware granted any of the given roles to the current user
+func HasRole(c *gin.Context, roles ...Role) bool {
+	value, exists := c.Get("user_roles")
+	if !exists {
+		return false
+	}
+	granted, ok := value.([]Role)
+	if !ok {
+		return false
+	}
+	return hasAny(granted, roles...)
+}
+
+// loadCourse loads the course of the route
+func loadCourse(c *gin.Context, resolver CourseMembershipResolver) (*model.Course, bool) {
+	courseID, err := strconv.Atoi(c.Param("course_id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Parameter", "Course ID must be a number")
+		c.Abort()
+		return nil, false
+	}
+
+	course, err := resolver.GetByID(uint(courseID))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusNotFound, "Not Found", "Course not found")
+		c.Abort()
+		return nil, false
+	}
+	return course, true
+}
+
+// resolveCourseRoles finds the roles of the current user in the course of the route
+func resolveCourseRoles(c *gin.Context, resolver CourseMembershipResolver, course *model.Course) ([]Role, bool) {
+	userID := c.GetString("user_id")
+	userEmail := c.GetString("user_email")
+
+	roles := []Role{}
+	if userEmail != "" && course.CreatedBy == userEmail {
+		roles = append(roles, RoleOwner)
+	}
+	for _, assistant := range course.TeachingAssistants {
+		if userEmail != "" && assistant == userEmail {
+			roles = append(roles, RoleTeachingAssistant)
+			break
+		}
+	}
+
+	enrolled, err := resolver.IsUserEnrolled(course.ID, userID)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error checking course membership")
+		c.Abort()
+		return nil, false
+	}
+	if enrolled {
+		roles = append(roles, RoleStudent)
+	}
+
+	return roles, true
+}
+
+// resolveUserRoles finds the roles of the current user towards the user named by the :user_id of the route.
+// Whether the current user teaches that user is only looked up when the permission allows it.
+func resolveUserRoles(c *gin.Context, resolver CourseMembershipResolver, permission Permission) ([]Role, bool) {
+	userID := c.GetString("user_id")
+	userEmail := c.GetString("user_email")
+	routeUserID := c.Param("user_id")
+
+	roles := []Role{}
+	if userID != "" && routeUserID == userID {
+		roles = append(roles, RoleSelf)
+	}
+
+	if userEmail != "" && hasAny(permission.Roles, RoleUserTeacher) {
+		teacher, err := resolver.IsTeacherOfStudent(userEmail, routeUserID)
+		if err != nil {
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Server Error", "Error checking course membership")
+			c.Abort()
+			return nil, false
+		}
+		if teacher {
+			roles = append(roles, RoleUserTeacher)
+		}
+	}
+
+	return roles, true
+}
+
+// isReadOnly reports whether requests with the method only read data
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// isAdmin reports whether the token carries the admin role or the email is listed in ADMIN_EMAILS
+func isAdmin(c *gin.Context) bool {
+	if c.GetString("user_role") == string(RoleAdmin) {
+		return true
+	}
+	userEmail := c.GetString("user_email")
+	if userEmail == "" {
+		return false
+	}
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if strings.TrimSpace(email) == userEmail {
+			return true
+		}
+	}
+	return false
+}
+
+func forbidden(c *gin.Context, detail string) {
+	utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", detail)
+	c.Abort()
+}
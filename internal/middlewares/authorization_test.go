@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"templateGo/internal/model"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCourseID = 1
+
+type fakeResolver struct {
+	course   model.Course
+	enrolled map[string]bool
+}
+
+func (f *fakeResolver) GetByID(id uint) (*model.Course, error) {
+	if id != f.course.ID {
+		return nil, errors.New("record not found")
+	}
+	return &f.course, nil
+}
+
+func (f *fakeResolver) IsUserEnrolled(courseID uint, userID string) (bool, error) {
+	return courseID == f.course.ID && f.enrolled[userID], nil
+}
+
+func (f *fakeResolver) IsTeacherOfStudent(userEmail, studentID string) (bool, error) {
+	staff := userEmail == f.course.CreatedBy || slices.Contains(f.course.TeachingAssistants, userEmail)
+	return staff && f.enrolled[studentID], nil
+}
+
+type testIdentity struct {
+	userID    string
+	userEmail string
+	role      string
+}
+
+// identities maps every role to a user that holds only that role in the test course
+var identities = map[Role]testIdentity{
+	RoleAdmin:             {userID: "admin", userEmail: "admin@example.com", role: "admin"},
+	RoleOwner:             {userID: "owner", userEmail: "owner@example.com"},
+	RoleTeachingAssistant: {userID: "ta", userEmail: "ta@example.com"},
+	RoleStudent:           {userID: "student", userEmail: "student@example.com"},
+}
+
+var outsider = testIdentity{userID: "outsider", userEmail: "outsider@example.com"}
+
+func newTestResolver() *fakeResolver {
+	course := model.Course{CreatedBy: "owner@example.com", TeachingAssistants: []string{"ta@example.com"}}
+	course.ID = testCourseID
+	return &fakeResolver{course: course, enrolled: map[string]bool{"student": true}}
+}
+
+// setupAuthorizedRouter registers every route of the permission table behind Authorize
+func setupAuthorizedRouter(permissions PermissionTable) *gin.Engine {
+	return setupRouterWithResolver(newTestResolver(), permissions)
+}
+
+func setupRouterWithResolver(resolver *fakeResolver, permissions PermissionTable) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	api := r.Group("/")
+	api.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User-ID"))
+		c.Set("user_email", c.GetHeader("X-User-Email"))
+		if role := c.GetHeader("X-User-Role"); role != "" {
+			c.Set("user_role", role)
+		}
+		c.Next()
+	})
+	api.Use(Authorize(resolver, permissions))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	for _, permission := range permissions {
+		api.Handle(permission.Method, permission.Path, ok)
+	}
+	return r
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+func concretePath(path string) string {
+	return pathParam.ReplaceAllStringFunc(path, func(param string) string {
+		if param == ":user_id" {
+			return "someone"
+		}
+		return "1"
+	})
+}
+
+func doRequest(r *gin.Engine, method, path string, identity testIdentity) int {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("X-User-ID", identity.userID)
+	req.Header.Set("X-User-Email", identity.userEmail)
+	req.Header.Set("X-User-Role", identity.role)
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestCoursePermissions_RejectWrongRoles(t *testing.T) {
+	r := setupAuthorizedRouter(CoursePermissions)
+
+	for _, permission := range CoursePermissions {
+		path := concretePath(permission.Path)
+
+		if len(permission.Roles) == 0 {
+			assert.Equal(t, http.StatusOK, doRequest(r, permission.Method, path, outsider),
+				"%s %s should be open to any authenticated user", permission.Method, permission.Path)
+			continue
+		}
+
+		assert.Equal(t, http.StatusForbidden, doRequest(r, permission.Method, path, outsider),
+			"%s %s should reject users without a role in the course", permission.Method, permission.Path)
+
+		for role, identity := range identities {
+			expected := http.StatusForbidden
+			if permission.Allows([]Role{role}) {
+				expected = http.StatusOK
+			}
+			assert.Equal(t, expected, doRequest(r, permission.Method, path, identity),
+				"%s %s with role %s", permission.Method, permission.Path, role)
+		}
+	}
+}
+
+func TestCoursePermissions_StaffOnlyEndpoints(t *testing.T) {
+	r := setupAuthorizedRouter(CoursePermissions)
+
+	staffOnly := []struct{ method, path string }{
+		{http.MethodPatch, "/1/assignment/1/submission/1"},
+		{http.MethodDelete, "/1"},
+		{http.MethodPatch, "/1/assignment/1"},
+		{http.MethodPost, "/1/resource/module"},
+		{http.MethodPost, "/approve/someone/1"},
+		{http.MethodGet, "/statistics/1"},
+	}
+
+	for _, endpoint := range staffOnly {
+		assert.Equal(t, http.StatusForbidden, doRequest(r, endpoint.method, endpoint.path, identities[RoleStudent]),
+			"students must not call %s %s", endpoint.method, endpoint.path)
+		assert.Equal(t, http.StatusOK, doRequest(r, endpoint.method, endpoint.path, identities[RoleAdmin]),
+			"admins may call %s %s", endpoint.method, endpoint.path)
+	}
+}
+
+func TestAuthorize_AdminFromEnvironment(t *testing.T) {
+	t.Setenv("ADMIN_EMAILS", "first@example.com, root@example.com")
+	r := setupAuthorizedRouter(CoursePermissions)
+
+	code := doRequest(r, http.MethodDelete, "/1", testIdentity{userID: "root", userEmail: "root@example.com"})
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestAuthorize_UndeclaredRouteIsRejected(t *testing.T) {
+	r := setupAuthorizedRouter(PermissionTable{})
+	r.Group("/").Use(Authorize(newTestResolver(), PermissionTable{})).GET("/undeclared", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusForbidden, doRequest(r, http.MethodGet, "/undeclared", identities[RoleAdmin]))
+}
+
+func TestAuthorize_UnknownCourse(t *testing.T) {
+	r := setupAuthorizedRouter(CoursePermissions)
+
+	assert.Equal(t, http.StatusNotFound, doRequest(r, http.MethodDelete, "/999", identities[RoleOwner]))
+	assert.Equal(t, http.StatusBadRequest, doRequest(r, http.MethodDelete, "/abc", identities[RoleOwner]))
+}
+
+func TestAuthorize_ArchivedCourseIsReadOnly(t *testing.T) {
+	resolver := newTestResolver()
+	resolver.course.State = model.CourseStateArchived
+	r := setupRouterWithResolver(resolver, CoursePermissions)
+
+	assert.Equal(t, http.StatusConflict, doRequest(r, http.MethodPatch, "/1", identities[RoleOwner]))
+	assert.Equal(t, http.StatusConflict, doRequest(r, http.MethodPost, "/1/assignment", identities[RoleTeachingAssistant]))
+	assert.Equal(t, http.StatusConflict, doRequest(r, http.MethodPost, "/1/enroll", outsider))
+	assert.Equal(t, http.StatusConflict, doRequest(r, http.MethodPatch, "/1", identities[RoleAdmin]))
+
+	// Reads and the changes allowed on archived courses go through
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, "/1/assignments", identities[RoleStudent]))
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodPost, "/1/feedback", identities[RoleStudent]))
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodDelete, "/1", identities[RoleOwner]))
+
+	// Permissions are checked first, so users without a role still get 403
+	assert.Equal(t, http.StatusForbidden, doRequest(r, http.MethodPatch, "/1", identities[RoleStudent]))
+}
+
+func TestCoursePermissions_UserFeedbacks(t *testing.T) {
+	r := setupAuthorizedRouter(CoursePermissions)
+
+	for _, path := range []string{"/user/student/feedbacks", "/user/student/ai-feedback-analysis"} {
+		// The student themselves, the staff of a course the student is enrolled in and admins
+		assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, path, identities[RoleStudent]), path)
+		assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, path, identities[RoleOwner]), path)
+		assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, path, identities[RoleTeachingAssistant]), path)
+		assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, path, identities[RoleAdmin]), path)
+
+		// Any other user
+		assert.Equal(t, http.StatusForbidden, doRequest(r, http.MethodGet, path, outsider), path)
+	}
+
+	// Staff of the course cannot read the feedback of users not enrolled in it, nor students that of others
+	assert.Equal(t, http.StatusForbidden, doRequest(r, http.MethodGet, "/user/someone/feedbacks", identities[RoleOwner]))
+	assert.Equal(t, http.StatusForbidden, doRequest(r, http.MethodGet, "/user/someone/feedbacks", identities[RoleStudent]))
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, "/user/outsider/feedbacks", outsider))
+}
+
+func TestCoursePermissions_UserStatisticsForCourse(t *testing.T) {
+	r := setupAuthorizedRouter(CoursePermissions)
+
+	// Students read only their own statistics
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, "/statistics/course/1/user/student", identities[RoleStudent]))
+	assert.Equal(t, http.StatusForbidden, doRequest(r, http.MethodGet, "/statistics/course/1/user/someone", identities[RoleStudent]))
+
+	// Users not enrolled in the course, even about themselves
+	assert.Equal(t, http.StatusForbidden, doRequest(r, http.MethodGet, "/statistics/course/1/user/outsider", outsider))
+
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, "/statistics/course/1/user/student", identities[RoleOwner]))
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, "/statistics/course/1/user/someone", identities[RoleTeachingAssistant]))
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodGet, "/statistics/course/1/user/someone", identities[RoleAdmin]))
+}
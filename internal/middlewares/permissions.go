@@ -0,0 +1,128 @@
+package middleware
+
+import "net/http"
+
+// CoursePermissions declares who may call each route registered in services.SetupRoutes.
+// Every new route must be added here, otherwise Authorize rejects it. Routes that change a course
+// are rejected on archived courses unless they set AllowArchived.
+var CoursePermissions = PermissionTable{
+	// Course Management
+	{Method: http.MethodPost, Path: "/course"},
+	{Method: http.MethodGet, Path: "/courses"},
+	{Method: http.MethodGet, Path: "/:course_id"},
+	{Method: http.MethodPatch, Path: "/:course_id", Roles: []Role{RoleOwner}},
+	{Method: http.MethodDelete, Path: "/:course_id", Roles: []Role{RoleOwner}, AllowArchived: true},
+	{Method: http.MethodGet, Path: "/:course_id/members", Roles: CourseMembers},
+	{Method: http.MethodGet, Path: "/available"},
+	{Method: http.MethodGet, Path: "/search"},
+	{Method: http.MethodPatch, Path: "/:course_id/favorite/toggle", Roles: []Role{RoleStudent}, AllowArchived: true},
+
+	// Course Lifecycle
+	{Method: http.MethodPost, Path: "/:course_id/state", Roles: []Role{RoleOwner}},
+	{Method: http.MethodGet, Path: "/:course_id/state/schedules", Roles: CourseStaff},
+	{Method: http.MethodDelete, Path: "/:course_id/state/schedules/:schedule_id", Roles: []Role{RoleOwner}},
+
+	// Course Cloning
+	{Method: http.MethodPost, Path: "/:course_id/clone", Roles: CourseStaff, AllowArchived: true},
+	{Method: http.MethodPost, Path: "/templates/:course_id", AllowArchived: true},
+
+	// Enrollment Management
+	{Method: http.MethodPost, Path: "/:course_id/enroll"},
+	{Method: http.MethodDelete, Path: "/:course_id/enroll", Roles: []Role{RoleStudent}},
+	{Method: http.MethodGet, Path: "/enrolled"},
+	{Method: http.MethodGet, Path: "/:course_id/eligibility"},
+
+	// Waitlist Management
+	{Method: http.MethodPost, Path: "/:course_id/waitlist"},
+	{Method: http.MethodDelete, Path: "/:course_id/waitlist", AllowArchived: true},
+	{Method: http.MethodGet, Path: "/:course_id/waitlist/position"},
+	{Method: http.MethodGet, Path: "/:course_id/waitlist", Roles: CourseStaff},
+
+	// Course Approval System
+	{Method: http.MethodPost, Path: "/approve/:user_id/:course_id", Roles: CourseStaff, AllowArchived: true},
+	{Method: http.MethodGet, Path: "/approved"},
+	{Method: http.MethodGet, Path: "/:course_id/approved-users", Roles: CourseStaff},
+	{Method: http.MethodPut, Path: "/:course_id/approval/rules", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/approval/rules", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/approval/preview", Roles: CourseStaff},
+	{Method: http.MethodPost, Path: "/:course_id/approval/run", Roles: CourseStaff, AllowArchived: true},
+
+	// Certificates, verifying them is public and not listed here
+	{Method: http.MethodGet, Path: "/certificates"},
+	{Method: http.MethodGet, Path: "/certificates/:certificate_id/pdf"},
+	{Method: http.MethodGet, Path: "/:course_id/certificate", Roles: []Role{RoleStudent}},
+	{Method: http.MethodGet, Path: "/:course_id/certificates", Roles: CourseStaff},
+
+	// Calendar Feeds, reading them is public and not listed here
+	{Method: http.MethodPost, Path: "/calendar/feeds"},
+	{Method: http.MethodGet, Path: "/calendar/feeds"},
+	{Method: http.MethodDelete, Path: "/calendar/feeds/:feed_id"},
+	{Method: http.MethodPost, Path: "/:course_id/calendar/feeds", Roles: CourseMembers, AllowArchived: true},
+
+	// Course Feedback & Ratings
+	{Method: http.MethodPost, Path: "/:course_id/feedback", Roles: []Role{RoleStudent}, AllowArchived: true},
+	{Method: http.MethodGet, Path: "/:course_id/feedbacks", Roles: CourseMembers},
+	{Method: http.MethodGet, Path: "/:course_id/ai-feedback-analysis", Roles: CourseStaff},
+
+	// User Feedback & Ratings
+	{Method: http.MethodPost, Path: "/:course_id/user/:user_id/feedback", Roles: CourseStaff, AllowArchived: true},
+	{Method: http.MethodGet, Path: "/user/:user_id/feedbacks", Roles: []Role{RoleSelf, RoleUserTeacher}},
+	{Method: http.MethodGet, Path: "/user/:user_id/ai-feedback-analysis", Roles: []Role{RoleSelf, RoleUserTeacher}},
+
+	// Assignment Management
+	{Method: http.MethodPost, Path: "/:course_id/assignment", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/assignments", Roles: CourseMembers},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id", Roles: CourseMembers},
+	{Method: http.MethodPatch, Path: "/:course_id/assignment/:assignment_id", Roles: CourseStaff},
+	{Method: http.MethodDelete, Path: "/:course_id/assignment/:assignment_id", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/file/:file_id", Roles: CourseMembers},
+
+	// Rubric Management
+	{Method: http.MethodPost, Path: "/:course_id/rubric", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/rubrics", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/rubric/:rubric_id", Roles: CourseStaff},
+	{Method: http.MethodPatch, Path: "/:course_id/rubric/:rubric_id", Roles: CourseStaff},
+	{Method: http.MethodDelete, Path: "/:course_id/rubric/:rubric_id", Roles: CourseStaff},
+
+	// Gradebook
+	{Method: http.MethodPost, Path: "/:course_id/gradebook/categories", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/gradebook/categories", Roles: CourseMembers},
+	{Method: http.MethodPatch, Path: "/:course_id/gradebook/categories/:category_id", Roles: CourseStaff},
+	{Method: http.MethodDelete, Path: "/:course_id/gradebook/categories/:category_id", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/gradebook", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/gradebook/me", Roles: []Role{RoleStudent}},
+
+	// Submission Management
+	{Method: http.MethodPut, Path: "/:course_id/assignment/:assignment_id/submission", Roles: []Role{RoleStudent}},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/submission", Roles: []Role{RoleStudent}},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/submissions", Roles: CourseStaff},
+	{Method: http.MethodPatch, Path: "/:course_id/assignment/:assignment_id/submission/:submission_id", Roles: CourseStaff},
+	{Method: http.MethodPost, Path: "/:course_id/assignment/:assignment_id/submission/:submission_id/rubric-grade", Roles: CourseStaff},
+	{Method: http.MethodPost, Path: "/:course_id/assignment/:assignment_id/submission/:submission_id/return", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/submission/:submission_id/ai-grade", Roles: CourseStaff},
+	{Method: http.MethodPost, Path: "/:course_id/assignment/:assignment_id/submission/:submission_id/ai-grade/accept", Roles: CourseStaff},
+	{Method: http.MethodDelete, Path: "/:course_id/assignment/:assignment_id/submission", Roles: []Role{RoleStudent}},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/submission/:submission_id/file/:file_id", Roles: CourseMembers},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/submission/attempts", Roles: []Role{RoleStudent}},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/submission/:submission_id/attempts", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/submission/:submission_id/attempts/diff", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/assignment/:assignment_id/similarity", Roles: CourseStaff},
+	{Method: http.MethodPost, Path: "/:course_id/assignment/:assignment_id/similarity", Roles: CourseStaff},
+
+	// Resources Management
+	{Method: http.MethodPost, Path: "/:course_id/resource/module", Roles: CourseStaff},
+	{Method: http.MethodPost, Path: "/:course_id/resource/module/:module_id", Roles: CourseStaff},
+	{Method: http.MethodPatch, Path: "/:course_id/resource/module/:module_id", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/:course_id/resources", Roles: CourseMembers},
+	{Method: http.MethodPatch, Path: "/:course_id/resources", Roles: CourseStaff},
+	{Method: http.MethodDelete, Path: "/:course_id/resource/module/:module_id/:resource_id", Roles: CourseStaff},
+	{Method: http.MethodDelete, Path: "/:course_id/resource/module/:module_id", Roles: CourseStaff},
+	{Method: http.MethodPost, Path: "/:course_id/resource/module/:module_id/:resource_id/complete", Roles: []Role{RoleStudent}},
+
+	// Statistics
+	{Method: http.MethodGet, Path: "/statistics/global"},
+	{Method: http.MethodGet, Path: "/statistics/:course_id", Roles: CourseStaff},
+	{Method: http.MethodGet, Path: "/statistics/course/:course_id/user/:user_id", Roles: CourseStaff, SelfRoles: []Role{RoleStudent}},
+	{Method: http.MethodGet, Path: "/statistics/tasks/failed", Roles: []Role{RoleAdmin}},
+	{Method: http.MethodPost, Path: "/statistics/tasks/:task_id/requeue", Roles: []Role{RoleAdmin}},
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ApprovalRules are the completion rules a student must meet to approve a course. Only the rules that are
+// set are checked.
+type ApprovalRules struct {
+	ID                          uint       `json:"id" gorm:"primaryKey"`
+	CourseID                    uint       `json:"course_id" gorm:"not null;uniqueIndex"`
+	MinFinalGrade               *float64   `json:"min_final_grade"`                                  // from 0 to 100, as computed by the gradebook
+	RequireMandatoryAssignments bool       `json:"require_mandatory_assignments" gorm:"not null"`    // every mandatory assignment submitted
+	MinResourceCompletion       *float64   `json:"min_resource_completion"`                          // percentage of the resources completed
+	AutoApprove                 bool       `json:"auto_approve" gorm:"not null;default:false;index"` // approve in the background when the course ends
+	LastRunAt                   *time.Time `json:"last_run_at"`                                      // when the rules were applied after the course ended
+	ClaimedUntil                *time.Time `json:"-"`                                                // when the claim of the instance applying the rules expires
+	CreatedAt                   time.Time  `json:"created_at"`
+	UpdatedAt                   time.Time  `json:"updated_at"`
+}
+
+// ApprovalRulesRequest creates or replaces the approval rules of a course
+type ApprovalRulesRequest struct {
+	MinFinalGrade               *float64 `json:"min_final_grade" binding:"omitempty,gte=0,lte=100" example:"60"`
+	RequireMandatoryAssignments bool     `json:"require_mandatory_assignments" example:"true"`
+	MinResourceCompletion       *float64 `json:"min_resource_completion" binding:"omitempty,gte=0,lte=100" example:"75"`
+	AutoApprove                 bool     `json:"auto_approve" example:"true"`
+}
+
+// Validate rejects rules that would approve every student
+func (r *ApprovalRulesRequest) Validate() error {
+	if r.MinFinalGrade == nil && !r.RequireMandatoryAssignments && r.MinResourceCompletion == nil {
+		return errors.New("at least one rule must be set")
+	}
+	return nil
+}
+
+// ApplyTo sets the rules of the request, keeping when they were last applied
+func (r *ApprovalRulesRequest) ApplyTo(rules *ApprovalRules) {
+	rules.MinFinalGrade = r.MinFinalGrade
+	rules.RequireMandatoryAssignments = r.RequireMandatoryAssignments
+	rules.MinResourceCompletion = r.MinResourceCompletion
+	rules.AutoApprove = r.AutoApprove
+}
+
+// ApprovalProgress is what a student did in a course, as checked by the approval rules
+type ApprovalProgress struct {
+	UserID             string
+	FinalGrade         *float64
+	MissingAssignments []uint // mandatory assignments without a submission
+	ResourcesCompleted int
+	ResourcesTotal     int
+}
+
+// ResourceCompletion returns the percentage of the resources of the course the student completed.
+// Courses without resources count as completed.
+func (p *ApprovalProgress) ResourceCompletion() float64 {
+	if p.ResourcesTotal == 0 {
+		return 100
+	}
+	return roundGrade(float64(p.ResourcesCompleted) / float64(p.ResourcesTotal) * 100)
+}
+
+// ApprovalResult is how a student did against the approval rules of a course
+type ApprovalResult struct {
+	UserID             string   `json:"user_id"`
+	Eligible           bool     `json:"eligible"`
+	AlreadyApproved    bool     `json:"already_approved"`
+	Approved           bool     `json:"approved"` // approved by this run
+	FinalGrade         *float64 `json:"final_grade"`
+	MissingAssignments []uint   `json:"missing_assignments"`
+	ResourceCompletion float64  `json:"resource_completion"`
+	Reasons            []string `json:"reasons"` // why the student is not eligible
+}
+
+// ApprovalReport is the result of evaluating the approval rules of a course for every enrolled student
+type ApprovalReport struct {
+	CourseID    uint             `json:"course_id"`
+	DryRun      bool             `json:"dry_run"`
+	EvaluatedAt time.Time        `json:"evaluated_at"`
+	Eligible    int              `json:"eligible"`
+	Approved    int              `json:"approved"`
+	Students    []ApprovalResult `json:"students"`
+}
+
+// Evaluate checks the progress of a student against the rules
+func (r *ApprovalRules) Evaluate(progress ApprovalProgress) ApprovalResult {
+	result := ApprovalResult{
+		UserID:             progress.UserID,
+		FinalGrade:         progress.FinalGrade,
+		MissingAssignments: progress.MissingAssignments,
+		ResourceCompletion: progress.ResourceCompletion(),
+		Reasons:            []string{},
+	}
+	if result.MissingAssignments == nil {
+		result.MissingAssignments = []uint{}
+	}
+
+	if r.MinFinalGrade != nil {
+		switch {
+		case progress.FinalGrade == nil:
+			result.Reasons = append(result.Reasons, "no assignment counts towards the final grade yet")
+		case *progress.FinalGrade < *r.MinFinalGrade:
+			result.Reasons = append(result.Reasons,
+				fmt.Sprintf("final grade %.2f is below %.2f", *progress.FinalGrade, *r.MinFinalGrade))
+		}
+	}
+	if r.RequireMandatoryAssignments && len(progress.MissingAssignments) > 0 {
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("%d mandatory assignments not submitted", len(progress.MissingAssignments)))
+	}
+	if r.MinResourceCompletion != nil && result.ResourceCompletion < *r.MinResourceCompletion {
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("completed %.2f%% of the resources, %.2f%% required", result.ResourceCompletion, *r.MinResourceCompletion))
+	}
+
+	result.Eligible = len(result.Reasons) == 0
+	return result
+}
+
+// ResourceCompletion records that a student completed a resource of a course
+type ResourceCompletion struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ResourceID  string    `json:"resource_id" gorm:"not null;uniqueIndex:idx_resource_completions_user"`
+	UserID      string    `json:"user_id" gorm:"not null;uniqueIndex:idx_resource_completions_user"`
+	CompletedAt time.Time `json:"completed_at"`
+}
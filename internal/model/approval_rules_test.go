@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalRules_Evaluate(t *testing.T) {
+	minGrade, minCompletion := 60.0, 75.0
+	rules := &ApprovalRules{MinFinalGrade: &minGrade, RequireMandatoryAssignments: true, MinResourceCompletion: &minCompletion}
+
+	passing := 72.5
+	result := rules.Evaluate(ApprovalProgress{UserID: "a", FinalGrade: &passing, ResourcesCompleted: 3, ResourcesTotal: 4})
+	assert.True(t, result.Eligible)
+	assert.Empty(t, result.Reasons)
+	assert.Equal(t, 75.0, result.ResourceCompletion)
+	assert.Equal(t, []uint{}, result.MissingAssignments)
+
+	failing := 59.99
+	result = rules.Evaluate(ApprovalProgress{
+		UserID: "b", FinalGrade: &failing, MissingAssignments: []uint{4, 9}, ResourcesCompleted: 1, ResourcesTotal: 3,
+	})
+	assert.False(t, result.Eligible)
+	assert.Equal(t, []string{
+		"final grade 59.99 is below 60.00",
+		"2 mandatory assignments not submitted",
+		"completed 33.33% of the resources, 75.00% required",
+	}, result.Reasons)
+
+	result = rules.Evaluate(ApprovalProgress{UserID: "c"})
+	assert.False(t, result.Eligible, "a student with no grade does not meet a minimum grade")
+	assert.Equal(t, 100.0, result.ResourceCompletion, "courses without resources count as completed")
+}
+
+func TestApprovalRules_OnlyChecksTheRulesSet(t *testing.T) {
+	rules := &ApprovalRules{RequireMandatoryAssignments: true}
+
+	result := rules.Evaluate(ApprovalProgress{UserID: "a", ResourcesTotal: 10})
+	assert.True(t, result.Eligible)
+}
+
+func TestApprovalRulesRequest_Validate(t *testing.T) {
+	assert.Error(t, (&ApprovalRulesRequest{AutoApprove: true}).Validate())
+	assert.NoError(t, (&ApprovalRulesRequest{RequireMandatoryAssignments: true}).Validate())
+}
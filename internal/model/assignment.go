@@ -14,27 +14,47 @@ import (
 // 	Size        int64          `json:"size"`
 // }
 
-// Provisory: a file struct has content as binary data
+// File is a file attached to an assignment. Its content is kept in the blob store.
 type File struct {
-	ID      uint   `gorm:"primarykey" json:"id"`
-	Name    string `gorm:"not null" json:"name"`
-	Content []byte `gorm:"not null" json:"content"`
-	Size    int64  `json:"size"`
+	ID   uint   `gorm:"primarykey" json:"id"`
+	Name string `gorm:"not null" json:"name"`
+	FileContent
+}
+
+// FileContent describes the content of a file, stored in the blob store under its SHA-256 digest.
+// Content is only filled in requests and when a handler needs the bytes, listings return the metadata.
+type FileContent struct {
+	SHA256      string `gorm:"column:sha256;index" json:"sha256"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Content     []byte `gorm:"-" json:"content,omitempty"`
 }
 
 type Assignment struct {
-	ID          uint           `gorm:"primarykey" json:"id"`
-	CourseID    uint           `gorm:"not null" json:"course_id"`
-	Title       string         `gorm:"not null" json:"title"`
-	Description string         `json:"description"`
-	Deadline    time.Time      `json:"deadline"`
-	TimeLimit   int            `json:"time_limit"` // in minutes
-	Files       []File         `gorm:"many2many:assignment_files" json:"files"`
-	CreatedAt   time.Time      `json:"created_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint           `gorm:"primarykey" json:"id"`
+	CourseID          uint           `gorm:"not null" json:"course_id"`
+	Title             string         `gorm:"not null" json:"title"`
+	Description       string         `json:"description"`
+	Deadline          time.Time      `json:"deadline"`
+	TimeLimit         int            `json:"time_limit"` // in minutes
+	LatePolicy        string         `gorm:"not null;default:accept" json:"late_policy"`
+	LatePenaltyPerDay float64        `json:"late_penalty_per_day"` // percentage of the grade, for the penalty policy
+	GracePeriod       int            `json:"grace_period"`         // in minutes after the deadline
+	MaxAttempts       int            `json:"max_attempts"`         // 0 allows unlimited attempts
+	AttemptPolicy     string         `gorm:"not null;default:last" json:"attempt_policy"`
+	AllowedFileTypes  []string       `gorm:"serializer:json;type:jsonb" json:"allowed_file_types"` // any type when empty
+	MaxFileSize       int64          `gorm:"not null;default:0" json:"max_file_size"`              // in bytes, the service limit when 0
+	Files             []File         `gorm:"many2many:assignment_files" json:"files"`
+	RubricID          *uint          `json:"rubric_id"`
+	CategoryID        *uint          `json:"category_id"`                             // category of the gradebook it counts in
+	Points            float64        `gorm:"not null;default:100" json:"points"`      // what it is worth in its category
+	Mandatory         bool           `gorm:"not null;default:false" json:"mandatory"` // must be submitted to approve the course
+	CreatedAt         time.Time      `json:"created_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Associations
-	Course Course `gorm:"foreignKey:CourseID" json:"-"`
+	Course Course  `gorm:"foreignKey:CourseID" json:"-"`
+	Rubric *Rubric `gorm:"foreignKey:RubricID" json:"rubric,omitempty"`
 }
 
 type AssignmentPreview struct {
@@ -45,6 +65,10 @@ type AssignmentPreview struct {
 	CreatedAt time.Time      `json:"created_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 	Status    string         `json:"status"` // "pending", "submitted", "started"
+
+	CategoryID *uint   `json:"category_id"`
+	Points     float64 `json:"points"`
+	Mandatory  bool    `json:"mandatory"`
 }
 
 type AssignmentSession struct {
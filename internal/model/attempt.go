@@ -0,0 +1,240 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Policies deciding which attempts of a submission count towards its final grade
+const (
+	AttemptPolicyLast    = "last"    // the latest attempt counts, the submission is ungraded until it is graded
+	AttemptPolicyBest    = "best"    // the highest graded attempt counts
+	AttemptPolicyAverage = "average" // the average of the graded attempts counts
+)
+
+// SubmissionAttempt is a snapshot of a Submission each time the student submits it.
+// The content and files of an attempt never change, only its grading fields do.
+type SubmissionAttempt struct {
+	ID           uint             `json:"id" gorm:"primaryKey"`
+	SubmissionID uint             `json:"submission_id" gorm:"not null;uniqueIndex:idx_submission_attempt_number"`
+	Number       int              `json:"number" gorm:"not null;uniqueIndex:idx_submission_attempt_number"`
+	Content      string           `json:"content" gorm:"not null"`
+	SubmittedAt  time.Time        `json:"submitted_at"`
+	Late         bool             `json:"late"`
+	DaysLate     int              `json:"days_late,omitempty"`
+	LatePenalty  float64          `json:"late_penalty,omitempty"`
+	Grade        uint             `json:"grade"`
+	Feedback     string           `json:"feedback"`
+	Status       string           `json:"status" gorm:"not null;default:submitted"`
+	GradedAt     *time.Time       `json:"graded_at,omitempty"`
+	GradedBy     string           `json:"graded_by,omitempty"`
+	RubricID     *uint            `json:"rubric_id,omitempty"`
+	Scores       []CriterionScore `json:"criteria,omitempty" gorm:"serializer:json;type:jsonb"`
+	Files        []SubmissionFile `gorm:"many2many:submission_attempt_files" json:"files"`
+}
+
+// IsGraded reports whether the attempt has a grade, which may be 0
+func (a *SubmissionAttempt) IsGraded() bool {
+	switch a.Status {
+	case SubmissionStatusGraded, SubmissionStatusReturned, SubmissionStatusRegraded:
+		return true
+	}
+	return false
+}
+
+// NewAttempt snapshots the submission as its attempt number
+func (s *Submission) NewAttempt(number int) *SubmissionAttempt {
+	return &SubmissionAttempt{
+		SubmissionID: s.ID,
+		Number:       number,
+		Content:      s.Content,
+		SubmittedAt:  s.SubmittedAt,
+		Late:         s.Late,
+		DaysLate:     s.DaysLate,
+		Status:       s.Status,
+		Files:        s.Files,
+	}
+}
+
+// CopyGrade copies the grading fields of the submission to the attempt
+func (a *SubmissionAttempt) CopyGrade(submission *Submission) {
+	a.Grade = submission.Grade
+	a.LatePenalty = submission.LatePenalty
+	a.Feedback = submission.Feedback
+	a.Status = submission.Status
+	a.GradedAt = submission.GradedAt
+	a.GradedBy = submission.GradedBy
+	a.RubricID = submission.RubricID
+	a.Scores = submission.Scores
+}
+
+// FinalGrade returns the grade that counts for the submission with the given attempts, sorted by number,
+// following the attempt policy of the assignment, or nil when no counting attempt is graded
+func (a *Assignment) FinalGrade(attempts []SubmissionAttempt) *uint {
+	if len(attempts) == 0 {
+		return nil
+	}
+
+	switch a.AttemptPolicy {
+	case AttemptPolicyBest, AttemptPolicyAverage:
+		var best, total uint
+		graded := 0
+		for _, attempt := range attempts {
+			if !attempt.IsGraded() {
+				continue
+			}
+			best = max(best, attempt.Grade)
+			total += attempt.Grade
+			graded++
+		}
+		if graded == 0 {
+			return nil
+		}
+		if a.AttemptPolicy == AttemptPolicyBest {
+			return &best
+		}
+		average := uint(math.Round(float64(total) / float64(graded)))
+		return &average
+	default:
+		last := attempts[len(attempts)-1]
+		if !last.IsGraded() {
+			return nil
+		}
+		return &last.Grade
+	}
+}
+
+// AttemptsLeft returns how many more attempts can be submitted after count attempts, or -1 when unlimited
+func (a *Assignment) AttemptsLeft(count int) int {
+	if a.MaxAttempts <= 0 {
+		return -1
+	}
+	return max(0, a.MaxAttempts-count)
+}
+
+// Line operations of an AttemptDiff
+const (
+	DiffEqual   = "equal"
+	DiffInsert  = "insert"
+	DiffDelete  = "delete"
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// maxDiffCells bounds the size of the table used to diff the content of two attempts,
+// about 2 MB, since any member of the course can request diffs repeatedly
+const maxDiffCells = 250_000
+
+// DiffLine is a line of the content of an attempt and whether it was kept, inserted or deleted
+type DiffLine struct {
+	Op   string `json:"op" example:"insert"`
+	Text string `json:"text"`
+}
+
+// FileDiff tells whether a file was added, removed or changed between two attempts
+type FileDiff struct {
+	Name string `json:"name"`
+	Op   string `json:"op" example:"changed"`
+}
+
+// AttemptDiff are the changes between two attempts of a submission
+type AttemptDiff struct {
+	From    int        `json:"from"`
+	To      int        `json:"to"`
+	Content []DiffLine `json:"content"`
+	Files   []FileDiff `json:"files"`
+}
+
+// DiffAttempts compares the content, line by line, and the files, by name and digest, of two attempts
+func DiffAttempts(from, to *SubmissionAttempt) AttemptDiff {
+	diff := AttemptDiff{
+		From:    from.Number,
+		To:      to.Number,
+		Content: diffLines(splitLines(from.Content), splitLines(to.Content)),
+		Files:   []FileDiff{},
+	}
+
+	previous := make(map[string]string, len(from.Files))
+	for _, file := range from.Files {
+		previous[file.Name] = file.SHA256
+	}
+	for _, file := range to.Files {
+		digest, ok := previous[file.Name]
+		switch {
+		case !ok:
+			diff.Files = append(diff.Files, FileDiff{Name: file.Name, Op: DiffAdded})
+		case digest != file.SHA256:
+			diff.Files = append(diff.Files, FileDiff{Name: file.Name, Op: DiffChanged})
+		}
+		delete(previous, file.Name)
+	}
+	for _, file := range from.Files {
+		if _, ok := previous[file.Name]; ok {
+			diff.Files = append(diff.Files, FileDiff{Name: file.Name, Op: DiffRemoved})
+		}
+	}
+
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the shortest edit between two texts using their longest common subsequence.
+// Texts too long to compare line by line are shown as fully replaced.
+func diffLines(from, to []string) []DiffLine {
+	lines := []DiffLine{}
+	if (len(from)+1)*(len(to)+1) > maxDiffCells {
+		for _, text := range from {
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: text})
+		}
+		for _, text := range to {
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: text})
+		}
+		return lines
+	}
+
+	// common[i][j] is the length of the longest common subsequence of from[i:] and to[j:]
+	common := make([][]int, len(from)+1)
+	for i := range common {
+		common[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: from[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: from[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		lines = append(lines, DiffLine{Op: DiffDelete, Text: from[i]})
+	}
+	for ; j < len(to); j++ {
+		lines = append(lines, DiffLine{Op: DiffInsert, Text: to[j]})
+	}
+	return lines
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttemptPolicy_FinalGrade(t *testing.T) {
+	attempts := []SubmissionAttempt{
+		{Number: 1, Grade: 90, Status: SubmissionStatusGraded},
+		{Number: 2, Grade: 0, Status: SubmissionStatusReturned},
+		{Number: 3, Status: SubmissionStatusSubmitted},
+	}
+
+	best := (&Assignment{AttemptPolicy: AttemptPolicyBest}).FinalGrade(attempts)
+	require.NotNil(t, best)
+	assert.Equal(t, uint(90), *best)
+
+	average := (&Assignment{AttemptPolicy: AttemptPolicyAverage}).FinalGrade(attempts)
+	require.NotNil(t, average)
+	assert.Equal(t, uint(45), *average)
+
+	last := &Assignment{AttemptPolicy: AttemptPolicyLast}
+	assert.Nil(t, last.FinalGrade(attempts))
+	assert.Equal(t, uint(0), *last.FinalGrade(attempts[:2]))
+	assert.Nil(t, last.FinalGrade(nil))
+}
+
+func TestAttemptPolicy_AttemptsLeft(t *testing.T) {
+	assert.Equal(t, -1, (&Assignment{}).AttemptsLeft(5))
+	assert.Equal(t, 1, (&Assignment{MaxAttempts: 3}).AttemptsLeft(2))
+	assert.Equal(t, 0, (&Assignment{MaxAttempts: 3}).AttemptsLeft(3))
+}
+
+func TestDiffAttempts(t *testing.T) {
+	from := &SubmissionAttempt{Number: 1, Content: "a\nb\nc\n", Files: []SubmissionFile{
+		{Name: "main.go", FileContent: FileContent{SHA256: "a1"}},
+		{Name: "notes.txt", FileContent: FileContent{SHA256: "b2"}},
+	}}
+	to := &SubmissionAttempt{Number: 2, Content: "a\nc\nd\n", Files: []SubmissionFile{
+		{Name: "main.go", FileContent: FileContent{SHA256: "c3"}},
+		{Name: "README.md", FileContent: FileContent{SHA256: "d4"}},
+	}}
+
+	diff := DiffAttempts(from, to)
+	assert.Equal(t, []DiffLine{
+		{Op: DiffEqual, Text: "a"},
+		{Op: DiffDelete, Text: "b"},
+		{Op: DiffEqual, Text: "c"},
+		{Op: DiffInsert, Text: "d"},
+	}, diff.Content)
+	assert.Equal(t, []FileDiff{
+		{Name: "main.go", Op: DiffChanged},
+		{Name: "README.md", Op: DiffAdded},
+		{Name: "notes.txt", Op: DiffRemoved},
+	}, diff.Files)
+}
+
+func TestDiffAttempts_TooLarge(t *testing.T) {
+	from := &SubmissionAttempt{Number: 1, Content: strings.Repeat("a\n", 500) + "b\n"}
+	to := &SubmissionAttempt{Number: 2, Content: strings.Repeat("a\n", 500) + "c\n"}
+
+	diff := DiffAttempts(from, to)
+	assert.Len(t, diff.Content, 1002)
+	for i, line := range diff.Content {
+		if i < 501 {
+			assert.Equal(t, DiffDelete, line.Op)
+		} else {
+			assert.Equal(t, DiffInsert, line.Op)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// CalendarFeed is a calendar feed a user subscribed to. Calendar apps cannot send a Bearer token, so the feed
+// is read with a secret token in its URL instead.
+type CalendarFeed struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Token     string    `json:"-" gorm:"not null;uniqueIndex"`
+	UserID    string    `json:"user_id" gorm:"not null;uniqueIndex:idx_calendar_feeds_user_course"`
+	UserEmail string    `json:"-" gorm:"not null"`                                                              // courses are taught by email
+	CourseID  uint      `json:"course_id" gorm:"not null;default:0;uniqueIndex:idx_calendar_feeds_user_course"` // 0 for every course of the user
+	URL       string    `json:"url" gorm:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// certificatePayloadVersion identifies how the payload of certificates is built, so it can change without
+// breaking the certificates already issued
+const certificatePayloadVersion = "classconnect-certificate-v1"
+
+// Certificate is the completion certificate issued to a student who approved a course
+type Certificate struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	UserID      string    `json:"user_id" gorm:"not null;uniqueIndex:idx_certificates_user_course"`
+	CourseID    uint      `json:"course_id" gorm:"not null;uniqueIndex:idx_certificates_user_course;index"`
+	CourseTitle string    `json:"course_title" gorm:"not null"`
+	StudentName string    `json:"student_name" gorm:"not null"`
+	Teacher     string    `json:"teacher" gorm:"not null"`
+	IssuedAt    time.Time `json:"issued_at" gorm:"not null"`
+	Signature   string    `json:"signature" gorm:"not null"` // Ed25519 signature of the payload, in unpadded base64url
+}
+
+// Payload returns the bytes signed for the certificate. Every field but the signature is covered, and the
+// issue date is taken in UTC with second precision, as stored.
+func (c *Certificate) Payload() []byte {
+	payload, _ := json.Marshal([]string{
+		certificatePayloadVersion,
+		c.ID,
+		c.UserID,
+		strconv.FormatUint(uint64(c.CourseID), 10),
+		c.CourseTitle,
+		c.StudentName,
+		c.Teacher,
+		c.IssuedAt.UTC().Format(time.RFC3339),
+	})
+	return payload
+}
+
+// CertificateVerification tells whether a certificate is genuine
+type CertificateVerification struct {
+	Valid       bool         `json:"valid"`
+	Certificate *Certificate `json:"certificate"` // only set when the certificate is genuine
+}
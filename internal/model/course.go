@@ -10,12 +10,17 @@ import (
 // Course represents a course in the system
 type Course struct {
 	gorm.Model
-	Title               string         `json:"title"`
-	Description         string         `json:"description"`
-	CreatedBy           string         `json:"created_by"`
-	Capacity            int            `json:"capacity"`
-	StartDate           time.Time      `json:"start_date"`
-	EndDate             time.Time      `json:"end_date"`
-	EligibilityCriteria pq.StringArray `json:"eligibility_criteria" gorm:"type:text[]"`
-	TeachingAssistants  pq.StringArray `json:"teaching_assistants" gorm:"type:text[]"` // List of TA user IDs
+	Title              string         `json:"title"`
+	Description        string         `json:"description"`
+	CreatedBy          string         `json:"created_by"`
+	Capacity           int            `json:"capacity"`
+	StartDate          time.Time      `json:"start_date"`
+	EndDate            time.Time      `json:"end_date"`
+	State              string         `json:"state" gorm:"not null;default:enrollment_open;index"`
+	IsTemplate         bool           `json:"is_template" gorm:"not null;default:false"` // other teachers can create courses from it
+	ClonedFromID       *uint          `json:"cloned_from_id"`                            // the course or template it was cloned from
+	TeachingAssistants pq.StringArray `json:"teaching_assistants" gorm:"type:text[]"`    // List of TA user IDs
+
+	// Associations
+	Prerequisites []CoursePrerequisite `json:"-" gorm:"foreignKey:CourseID"`
 }
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// CloneCourseRequest creates a draft course with the content of another one: its modules, resources,
+// rubrics and assignments, with their deadlines moved along with the start date
+type CloneCourseRequest struct {
+	Title     string    `json:"title" example:"Introduction to Programming 2025"` // the title of the source course when empty
+	StartDate time.Time `json:"start_date" binding:"required" example:"2025-03-10T00:00:00Z"`
+	Capacity  int       `json:"capacity" binding:"gte=0" example:"30"` // the capacity of the source course when 0
+}
+
+// ToModel returns the draft course cloned from source for a teacher. It lasts as long as the source course
+// and requires the same courses. Teaching assistants are kept only when keepStaff is set.
+func (r *CloneCourseRequest) ToModel(source *Course, createdBy string, keepStaff bool) *Course {
+	clone := &Course{
+		Title:         source.Title,
+		Description:   source.Description,
+		CreatedBy:     createdBy,
+		Capacity:      source.Capacity,
+		StartDate:     r.StartDate,
+		EndDate:       r.StartDate.Add(source.EndDate.Sub(source.StartDate)),
+		State:         CourseStateDraft,
+		ClonedFromID:  &source.ID,
+		Prerequisites: PrerequisitesFromGroups(source.PrerequisiteGroups()),
+	}
+	if r.Title != "" {
+		clone.Title = r.Title
+	}
+	if r.Capacity > 0 {
+		clone.Capacity = r.Capacity
+	}
+	if keepStaff {
+		clone.TeachingAssistants = append([]string{}, source.TeachingAssistants...)
+	}
+	return clone
+}
+
+// DeadlineShift returns how much the deadlines of the source course move in the clone
+func (c *Course) DeadlineShift(source *Course) time.Duration {
+	return c.StartDate.Sub(source.StartDate)
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneCourseRequest_ToModel(t *testing.T) {
+	start := time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC)
+	source := &Course{
+		Title:              "Algoritmos I",
+		Capacity:           40,
+		StartDate:          start,
+		EndDate:            start.AddDate(0, 4, 0),
+		State:              CourseStateArchived,
+		IsTemplate:         true,
+		TeachingAssistants: []string{"ta@example.com"},
+		Prerequisites:      PrerequisitesFromGroups([][]uint{{3, 4}, {5}}),
+	}
+	source.ID = 7
+
+	newStart := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	req := &CloneCourseRequest{StartDate: newStart}
+	clone := req.ToModel(source, "teacher@example.com", true)
+
+	assert.Equal(t, "Algoritmos I", clone.Title)
+	assert.Equal(t, 40, clone.Capacity)
+	assert.Equal(t, "teacher@example.com", clone.CreatedBy)
+	assert.Equal(t, CourseStateDraft, clone.State)
+	assert.False(t, clone.IsTemplate)
+	assert.Equal(t, uint(7), *clone.ClonedFromID)
+	assert.Equal(t, source.EndDate.Sub(source.StartDate), clone.EndDate.Sub(clone.StartDate), "keeps the length of the course")
+	assert.Equal(t, [][]uint{{3, 4}, {5}}, clone.PrerequisiteGroups())
+	assert.Equal(t, []string{"ta@example.com"}, []string(clone.TeachingAssistants))
+
+	deadline := start.AddDate(0, 0, 15)
+	assert.Equal(t, newStart.AddDate(0, 0, 15), deadline.Add(clone.DeadlineShift(source)))
+
+	req = &CloneCourseRequest{Title: "Algoritmos I - 2025", StartDate: newStart, Capacity: 60}
+	clone = req.ToModel(source, "other@example.com", false)
+	assert.Equal(t, "Algoritmos I - 2025", clone.Title)
+	assert.Equal(t, 60, clone.Capacity)
+	assert.Empty(t, clone.TeachingAssistants, "the staff of a template is not copied")
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// CourseSearchQuery searches the course catalog. Dates are in RFC 3339 format.
+type CourseSearchQuery struct {
+	Query     string    `form:"q" binding:"required"` // words searched in titles, descriptions, modules and resources, also as prefixes
+	StartFrom time.Time `form:"start_from"`           // courses starting at or after
+	StartTo   time.Time `form:"start_to"`             // courses starting at or before
+	HasSeats  bool      `form:"has_seats"`            // only courses with free seats or no capacity limit
+	Eligible  bool      `form:"eligible"`             // only courses whose eligibility criteria the user has approved
+}
+
+// CourseSearchResult is a course found by a search, with how well it matches and the matching text
+// highlighted between <mark> tags. The rest of the highlighted text is HTML escaped.
+type CourseSearchResult struct {
+	Course
+	Rank                 float64
+	TitleHighlight       string
+	DescriptionHighlight string
+}
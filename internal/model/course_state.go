@@ -0,0 +1,68 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// States of the lifecycle of a course
+const (
+	CourseStateDraft          = "draft"           // being prepared, hidden from the catalog
+	CourseStatePublished      = "published"       // in the catalog, enrollment not open yet
+	CourseStateEnrollmentOpen = "enrollment_open" // students can enroll
+	CourseStateInProgress     = "in_progress"     // being taught, enrollment closed
+	CourseStateArchived       = "archived"        // finished and read-only
+)
+
+// CatalogStates are the states of the courses listed in the catalog search
+var CatalogStates = []string{CourseStatePublished, CourseStateEnrollmentOpen, CourseStateInProgress}
+
+// courseStateTransitions lists the states a course can move to from each state. Archived courses are final.
+var courseStateTransitions = map[string][]string{
+	CourseStateDraft:          {CourseStatePublished, CourseStateArchived},
+	CourseStatePublished:      {CourseStateDraft, CourseStateEnrollmentOpen, CourseStateArchived},
+	CourseStateEnrollmentOpen: {CourseStatePublished, CourseStateInProgress, CourseStateArchived},
+	CourseStateInProgress:     {CourseStateEnrollmentOpen, CourseStateArchived},
+}
+
+// CanTransition reports whether a course can move from one state to another
+func CanTransition(from, to string) bool {
+	return slices.Contains(courseStateTransitions[from], to)
+}
+
+// AcceptsEnrollments reports whether students can enroll in the course or join its waitlist
+func (c *Course) AcceptsEnrollments() bool {
+	return c.State == CourseStateEnrollmentOpen
+}
+
+// IsArchived reports whether the course is archived and so read-only
+func (c *Course) IsArchived() bool {
+	return c.State == CourseStateArchived
+}
+
+// Statuses of a scheduled change of state
+const (
+	ScheduleStatusPending = "pending"
+	ScheduleStatusApplied = "applied"
+	ScheduleStatusFailed  = "failed" // the transition was not allowed when it was due
+)
+
+// CourseStateSchedule is a change of state of a course scheduled for later, applied by the course state scheduler.
+// The transition is checked when it is due, against the state the course has then.
+type CourseStateSchedule struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	CourseID    uint       `json:"course_id" gorm:"not null;index"`
+	State       string     `json:"state" gorm:"not null"`
+	RunAt       time.Time  `json:"run_at" gorm:"not null"`
+	Status      string     `json:"status" gorm:"not null;default:pending"`
+	Error       string     `json:"error,omitempty"` // why a failed change was not applied
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
+
+// ChangeCourseStateRequest moves a course to another state, now or at a later time
+type ChangeCourseStateRequest struct {
+	State string     `json:"state" binding:"required,oneof=draft published enrollment_open in_progress archived" example:"enrollment_open"`
+	At    *time.Time `json:"at" example:"2025-03-01T09:00:00Z"` // schedules the change, applied now when omitted or past
+}
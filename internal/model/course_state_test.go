@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, CanTransition(CourseStateDraft, CourseStatePublished))
+	assert.True(t, CanTransition(CourseStatePublished, CourseStateEnrollmentOpen))
+	assert.True(t, CanTransition(CourseStateEnrollmentOpen, CourseStateInProgress))
+	assert.True(t, CanTransition(CourseStateInProgress, CourseStateEnrollmentOpen), "enrollment can be reopened")
+	assert.True(t, CanTransition(CourseStateInProgress, CourseStateArchived))
+
+	assert.False(t, CanTransition(CourseStateDraft, CourseStateEnrollmentOpen), "drafts are published first")
+	assert.False(t, CanTransition(CourseStateInProgress, CourseStateDraft))
+	assert.False(t, CanTransition(CourseStatePublished, CourseStatePublished))
+	assert.False(t, CanTransition(CourseStateArchived, CourseStateDraft), "archived courses are final")
+	assert.False(t, CanTransition("unknown", CourseStatePublished))
+}
+
+func TestCreateCourseRequest_DefaultState(t *testing.T) {
+	assert.Equal(t, CourseStateEnrollmentOpen, (&CreateCourseRequest{}).ToModel().State)
+	assert.Equal(t, CourseStateDraft, (&CreateCourseRequest{State: CourseStateDraft}).ToModel().State)
+	assert.True(t, (&Course{State: CourseStateEnrollmentOpen}).AcceptsEnrollments())
+	assert.False(t, (&Course{State: CourseStatePublished}).AcceptsEnrollments())
+}
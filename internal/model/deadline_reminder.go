@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// DeadlineReminder records that a student was reminded of the deadline of an assignment, once per offset
+type DeadlineReminder struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	AssignmentID  uint      `json:"assignment_id" gorm:"not null;uniqueIndex:idx_deadline_reminders_user_offset"`
+	UserID        string    `json:"user_id" gorm:"not null;uniqueIndex:idx_deadline_reminders_user_offset"`
+	OffsetMinutes int       `json:"offset_minutes" gorm:"not null;uniqueIndex:idx_deadline_reminders_user_offset"` // how long before the deadline
+	SentAt        time.Time `json:"sent_at"`
+}
+
+// DueAssignment is an assignment whose deadline is close, with the course it belongs to
+type DueAssignment struct {
+	AssignmentID uint
+	Title        string
+	Deadline     time.Time
+	CourseID     uint
+	CourseTitle  string
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Statuses of a GradeDraft
+const (
+	GradeDraftStatusDraft    = "draft"    // suggested by the AI, waiting for the teacher
+	GradeDraftStatusAccepted = "accepted" // accepted, possibly edited, and applied to the submission
+)
+
+// GradeDraft is an AI generated grade for a submission that only takes effect once a teacher accepts it.
+// Each submission keeps its latest draft.
+type GradeDraft struct {
+	ID           uint             `json:"id" gorm:"primaryKey"`
+	SubmissionID uint             `json:"submission_id" gorm:"not null;uniqueIndex"`
+	RubricID     *uint            `json:"rubric_id"`
+	Scores       []CriterionScore `json:"criteria" gorm:"serializer:json;type:jsonb"`
+	Grade        uint             `json:"grade"`
+	Feedback     string           `json:"feedback"`
+	Provider     string           `json:"provider"`
+	Status       string           `json:"status" gorm:"not null;default:draft"`
+	AcceptedBy   string           `json:"accepted_by,omitempty"`
+	AcceptedAt   *time.Time       `json:"accepted_at,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+
+	// Associations
+	Submission Submission `gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE" json:"-"`
+}
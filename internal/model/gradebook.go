@@ -0,0 +1,217 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultAssignmentPoints is what an assignment is worth when it sets no points
+const DefaultAssignmentPoints = 100
+
+// GradeCategory groups the assignments of a course that weigh the same in the final grade, such as
+// homework or exams
+type GradeCategory struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	CourseID   uint      `json:"course_id" gorm:"not null;index"`
+	Name       string    `json:"name" gorm:"not null"`
+	Weight     float64   `json:"weight" gorm:"not null"`                // relative to the weights of the other categories
+	DropLowest int       `json:"drop_lowest" gorm:"not null;default:0"` // lowest grades of the category left out
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GradeCategoryRequest creates or replaces a category of the gradebook of a course
+type GradeCategoryRequest struct {
+	Name       string  `json:"name" binding:"required" example:"Exams"`
+	Weight     float64 `json:"weight" binding:"gt=0" example:"60"`
+	DropLowest int     `json:"drop_lowest" binding:"gte=0" example:"1"`
+}
+
+// ApplyTo sets the fields of the request in a category
+func (r *GradeCategoryRequest) ApplyTo(category *GradeCategory) {
+	category.Name = r.Name
+	category.Weight = r.Weight
+	category.DropLowest = r.DropLowest
+}
+
+// Statuses of an assignment in the gradebook of a student
+const (
+	GradeStatusGraded  = "graded"  // counts with the grade of the submission
+	GradeStatusMissing = "missing" // not submitted by the deadline, counts as 0
+	GradeStatusPending = "pending" // not graded yet or not due yet, does not count
+)
+
+// GradebookEntry is how an assignment counts in the grade of a student
+type GradebookEntry struct {
+	AssignmentID uint     `json:"assignment_id"`
+	Title        string   `json:"title"`
+	CategoryID   *uint    `json:"category_id"`
+	Points       float64  `json:"points"`
+	Status       string   `json:"status"`
+	Grade        *uint    `json:"grade"`   // from 0 to 100, the grade that counts of the submission
+	Earned       *float64 `json:"earned"`  // points earned, nil while pending
+	Dropped      bool     `json:"dropped"` // left out by the drop lowest rule of its category
+}
+
+// CategoryGrade is the grade of a student in a category of the gradebook
+type CategoryGrade struct {
+	CategoryID *uint    `json:"category_id"` // nil for the assignments without category
+	Name       string   `json:"name"`
+	Weight     float64  `json:"weight"`
+	Earned     float64  `json:"earned"`
+	Possible   float64  `json:"possible"`
+	Percentage *float64 `json:"percentage"` // from 0 to 100, nil until an assignment of the category counts
+}
+
+// StudentGrade is the gradebook of a student: the final grade of the course and how each category and
+// assignment counts towards it
+type StudentGrade struct {
+	UserID      string           `json:"user_id"`
+	FinalGrade  *float64         `json:"final_grade"` // from 0 to 100, nil until an assignment counts
+	Categories  []CategoryGrade  `json:"categories"`
+	Assignments []GradebookEntry `json:"assignments"`
+}
+
+// Gradebook is the gradebook of every student of a course
+type Gradebook struct {
+	Categories []GradeCategory `json:"categories"`
+	Students   []StudentGrade  `json:"students"`
+}
+
+// uncategorizedName is the name of the group of the assignments without category
+const uncategorizedName = "Uncategorized"
+
+// ComputeStudentGrade computes the final grade of a student from the submissions of the student by assignment ID.
+// Each category scores the points earned over the points possible of its graded and missing assignments,
+// after dropping its lowest grades, and the final grade is the weighted average of the categories with
+// assignments that count. Courses without categories score the points of every assignment together, while
+// in courses with categories the assignments without one do not count.
+func ComputeStudentGrade(userID string, categories []GradeCategory, assignments []AssignmentPreview, submissions map[uint]*Submission, now time.Time) StudentGrade {
+	assignments = append([]AssignmentPreview{}, assignments...)
+	sort.SliceStable(assignments, func(i, j int) bool {
+		if !assignments[i].Deadline.Equal(assignments[j].Deadline) {
+			return assignments[i].Deadline.Before(assignments[j].Deadline)
+		}
+		return assignments[i].ID < assignments[j].ID
+	})
+
+	groups := make([]CategoryGrade, 0, len(categories)+1)
+	index := make(map[uint]int, len(categories))
+	for _, category := range categories {
+		index[category.ID] = len(groups)
+		groups = append(groups, CategoryGrade{CategoryID: &category.ID, Name: category.Name, Weight: category.Weight})
+	}
+	uncategorized := len(groups)
+	groups = append(groups, CategoryGrade{Name: uncategorizedName})
+	if len(categories) == 0 {
+		groups[uncategorized].Weight = 1
+	}
+
+	entries := make([]GradebookEntry, len(assignments))
+	members := make([][]int, len(groups))
+	for i, assignment := range assignments {
+		entries[i] = gradebookEntry(assignment, submissions[assignment.ID], now)
+		group := uncategorized
+		if assignment.CategoryID != nil {
+			if j, ok := index[*assignment.CategoryID]; ok {
+				group = j
+			}
+		}
+		if entries[i].Earned != nil {
+			members[group] = append(members[group], i)
+		}
+	}
+
+	for i, category := range categories {
+		dropLowest(entries, members[i], category.DropLowest)
+	}
+
+	var weighted, totalWeight float64
+	for i := range groups {
+		for _, j := range members[i] {
+			if entries[j].Dropped {
+				continue
+			}
+			groups[i].Earned += *entries[j].Earned
+			groups[i].Possible += entries[j].Points
+		}
+		if groups[i].Possible == 0 {
+			continue
+		}
+		percentage := roundGrade(groups[i].Earned / groups[i].Possible * 100)
+		groups[i].Percentage = &percentage
+		weighted += groups[i].Weight * percentage
+		totalWeight += groups[i].Weight
+	}
+
+	grade := StudentGrade{UserID: userID, Assignments: entries}
+	// The uncategorized group is only listed when it has assignments
+	grade.Categories = groups
+	if len(categories) > 0 && !hasUncategorized(assignments, index) {
+		grade.Categories = groups[:uncategorized]
+	}
+	if totalWeight > 0 {
+		final := roundGrade(weighted / totalWeight)
+		grade.FinalGrade = &final
+	}
+	return grade
+}
+
+func gradebookEntry(assignment AssignmentPreview, submission *Submission, now time.Time) GradebookEntry {
+	entry := GradebookEntry{
+		AssignmentID: assignment.ID,
+		Title:        assignment.Title,
+		CategoryID:   assignment.CategoryID,
+		Points:       assignment.Points,
+		Status:       GradeStatusPending,
+	}
+	if entry.Points <= 0 {
+		entry.Points = DefaultAssignmentPoints
+	}
+
+	switch {
+	case submission != nil && submission.FinalGrade != nil:
+		earned := roundGrade(float64(*submission.FinalGrade) / 100 * entry.Points)
+		entry.Status = GradeStatusGraded
+		entry.Grade = submission.FinalGrade
+		entry.Earned = &earned
+	case submission == nil && !assignment.Deadline.IsZero() && now.After(assignment.Deadline):
+		earned := 0.0
+		entry.Status = GradeStatusMissing
+		entry.Earned = &earned
+	}
+	return entry
+}
+
+// dropLowest marks the count lowest scoring entries of a category as dropped, always keeping one
+func dropLowest(entries []GradebookEntry, members []int, count int) {
+	count = min(count, len(members)-1)
+	if count <= 0 {
+		return
+	}
+	lowest := append([]int{}, members...)
+	sort.SliceStable(lowest, func(i, j int) bool {
+		return *entries[lowest[i]].Earned/entries[lowest[i]].Points < *entries[lowest[j]].Earned/entries[lowest[j]].Points
+	})
+	for _, i := range lowest[:count] {
+		entries[i].Dropped = true
+	}
+}
+
+func hasUncategorized(assignments []AssignmentPreview, categories map[uint]int) bool {
+	for _, assignment := range assignments {
+		if assignment.CategoryID == nil {
+			return true
+		}
+		if _, ok := categories[*assignment.CategoryID]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// roundGrade rounds a grade or a number of points to two decimals
+func roundGrade(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gradedSubmission(grade uint) *Submission {
+	return &Submission{FinalGrade: &grade}
+}
+
+func TestComputeStudentGrade_WeightsCategories(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	past, future := now.AddDate(0, 0, -7), now.AddDate(0, 0, 7)
+	homework, exams := uint(1), uint(2)
+
+	categories := []GradeCategory{
+		{ID: homework, Name: "Homework", Weight: 40, DropLowest: 1},
+		{ID: exams, Name: "Exams", Weight: 60},
+	}
+	assignments := []AssignmentPreview{
+		{ID: 10, Title: "TP1", Deadline: past, CategoryID: &homework, Points: 10},
+		{ID: 11, Title: "TP2", Deadline: past.AddDate(0, 0, 1), CategoryID: &homework, Points: 10},
+		{ID: 12, Title: "TP3", Deadline: past.AddDate(0, 0, 2), CategoryID: &homework, Points: 20},
+		{ID: 20, Title: "Parcial", Deadline: past, CategoryID: &exams, Points: 100},
+		{ID: 21, Title: "Final", Deadline: future, CategoryID: &exams, Points: 100},
+		{ID: 30, Title: "Extra", Deadline: past},
+	}
+	submissions := map[uint]*Submission{
+		10: gradedSubmission(50),
+		// 11 is missing and counts as 0, so the drop rule leaves it out
+		12: gradedSubmission(90),
+		20: gradedSubmission(70),
+		21: {}, // submitted early and not graded yet
+		30: gradedSubmission(100),
+	}
+
+	grade := ComputeStudentGrade("student", categories, assignments, submissions, now)
+
+	require.Len(t, grade.Assignments, 6)
+	byID := make(map[uint]GradebookEntry)
+	for _, entry := range grade.Assignments {
+		byID[entry.AssignmentID] = entry
+	}
+	assert.Equal(t, GradeStatusMissing, byID[11].Status)
+	assert.True(t, byID[11].Dropped)
+	assert.False(t, byID[10].Dropped)
+	assert.Equal(t, GradeStatusPending, byID[21].Status)
+	assert.Nil(t, byID[21].Earned)
+
+	// Homework: (5 + 18) / 30, exams: 70 / 100, extra does not count
+	require.Len(t, grade.Categories, 3)
+	assert.Equal(t, 76.67, *grade.Categories[0].Percentage)
+	assert.Equal(t, 70.0, *grade.Categories[1].Percentage)
+	assert.Equal(t, uncategorizedName, grade.Categories[2].Name)
+	assert.Zero(t, grade.Categories[2].Weight)
+	require.NotNil(t, grade.FinalGrade)
+	assert.Equal(t, 72.67, *grade.FinalGrade)
+}
+
+func TestComputeStudentGrade_WithoutCategories(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	assignments := []AssignmentPreview{
+		{ID: 1, Deadline: now.AddDate(0, 0, -2), Points: 30},
+		{ID: 2, Deadline: now.AddDate(0, 0, -1)},
+	}
+
+	grade := ComputeStudentGrade("student", nil, assignments, map[uint]*Submission{1: gradedSubmission(100)}, now)
+
+	// 30 points of 130, the assignment without points is worth 100
+	require.NotNil(t, grade.FinalGrade)
+	assert.Equal(t, 23.08, *grade.FinalGrade)
+	assert.Equal(t, float64(DefaultAssignmentPoints), grade.Assignments[1].Points)
+}
+
+func TestComputeStudentGrade_NothingCounts(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	categories := []GradeCategory{{ID: 1, Name: "Exams", Weight: 1, DropLowest: 2}}
+	category := uint(1)
+	assignments := []AssignmentPreview{{ID: 1, Deadline: now.AddDate(0, 0, 1), CategoryID: &category}}
+
+	grade := ComputeStudentGrade("student", categories, assignments, nil, now)
+
+	assert.Nil(t, grade.FinalGrade)
+	require.Len(t, grade.Categories, 1, "the uncategorized group is only listed when it has assignments")
+	assert.Nil(t, grade.Categories[0].Percentage)
+}
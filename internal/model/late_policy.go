@@ -0,0 +1,58 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// Late policies of an Assignment, applied to submissions made after the deadline and its grace period
+const (
+	LatePolicyAccept  = "accept"  // late submissions are accepted and flagged
+	LatePolicyReject  = "reject"  // late submissions are rejected
+	LatePolicyPenalty = "penalty" // late submissions lose a percentage of their grade per day late
+)
+
+// DaysLate returns the number of started days between the deadline and the submission time,
+// or 0 when the submission is within the grace period or the assignment has no deadline
+func (a *Assignment) DaysLate(submittedAt time.Time) int {
+	if a.Deadline.IsZero() {
+		return 0
+	}
+
+	graceDeadline := a.Deadline.Add(time.Duration(a.GracePeriod) * time.Minute)
+	if !submittedAt.After(graceDeadline) {
+		return 0
+	}
+
+	return int(math.Ceil(submittedAt.Sub(a.Deadline).Hours() / 24))
+}
+
+// SessionExpired reports whether a timed session started at startedAt is over at the given time
+func (a *Assignment) SessionExpired(startedAt time.Time, at time.Time) bool {
+	if a.TimeLimit <= 0 {
+		return false
+	}
+	return at.After(startedAt.Add(time.Duration(a.TimeLimit) * time.Minute))
+}
+
+// LatePenalty returns the percentage of the grade the submission loses for being late
+func (a *Assignment) LatePenalty(submission *Submission) float64 {
+	if a.LatePolicy != LatePolicyPenalty || !submission.Late {
+		return 0
+	}
+	return math.Min(100, float64(submission.DaysLate)*a.LatePenaltyPerDay)
+}
+
+// MarkSubmitted records the submission time and whether the submission is late
+func (s *Submission) MarkSubmitted(assignment *Assignment, submittedAt time.Time) {
+	s.SubmittedAt = submittedAt
+	s.DaysLate = assignment.DaysLate(submittedAt)
+	s.Late = s.DaysLate > 0
+	s.LatePenalty = 0
+}
+
+// ApplyGrade sets the grade of the submission, deducting the late penalty of the assignment
+func (s *Submission) ApplyGrade(grade uint, assignment *Assignment) {
+	s.LatePenalty = assignment.LatePenalty(s)
+	s.Grade = uint(math.Round(float64(grade) * (100 - s.LatePenalty) / 100))
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatePolicy_GracePeriodAndPenalty(t *testing.T) {
+	deadline := time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC)
+	assignment := &Assignment{Deadline: deadline, GracePeriod: 15, LatePolicy: LatePolicyPenalty, LatePenaltyPerDay: 10}
+
+	onTime := &Submission{}
+	onTime.MarkSubmitted(assignment, deadline.Add(10*time.Minute))
+	assert.False(t, onTime.Late)
+	onTime.ApplyGrade(80, assignment)
+	assert.Equal(t, uint(80), onTime.Grade)
+
+	late := &Submission{}
+	late.MarkSubmitted(assignment, deadline.Add(25*time.Hour))
+	assert.True(t, late.Late)
+	assert.Equal(t, 2, late.DaysLate)
+	late.ApplyGrade(80, assignment)
+	assert.Equal(t, 20.0, late.LatePenalty)
+	assert.Equal(t, uint(64), late.Grade)
+
+	assignment.LatePolicy = LatePolicyAccept
+	late.ApplyGrade(80, assignment)
+	assert.Equal(t, uint(80), late.Grade)
+}
+
+func TestLatePolicy_SessionExpiresAfterTimeLimit(t *testing.T) {
+	startedAt := time.Now()
+	assignment := &Assignment{TimeLimit: 30}
+
+	assert.False(t, assignment.SessionExpired(startedAt, startedAt.Add(29*time.Minute)))
+	assert.True(t, assignment.SessionExpired(startedAt, startedAt.Add(31*time.Minute)))
+	assert.False(t, (&Assignment{}).SessionExpired(startedAt, startedAt.Add(24*time.Hour)))
+}
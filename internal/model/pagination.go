@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// PageQuery selects a page of a list endpoint: up to Limit items after Cursor, in the order of Sort.
+// Sort is one of the sort keys of the list, prefixed with - for descending order.
+type PageQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Sort   string `form:"sort"`
+}
+
+// CourseFilter narrows the courses listed. Dates are in RFC 3339 format.
+type CourseFilter struct {
+	Title     string    `form:"title"`      // part of the title, case insensitive
+	StartFrom time.Time `form:"start_from"` // courses starting at or after
+	StartTo   time.Time `form:"start_to"`   // courses starting at or before
+	State     string    `form:"state" binding:"omitempty,oneof=draft published enrollment_open in_progress archived"`
+	Template  *bool     `form:"template"` // only templates, or only courses that are not templates
+}
+
+// SubmissionFilter narrows the submissions of an assignment listed. Dates are in RFC 3339 format.
+type SubmissionFilter struct {
+	Graded        *bool     `form:"graded"` // whether the grade that counts is set
+	Late          *bool     `form:"late"`
+	Status        string    `form:"status" binding:"omitempty,oneof=submitted graded returned regraded"`
+	SubmittedFrom time.Time `form:"submitted_from"`
+	SubmittedTo   time.Time `form:"submitted_to"`
+}
+
+// FeedbackFilter narrows the course or user feedbacks listed. Dates are in RFC 3339 format.
+type FeedbackFilter struct {
+	MinRating   int       `form:"min_rating" binding:"omitempty,gte=1,lte=5"`
+	MaxRating   int       `form:"max_rating" binding:"omitempty,gte=1,lte=5"`
+	CreatedFrom time.Time `form:"created_from"`
+	CreatedTo   time.Time `form:"created_to"`
+	CourseID    uint      `form:"course_id"` // only for the feedbacks of a user
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CoursePrerequisite is a course that satisfies a prerequisite group of another course when approved.
+// A course requires every one of its groups, and a group is met by approving any of its courses.
+type CoursePrerequisite struct {
+	ID               uint `json:"id" gorm:"primaryKey"`
+	CourseID         uint `json:"course_id" gorm:"not null;index"`
+	GroupNumber      int  `json:"group_number" gorm:"not null"`
+	RequiredCourseID uint `json:"required_course_id" gorm:"not null"`
+
+	// Associations
+	RequiredCourse Course `json:"-" gorm:"foreignKey:RequiredCourseID"`
+}
+
+// PrerequisitesFromGroups returns the prerequisites of a course from groups of course IDs,
+// such as [[1, 2], [3]] for (1 or 2) and 3. Empty groups are dropped.
+func PrerequisitesFromGroups(groups [][]uint) []CoursePrerequisite {
+	var prerequisites []CoursePrerequisite
+	number := 0
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		seen := make(map[uint]bool, len(group))
+		for _, courseID := range group {
+			if seen[courseID] {
+				continue
+			}
+			seen[courseID] = true
+			prerequisites = append(prerequisites, CoursePrerequisite{GroupNumber: number, RequiredCourseID: courseID})
+		}
+		number++
+	}
+	return prerequisites
+}
+
+// PrerequisiteGroups returns the prerequisites of the course as groups of course IDs
+func (c *Course) PrerequisiteGroups() [][]uint {
+	groups := [][]uint{}
+	index := make(map[int]int)
+	for _, prerequisite := range c.Prerequisites {
+		i, ok := index[prerequisite.GroupNumber]
+		if !ok {
+			i = len(groups)
+			index[prerequisite.GroupNumber] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], prerequisite.RequiredCourseID)
+	}
+	return groups
+}
+
+// FindPrerequisiteCycle returns the cycle that requiring courses would close for courseID, as the path
+// of course IDs from courseID back to itself, or nil if there is none. required maps each course to
+// the courses it currently requires.
+func FindPrerequisiteCycle(courseID uint, courses []uint, required map[uint][]uint) []uint {
+	visited := make(map[uint]bool)
+	var path []uint
+
+	var reaches func(from uint) bool
+	reaches = func(from uint) bool {
+		path = append(path, from)
+		if from == courseID {
+			return true
+		}
+		if !visited[from] {
+			visited[from] = true
+			for _, next := range required[from] {
+				if reaches(next) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	for _, course := range courses {
+		if reaches(course) {
+			return append([]uint{courseID}, path...)
+		}
+	}
+	return nil
+}
+
+// FormatCoursePath joins course IDs as a readable path, such as 1 -> 2 -> 1
+func FormatCoursePath(path []uint) string {
+	ids := make([]string, len(path))
+	for i, id := range path {
+		ids[i] = fmt.Sprint(id)
+	}
+	return strings.Join(ids, " -> ")
+}
+
+// Eligibility explains which prerequisites of a course a user meets
+type Eligibility struct {
+	CourseID     uint                `json:"course_id"`
+	Eligible     bool                `json:"eligible"`
+	Requirements []RequirementStatus `json:"requirements"` // one per group, every one must be met
+}
+
+// RequirementStatus is a prerequisite group, met by approving any of its courses
+type RequirementStatus struct {
+	Met   bool                 `json:"met"`
+	AnyOf []PrerequisiteCourse `json:"any_of"`
+}
+
+// PrerequisiteCourse is a course of a prerequisite group and whether the user approved it
+type PrerequisiteCourse struct {
+	CourseID uint   `json:"course_id"`
+	Title    string `json:"title"`
+	Approved bool   `json:"approved"`
+}
+
+// CheckEligibility compares the prerequisites of a course, with their required course loaded,
+// with the courses a user approved
+func CheckEligibility(courseID uint, prerequisites []CoursePrerequisite, approved map[uint]bool) Eligibility {
+	groups := make(map[int]*RequirementStatus)
+	var numbers []int
+	for _, prerequisite := range prerequisites {
+		group, ok := groups[prerequisite.GroupNumber]
+		if !ok {
+			group = &RequirementStatus{AnyOf: []PrerequisiteCourse{}}
+			groups[prerequisite.GroupNumber] = group
+			numbers = append(numbers, prerequisite.GroupNumber)
+		}
+		isApproved := approved[prerequisite.RequiredCourseID]
+		group.Met = group.Met || isApproved
+		group.AnyOf = append(group.AnyOf, PrerequisiteCourse{
+			CourseID: prerequisite.RequiredCourseID,
+			Title:    prerequisite.RequiredCourse.Title,
+			Approved: isApproved,
+		})
+	}
+	sort.Ints(numbers)
+
+	eligibility := Eligibility{CourseID: courseID, Eligible: true, Requirements: make([]RequirementStatus, 0, len(numbers))}
+	for _, number := range numbers {
+		group := groups[number]
+		eligibility.Eligible = eligibility.Eligible && group.Met
+		eligibility.Requirements = append(eligibility.Requirements, *group)
+	}
+	return eligibility
+}
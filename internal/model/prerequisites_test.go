@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrerequisiteGroups_RoundTrip(t *testing.T) {
+	course := &Course{Prerequisites: PrerequisitesFromGroups([][]uint{{1, 2, 1}, {}, {3}})}
+	assert.Equal(t, [][]uint{{1, 2}, {3}}, course.PrerequisiteGroups())
+	assert.Equal(t, [][]uint{}, (&Course{}).PrerequisiteGroups())
+}
+
+func TestFindPrerequisiteCycle(t *testing.T) {
+	// 2 requires 3, 3 requires 4 or 1
+	required := map[uint][]uint{2: {3}, 3: {4, 1}}
+
+	assert.Equal(t, []uint{1, 2, 3, 1}, FindPrerequisiteCycle(1, []uint{5, 2}, required))
+	assert.Equal(t, []uint{1, 1}, FindPrerequisiteCycle(1, []uint{1}, required), "a course requiring itself")
+	assert.Nil(t, FindPrerequisiteCycle(5, []uint{2}, required))
+	assert.Equal(t, []uint{4, 2, 3, 4}, FindPrerequisiteCycle(4, []uint{2}, required))
+	assert.Equal(t, "1 -> 2 -> 3 -> 1", FormatCoursePath([]uint{1, 2, 3, 1}))
+}
+
+func TestCheckEligibility(t *testing.T) {
+	prerequisites := []CoursePrerequisite{
+		{GroupNumber: 0, RequiredCourseID: 1, RequiredCourse: Course{Title: "Álgebra"}},
+		{GroupNumber: 0, RequiredCourseID: 2, RequiredCourse: Course{Title: "Análisis"}},
+		{GroupNumber: 1, RequiredCourseID: 3, RequiredCourse: Course{Title: "Algoritmos"}},
+	}
+
+	eligibility := CheckEligibility(9, prerequisites, map[uint]bool{2: true})
+	assert.False(t, eligibility.Eligible)
+	assert.Len(t, eligibility.Requirements, 2)
+	assert.True(t, eligibility.Requirements[0].Met)
+	assert.Equal(t, PrerequisiteCourse{CourseID: 2, Title: "Análisis", Approved: true}, eligibility.Requirements[0].AnyOf[1])
+	assert.False(t, eligibility.Requirements[1].Met)
+
+	assert.True(t, CheckEligibility(9, prerequisites, map[uint]bool{1: true, 3: true}).Eligible)
+	assert.True(t, CheckEligibility(9, nil, nil).Eligible)
+}
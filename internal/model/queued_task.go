@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Statuses of a QueuedTask
+const (
+	QueuedTaskStatusPending = "pending" // waiting to be claimed by a worker
+	QueuedTaskStatusRunning = "running" // claimed by a worker until LockedUntil
+	QueuedTaskStatusDead    = "dead"    // exhausted its attempts, kept for inspection and requeue
+)
+
+// QueuedTask is a background task persisted by the durable task queue.
+// Finished tasks are deleted, failed tasks are retried with exponential backoff
+// until MaxAttempts and then moved to the dead-letter state.
+// At most one pending task exists per DedupKey: enqueueing another one coalesces into it.
+// A task is not claimed while a task with one of the DependsOn keys is pending or running.
+type QueuedTask struct {
+	ID          string         `json:"id" gorm:"primaryKey"`
+	Type        string         `json:"type" gorm:"not null;index"`
+	Payload     []byte         `json:"payload" gorm:"type:jsonb"`
+	DedupKey    string         `json:"dedup_key" gorm:"index:idx_queued_tasks_pending_dedup_key,unique,where:status = 'pending' AND dedup_key <> ''"`
+	DependsOn   pq.StringArray `json:"depends_on" gorm:"type:text[]"`
+	Status      string         `json:"status" gorm:"not null;index:idx_queued_tasks_claim,priority:1"`
+	Attempts    int            `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int            `json:"max_attempts" gorm:"not null;default:3"`
+	AvailableAt time.Time      `json:"available_at" gorm:"not null;index:idx_queued_tasks_claim,priority:2"`
+	RunBy       *time.Time     `json:"run_by"` // latest available_at of a coalesced task, set by its first enqueue
+	LockedBy    string         `json:"locked_by"`
+	LockedUntil *time.Time     `json:"locked_until"`
+	LastError   string         `json:"last_error"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
@@ -5,37 +5,44 @@ import "time"
 // CreateCourseRequest represents the input for creating a course
 // @Description Request body for creating a course
 type CreateCourseRequest struct {
-	Title               string   `json:"title" binding:"required" example:"Introduction to Programming"`
-	Description         string   `json:"description" example:"Learn the basics of programming with Python"`
-	CreatedBy           string   `json:"created_by" binding:"required" example:"teacher123"`
-	Capacity            int      `json:"capacity" binding:"required,gte=1" example:"30"`
-	EligibilityCriteria []string `json:"eligibility_criteria" example:"[\"Computer Science Major\", \"Sophomore level or above\"]"`
-	TeachingAssistants  []string `json:"teaching_assistants" example:"[\"ta1@example.com\", \"ta2@example.com\"]"`
+	Title              string   `json:"title" binding:"required" example:"Introduction to Programming"`
+	Description        string   `json:"description" example:"Learn the basics of programming with Python"`
+	CreatedBy          string   `json:"created_by" binding:"required" example:"teacher123"`
+	Capacity           int      `json:"capacity" binding:"required,gte=1" example:"30"`
+	State              string   `json:"state" binding:"omitempty,oneof=draft published enrollment_open" example:"draft"` // enrollment_open by default
+	Prerequisites      [][]uint `json:"prerequisites"`                                                                   // groups of course IDs, such as [[1, 2], [3]] for (1 or 2) and 3
+	TeachingAssistants []string `json:"teaching_assistants" example:"[\"ta1@example.com\", \"ta2@example.com\"]"`
 }
 
 // ToModel converts API request to internal Course model
 func (r *CreateCourseRequest) ToModel() *Course {
+	state := r.State
+	if state == "" {
+		state = CourseStateEnrollmentOpen
+	}
 	return &Course{
-		Title:               r.Title,
-		Description:         r.Description,
-		CreatedBy:           r.CreatedBy,
-		Capacity:            r.Capacity,
-		StartDate:           time.Now(),
-		EndDate:             time.Now().AddDate(0, 4, 0), // 4 months by default
-		EligibilityCriteria: r.EligibilityCriteria,
-		TeachingAssistants:  r.TeachingAssistants,
+		Title:              r.Title,
+		Description:        r.Description,
+		CreatedBy:          r.CreatedBy,
+		Capacity:           r.Capacity,
+		StartDate:          time.Now(),
+		EndDate:            time.Now().AddDate(0, 4, 0), // 4 months by default
+		State:              state,
+		Prerequisites:      PrerequisitesFromGroups(r.Prerequisites),
+		TeachingAssistants: r.TeachingAssistants,
 	}
 }
 
 // UpdateCourseRequest represents the input for updating a course
 type UpdateCourseRequest struct {
-	Title               *string    `json:"title"`
-	Description         *string    `json:"description"`
-	Capacity            *int       `json:"capacity"`
-	StartDate           *time.Time `json:"start_date"`
-	EndDate             *time.Time `json:"end_date"`
-	EligibilityCriteria *[]string  `json:"eligibility_criteria"`
-	TeachingAssistants  *[]string  `json:"teaching_assistants"`
+	Title              *string    `json:"title"`
+	Description        *string    `json:"description"`
+	Capacity           *int       `json:"capacity"`
+	StartDate          *time.Time `json:"start_date"`
+	EndDate            *time.Time `json:"end_date"`
+	Prerequisites      *[][]uint  `json:"prerequisites"` // replaces the prerequisite groups, saved apart from the course
+	TeachingAssistants *[]string  `json:"teaching_assistants"`
+	IsTemplate         *bool      `json:"is_template"` // lets other teachers create courses from this one
 }
 
 // ApplyTo applies the update request to an existing course
@@ -55,28 +62,67 @@ func (r *UpdateCourseRequest) ApplyTo(course *Course) {
 	if r.EndDate != nil {
 		course.EndDate = *r.EndDate
 	}
-	if r.EligibilityCriteria != nil {
-		course.EligibilityCriteria = *r.EligibilityCriteria
-	}
 	if r.TeachingAssistants != nil {
 		course.TeachingAssistants = *r.TeachingAssistants // Apply TA updates
 	}
+	if r.IsTemplate != nil {
+		course.IsTemplate = *r.IsTemplate
+	}
 }
 
 type CreateAssignmentRequest struct {
-	Title       string    `json:"title" binding:"required"`
-	Description string    `json:"description"`
-	Deadline    time.Time `json:"deadline" binding:"required"`
-	TimeLimit   int       `json:"time_limit"` // in minutes
-	Files       []File    `json:"files"`      // Provisory: a file struct has content as binary data
+	Title             string         `json:"title" binding:"required"`
+	Description       string         `json:"description"`
+	Deadline          time.Time      `json:"deadline" binding:"required"`
+	TimeLimit         int            `json:"time_limit"`                                                                    // in minutes
+	LatePolicy        string         `json:"late_policy" binding:"omitempty,oneof=accept reject penalty" example:"penalty"` // accept by default
+	LatePenaltyPerDay float64        `json:"late_penalty_per_day" binding:"gte=0,lte=100" example:"10"`
+	GracePeriod       int            `json:"grace_period" binding:"gte=0" example:"15"`                                 // in minutes
+	MaxAttempts       int            `json:"max_attempts" binding:"gte=0" example:"3"`                                  // unlimited when 0
+	AttemptPolicy     string         `json:"attempt_policy" binding:"omitempty,oneof=last best average" example:"best"` // last by default
+	AllowedFileTypes  []string       `json:"allowed_file_types" example:"application/pdf,image/*,.py"`                  // MIME types, type/* wildcards or extensions
+	MaxFileSize       int64          `json:"max_file_size" binding:"gte=0" example:"10485760"`                          // in bytes, the service limit when 0
+	Files             []File         `json:"files"`                                                                     // Provisory: a file struct has content as binary data
+	Rubric            *RubricRequest `json:"rubric"`
+	RubricID          *uint          `json:"rubric_id"`                           // Attaches an existing rubric of the course instead of creating one
+	CategoryID        *uint          `json:"category_id"`                         // category of the gradebook it counts in
+	Points            float64        `json:"points" binding:"gte=0" example:"20"` // 100 by default
+	Mandatory         bool           `json:"mandatory"`                           // must be submitted to approve the course
 }
 
 type UpdateAssignmentRequest struct {
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Deadline    time.Time `json:"deadline"`
-	TimeLimit   int       `json:"time_limit"` // in minutes
-	Files       []File    `json:"files"`      // Provisory: a file struct has content as binary data
+	Title             string         `json:"title"`
+	Description       string         `json:"description"`
+	Deadline          time.Time      `json:"deadline"`
+	TimeLimit         int            `json:"time_limit"`                                                                    // in minutes
+	LatePolicy        string         `json:"late_policy" binding:"omitempty,oneof=accept reject penalty" example:"penalty"` // accept by default
+	LatePenaltyPerDay float64        `json:"late_penalty_per_day" binding:"gte=0,lte=100" example:"10"`
+	GracePeriod       int            `json:"grace_period" binding:"gte=0" example:"15"`                                 // in minutes
+	MaxAttempts       int            `json:"max_attempts" binding:"gte=0" example:"3"`                                  // unlimited when 0
+	AttemptPolicy     string         `json:"attempt_policy" binding:"omitempty,oneof=last best average" example:"best"` // last by default
+	AllowedFileTypes  []string       `json:"allowed_file_types" example:"application/pdf,image/*,.py"`                  // MIME types, type/* wildcards or extensions
+	MaxFileSize       int64          `json:"max_file_size" binding:"gte=0" example:"10485760"`                          // in bytes, the service limit when 0
+	Files             []File         `json:"files"`                                                                     // Provisory: a file struct has content as binary data
+	Rubric            *RubricRequest `json:"rubric"`                                                                    // Replaces the rubric of the assignment, kept when omitted
+	RubricID          *uint          `json:"rubric_id"`                                                                 // Attaches an existing rubric of the course instead of creating one
+	CategoryID        *uint          `json:"category_id"`                                                               // Category of the gradebook, kept when omitted
+	Points            float64        `json:"points" binding:"gte=0" example:"20"`                                       // kept when 0
+	Mandatory         *bool          `json:"mandatory"`                                                                 // kept when omitted
+}
+
+// RubricRequest represents the rubric used to grade an assignment
+type RubricRequest struct {
+	Title    string            `json:"title" example:"Programming assignment rubric"`
+	Criteria []RubricCriterion `json:"criteria" binding:"required"`
+}
+
+// ToModel converts API request to internal Rubric model
+func (r *RubricRequest) ToModel(courseID uint) *Rubric {
+	return &Rubric{
+		CourseID: courseID,
+		Title:    r.Title,
+		Criteria: r.Criteria,
+	}
 }
 
 type CreateSubmissionRequest struct {
@@ -91,6 +137,21 @@ type GradeSubmissionRequest struct {
 	Feedback string `json:"feedback"`
 }
 
+// RubricGradeSubmissionRequest represents the grading of a submission criterion by criterion.
+// The grade is computed from the scores with the rubric of the assignment.
+type RubricGradeSubmissionRequest struct {
+	Criteria []CriterionScore `json:"criteria" binding:"required,min=1"`
+	Feedback string           `json:"feedback"`
+}
+
+// AcceptGradeDraftRequest represents the teacher edits applied when accepting an AI grade draft.
+// Omitted fields keep the values suggested by the AI.
+type AcceptGradeDraftRequest struct {
+	Grade    *uint            `json:"grade" binding:"omitempty,gte=0,lte=100"` // Only for assignments without rubric
+	Feedback *string          `json:"feedback"`
+	Criteria []CriterionScore `json:"criteria"` // Replaces the suggested scores of the given criteria
+}
+
 type ResourceOrderUpdateRequest struct {
 	ID string `json:"id" binding:"required"`
 	// Order is determined by the position in the array
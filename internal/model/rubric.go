@@ -0,0 +1,147 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Rubric is a set of weighted criteria used to grade the submissions of an assignment
+type Rubric struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	CourseID  uint              `json:"course_id" gorm:"not null;index"`
+	Title     string            `json:"title"`
+	Criteria  []RubricCriterion `json:"criteria" gorm:"serializer:json;type:jsonb;not null"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// RubricCriterion is an aspect of the submission graded on its own
+type RubricCriterion struct {
+	Name        string        `json:"name" example:"Correctness"`
+	Description string        `json:"description" example:"The solution produces the expected results"`
+	Weight      float64       `json:"weight" example:"2"`
+	Levels      []RubricLevel `json:"levels"`
+}
+
+// RubricLevel describes a performance level of a criterion and the points it is worth
+type RubricLevel struct {
+	Title       string  `json:"title" example:"Excellent"`
+	Description string  `json:"description" example:"Every case is handled"`
+	Points      float64 `json:"points" example:"4"`
+}
+
+// CriterionScore is the score given to a submission on a rubric criterion
+type CriterionScore struct {
+	Criterion     string  `json:"criterion"`
+	Level         string  `json:"level"`
+	Score         float64 `json:"score"`
+	Justification string  `json:"justification"`
+}
+
+// MaxPoints returns the points of the best level of the criterion
+func (c RubricCriterion) MaxPoints() float64 {
+	maxPoints := 0.0
+	for _, level := range c.Levels {
+		maxPoints = math.Max(maxPoints, level.Points)
+	}
+	return maxPoints
+}
+
+// HasLevel reports whether the criterion defines a level with the given title
+func (c RubricCriterion) HasLevel(title string) bool {
+	for _, level := range c.Levels {
+		if level.Title == title {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the rubric can be used to grade submissions
+func (r *Rubric) Validate() error {
+	if len(r.Criteria) == 0 {
+		return fmt.Errorf("rubric must have at least one criterion")
+	}
+
+	names := make(map[string]bool, len(r.Criteria))
+	for _, criterion := range r.Criteria {
+		if criterion.Name == "" {
+			return fmt.Errorf("every criterion must have a name")
+		}
+		if names[criterion.Name] {
+			return fmt.Errorf("criterion '%s' is duplicated", criterion.Name)
+		}
+		names[criterion.Name] = true
+
+		if criterion.Weight <= 0 {
+			return fmt.Errorf("criterion '%s' must have a positive weight", criterion.Name)
+		}
+		if len(criterion.Levels) == 0 {
+			return fmt.Errorf("criterion '%s' must have at least one level", criterion.Name)
+		}
+		for _, level := range criterion.Levels {
+			if level.Title == "" {
+				return fmt.Errorf("every level of criterion '%s' must have a title", criterion.Name)
+			}
+			if level.Points < 0 {
+				return fmt.Errorf("level '%s' of criterion '%s' must not have negative points", level.Title, criterion.Name)
+			}
+		}
+		if criterion.MaxPoints() <= 0 {
+			return fmt.Errorf("criterion '%s' must have a level worth more than 0 points", criterion.Name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateScores checks that there is exactly one valid score per criterion
+func (r *Rubric) ValidateScores(scores []CriterionScore) error {
+	byName := make(map[string]CriterionScore, len(scores))
+	for _, score := range scores {
+		if _, duplicated := byName[score.Criterion]; duplicated {
+			return fmt.Errorf("criterion '%s' is scored more than once", score.Criterion)
+		}
+		byName[score.Criterion] = score
+	}
+
+	for _, criterion := range r.Criteria {
+		score, ok := byName[criterion.Name]
+		if !ok {
+			return fmt.Errorf("criterion '%s' is not scored", criterion.Name)
+		}
+		if score.Score < 0 || score.Score > criterion.MaxPoints() {
+			return fmt.Errorf("score of criterion '%s' must be between 0 and %g", criterion.Name, criterion.MaxPoints())
+		}
+		if score.Level != "" && !criterion.HasLevel(score.Level) {
+			return fmt.Errorf("criterion '%s' has no level '%s'", criterion.Name, score.Level)
+		}
+		delete(byName, criterion.Name)
+	}
+
+	for name := range byName {
+		return fmt.Errorf("criterion '%s' is not part of the rubric", name)
+	}
+
+	return nil
+}
+
+// Total returns the weighted grade, from 0 to 100, of valid criterion scores
+func (r *Rubric) Total(scores []CriterionScore) uint {
+	byName := make(map[string]float64, len(scores))
+	for _, score := range scores {
+		byName[score.Criterion] = score.Score
+	}
+
+	var weighted, totalWeight float64
+	for _, criterion := range r.Criteria {
+		weighted += criterion.Weight * byName[criterion.Name] / criterion.MaxPoints()
+		totalWeight += criterion.Weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+
+	return uint(math.Round(weighted / totalWeight * 100))
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// SimilarityContentSource is the source of the matches found in the content of a submission, other
+// matches are found in the file they name
+const SimilarityContentSource = "content"
+
+// SubmissionSimilarity is how similar two submissions of an assignment are, computed in the background
+// every time the submissions change. Each pair is stored once, with SubmissionID lower than OtherSubmissionID.
+type SubmissionSimilarity struct {
+	ID                uint              `json:"id" gorm:"primaryKey"`
+	AssignmentID      uint              `json:"assignment_id" gorm:"not null;index"`
+	SubmissionID      uint              `json:"submission_id" gorm:"not null"`
+	UserID            string            `json:"user_id" gorm:"not null"`
+	OtherSubmissionID uint              `json:"other_submission_id" gorm:"not null"`
+	OtherUserID       string            `json:"other_user_id" gorm:"not null"`
+	Score             float64           `json:"score"` // share of the smaller submission found in the other one, from 0 to 1
+	Matches           []SimilarityMatch `json:"matches" gorm:"serializer:json;type:jsonb"`
+	CheckedAt         time.Time         `json:"checked_at"`
+}
+
+// SimilarityMatch is a fragment found in both submissions, with its byte offsets in each source
+type SimilarityMatch struct {
+	Source      string `json:"source" example:"main.py"`
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Text        string `json:"text"`
+	OtherSource string `json:"other_source" example:"solution.py"`
+	OtherStart  int    `json:"other_start"`
+	OtherEnd    int    `json:"other_end"`
+	OtherText   string `json:"other_text"`
+}
+
+// SimilarityReport lists the pairs of submissions of an assignment that look alike, most similar first
+type SimilarityReport struct {
+	AssignmentID uint                   `json:"assignment_id"`
+	CheckedAt    *time.Time             `json:"checked_at"` // nil until the submissions are checked
+	Pairs        []SubmissionSimilarity `json:"pairs"`
+}
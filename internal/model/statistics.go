@@ -18,6 +18,7 @@ type CourseStatistics struct {
 	Suggestions                             string                    `json:"suggestions"`
 	GlobalAverageGrade                      float64                   `json:"global_average_grade" gorm:"default:0"`
 	GlobalSubmissionRate                    float64                   `json:"global_submission_rate" gorm:"default:0"`
+	GradedSubmissionsCount                  int                       `json:"graded_submissions_count" gorm:"default:0"`
 	StatisticsForAssignments                []StatisticsForAssignment `json:"statistics_for_assignments" gorm:"serializer:json"`
 }
 
@@ -36,4 +37,5 @@ type StatisticsForAssignment struct {
 	Date           time.Time `json:"date"`
 	AverageGrade   float64   `json:"average_grade" gorm:"default:0"`
 	SubmissionRate float64   `json:"submission_rate" gorm:"default:0"`
+	GradedCount    int       `json:"graded_count"` // Graded submissions, the average grade is meaningless without them
 }
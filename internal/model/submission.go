@@ -4,6 +4,15 @@ import (
 	"time"
 )
 
+// Grading states of a Submission
+const (
+	SubmissionStatusSubmitted = "submitted" // waiting to be graded
+	SubmissionStatusGraded    = "graded"    // graded by a teacher
+	SubmissionStatusReturned  = "returned"  // grade and feedback returned to the student
+	SubmissionStatusRegraded  = "regraded"  // graded again after a previous grade
+)
+
+// Submission is the latest attempt of a student at an assignment. Every attempt is kept as a SubmissionAttempt.
 type Submission struct {
 	ID           uint             `json:"id" gorm:"primaryKey"`
 	CourseID     uint             `json:"course_id" gorm:"not null"`
@@ -11,14 +20,44 @@ type Submission struct {
 	UserID       string           `json:"user_id" gorm:"not null"`
 	Content      string           `json:"content" gorm:"not null"`
 	SubmittedAt  time.Time        `json:"submitted_at" gorm:"autoCreateTime"`
+	Late         bool             `json:"late"`
+	DaysLate     int              `json:"days_late,omitempty"`
+	LatePenalty  float64          `json:"late_penalty,omitempty"` // percentage deducted from the grade
 	Grade        uint             `json:"grade" gorm:"check:grade >= 0 AND grade <= 100"`
 	Feedback     string           `json:"feedback"`
+	Status       string           `json:"status" gorm:"not null;default:submitted;index"`
+	GradedAt     *time.Time       `json:"graded_at,omitempty"`
+	GradedBy     string           `json:"graded_by,omitempty"`
+	RubricID     *uint            `json:"rubric_id,omitempty"`                                  // Rubric the grade was computed with
+	Scores       []CriterionScore `json:"criteria,omitempty" gorm:"serializer:json;type:jsonb"` // Score of each rubric criterion
+	Attempt      int              `json:"attempt" gorm:"not null;default:1"`                    // Number of the latest attempt
+	FinalGrade   *uint            `json:"final_grade"`                                          // Grade that counts following the attempt policy of the assignment
 	Files        []SubmissionFile `gorm:"many2many:submission_files_join" json:"files"`
 }
 
+// IsGraded reports whether the submission has a grade, which may be 0
+func (s *Submission) IsGraded() bool {
+	switch s.Status {
+	case SubmissionStatusGraded, SubmissionStatusReturned, SubmissionStatusRegraded:
+		return true
+	}
+	return false
+}
+
+// MarkGraded records who graded the submission and when, as a regrade if it already had a grade
+func (s *Submission) MarkGraded(gradedBy string, gradedAt time.Time) {
+	if s.IsGraded() {
+		s.Status = SubmissionStatusRegraded
+	} else {
+		s.Status = SubmissionStatusGraded
+	}
+	s.GradedAt = &gradedAt
+	s.GradedBy = gradedBy
+}
+
+// SubmissionFile is a file attached to a submission. Its content is kept in the blob store.
 type SubmissionFile struct {
-	ID      uint   `gorm:"primarykey" json:"id"`
-	Name    string `gorm:"not null" json:"name"`
-	Content []byte `gorm:"not null" json:"content"`
-	Size    int64  `json:"size"`
+	ID   uint   `gorm:"primarykey" json:"id"`
+	Name string `gorm:"not null" json:"name"`
+	FileContent
 }
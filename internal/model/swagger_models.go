@@ -8,6 +8,13 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// PageResponse represents a page of a list endpoint
+// @Description Page of a list, with the cursor of the next page
+type PageResponse struct {
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor" example:"eyJzIjoiaWQiLCJ2IjoyMCwiaWQiOjIwfQ"` // empty on the last page
+}
+
 // ErrorResponse represents an error API response
 // @Description Error response
 type ErrorResponse struct {
@@ -29,10 +36,28 @@ type CourseResponse struct {
 	Title       string `json:"title" example:"Introduction to Programming"`
 	Description string `json:"description" example:"Learn the basics of programming with Python"`
 	CreatedBy   string `json:"createdBy" example:"teacher123"`
+	State       string `json:"state" example:"enrollment_open"`
+	IsTemplate  bool   `json:"isTemplate" example:"false"`
+	ClonedFrom  *uint  `json:"clonedFromId" example:"7"` // the course or template it was cloned from
 	CreatedAt   string `json:"created_at" example:"2023-01-15T10:00:00Z"`
 	UpdatedAt   string `json:"updated_at" example:"2023-01-15T10:00:00Z"`
 }
 
+// CourseSearchResponse represents a course found by a search
+// @Description Course search result
+type CourseSearchResponse struct {
+	CourseResponse
+	Rank       float64          `json:"rank" example:"0.6079"`
+	Highlights SearchHighlights `json:"highlights"`
+}
+
+// SearchHighlights are the texts of a course where a search matched
+// @Description Matches highlighted between <mark> tags, in HTML escaped text
+type SearchHighlights struct {
+	Title       string `json:"title" example:"Introduction to <mark>Programming</mark>"`
+	Description string `json:"description" example:"Learn the basics of <mark>programming</mark> with Python"`
+}
+
 // MembersList represents the members list for a course
 // @Description Members list response
 type MembersList struct {
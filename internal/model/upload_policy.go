@@ -0,0 +1,46 @@
+package model
+
+import (
+	"mime"
+	"path"
+	"strings"
+)
+
+// AllowsFile reports whether a file with the given name and detected content type can be submitted to the
+// assignment. AllowedFileTypes holds MIME types such as application/pdf, wildcards such as image/*, or
+// extensions such as .py, which suit formats whose content is detected as a generic type.
+func (a *Assignment) AllowsFile(name, contentType string) bool {
+	if len(a.AllowedFileTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	for _, allowed := range a.AllowedFileTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		switch {
+		case strings.HasPrefix(allowed, "."):
+			if strings.EqualFold(path.Ext(name), allowed) {
+				return true
+			}
+		case strings.HasSuffix(allowed, "/*"):
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*")) {
+				return true
+			}
+		case mediaType == allowed:
+			return true
+		}
+	}
+	return false
+}
+
+// FileSizeLimit returns the largest file that can be submitted to the assignment, in bytes,
+// given the limit of the service
+func (a *Assignment) FileSizeLimit(serviceLimit int64) int64 {
+	if a.MaxFileSize > 0 && a.MaxFileSize < serviceLimit {
+		return a.MaxFileSize
+	}
+	return serviceLimit
+}
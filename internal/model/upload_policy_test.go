@@ -0,0 +1,24 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignment_AllowsFile(t *testing.T) {
+	assert.True(t, (&Assignment{}).AllowsFile("setup.exe", "application/octet-stream"))
+
+	assignment := &Assignment{AllowedFileTypes: []string{"application/pdf", "image/*", ".PY"}}
+	assert.True(t, assignment.AllowsFile("report.pdf", "application/pdf"))
+	assert.True(t, assignment.AllowsFile("diagram", "image/png"))
+	assert.True(t, assignment.AllowsFile("main.py", "text/plain; charset=utf-8"))
+	assert.False(t, assignment.AllowsFile("notes.txt", "text/plain; charset=utf-8"))
+	assert.False(t, assignment.AllowsFile("report.pdf", "application/zip"))
+}
+
+func TestAssignment_FileSizeLimit(t *testing.T) {
+	assert.Equal(t, int64(100), (&Assignment{}).FileSizeLimit(100))
+	assert.Equal(t, int64(10), (&Assignment{MaxFileSize: 10}).FileSizeLimit(100))
+	assert.Equal(t, int64(100), (&Assignment{MaxFileSize: 1000}).FileSizeLimit(100))
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Waitlist represents a user waiting for a seat in a course that reached its capacity.
+// Entries are served in arrival order (CreatedAt, then ID).
+type Waitlist struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CourseID  uint      `json:"course_id" gorm:"not null;uniqueIndex:idx_waitlist_course_user"`
+	UserID    string    `json:"user_id" gorm:"not null;uniqueIndex:idx_waitlist_course_user"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Associations
+	Course Course `gorm:"foreignKey:CourseID" json:"-"`
+}
@@ -5,25 +5,34 @@ package queue
 
 /*
 import (
+	"templateGo/internal/approval"
+	"templateGo/internal/certificates"
 	"templateGo/internal/queue"
 	"templateGo/internal/repositories"
 	"templateGo/internal/handlers/ai"
 	"templateGo/internal/handlers/course"
+	"templateGo/internal/storage"
 )
 
 func main() {
 	// Initialize your existing dependencies
 	repo := repositories.NewCourseRepository()
-	aiAnalyzer := ai.NewGeminiAnalyzer() // or whatever your AI analyzer implementation is
+	aiAnalyzer := ai.NewLLMAnalyzer(ai.NewStubProvider()) // or any other ai.Provider
 	notification := notification.NewNotificationClient()
 	metricsClient := metrics.NewDatadogMetricsClient()
 
+	blobStore, _ := storage.NewBlobStoreFromEnv()
+
 	// Initialize the statistics service
-	statisticsService := queue.NewStatisticsService(repo, aiAnalyzer)
+	statisticsService := queue.NewStatisticsService(repo, aiAnalyzer, blobStore)
 
 	// Start the statistics service (this starts the background workers)
 	statisticsService.Start()
 
+	// Certificates are signed with the key in CERTIFICATE_SIGNING_KEY
+	signer, _ := certificates.NewSignerFromEnv()
+	certificateIssuer := certificates.NewIssuer(repo, signer, notification)
+
 	// Initialize the course handler with the statistics service
 	courseHandler := course.NewCourseHandler(
 		repo,
@@ -31,6 +40,9 @@ func main() {
 		aiAnalyzer,
 		metricsClient,
 		statisticsService,
+		blobStore,
+		approval.NewEngine(repo, notification, certificateIssuer),
+		certificateIssuer,
 	)
 
 	// Set up your routes with the courseHandler
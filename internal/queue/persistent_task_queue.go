@@ -0,0 +1,427 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PersistentTaskQueueConfig configures a PersistentTaskQueue
+type PersistentTaskQueueConfig struct {
+	Workers           int
+	PollInterval      time.Duration // how long an idle worker waits before polling again
+	VisibilityTimeout time.Duration // how long a claimed task stays invisible to other workers
+	BaseBackoff       time.Duration // delay before the first retry, doubled on every attempt
+	MaxBackoff        time.Duration
+}
+
+// DefaultPersistentTaskQueueConfig returns the configuration used by the statistics service
+func DefaultPersistentTaskQueueConfig() PersistentTaskQueueConfig {
+	return PersistentTaskQueueConfig{
+		Workers:           3,
+		PollInterval:      time.Second,
+		VisibilityTimeout: 5 * time.Minute,
+		BaseBackoff:       2 * time.Second,
+		MaxBackoff:        10 * time.Minute,
+	}
+}
+
+// PersistentTaskQueue is a durable task queue backed by the queued_tasks table.
+// Workers claim tasks with SELECT ... FOR UPDATE SKIP LOCKED, so several instances
+// of the service can share the same table. A claimed task that is not finished before
+// its visibility timeout (e.g. the process crashed) becomes available again.
+type PersistentTaskQueue struct {
+	db         *gorm.DB
+	config     PersistentTaskQueueConfig
+	processor  TaskProcessor
+	instanceID string
+	workerPool sync.WaitGroup
+	ctx        context.Context
+	cancel     context.CancelFunc
+	mu         sync.RWMutex
+	running    bool
+}
+
+// NewPersistentTaskQueue creates a new database backed task queue
+func NewPersistentTaskQueue(db *gorm.DB, config PersistentTaskQueueConfig, processor TaskProcessor) *PersistentTaskQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &PersistentTaskQueue{
+		db:         db,
+		config:     config,
+		processor:  processor,
+		instanceID: fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8]),
+		ctx:        ctx,
+		cancel:     cancel,
+		running:    false,
+	}
+}
+
+// Start starts the task queue workers
+func (pq *PersistentTaskQueue) Start() {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	if pq.running {
+		return
+	}
+
+	pq.running = true
+
+	for i := 0; i < pq.config.Workers; i++ {
+		pq.workerPool.Add(1)
+		go pq.worker(i)
+	}
+
+	log.Printf("Persistent task queue started with %d workers", pq.config.Workers)
+}
+
+// Stop stops the task queue. Tasks still pending stay in the table for the next start.
+func (pq *PersistentTaskQueue) Stop() {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	if !pq.running {
+		return
+	}
+
+	pq.running = false
+	pq.cancel()
+	pq.workerPool.Wait()
+
+	log.Println("Persistent task queue stopped")
+}
+
+// EnqueueTask stores a task in the queue.
+// If a pending task with the same Key exists the new task is coalesced into it:
+// its data is replaced, its dependencies are merged and its debounce window restarts,
+// never past MaxDelay from the first enqueue.
+func (pq *PersistentTaskQueue) EnqueueTask(task Task) error {
+	payload, err := json.Marshal(task.Data)
+	if err != nil {
+		return fmt.Errorf("error encoding task data: %w", err)
+	}
+
+	if task.MaxRetries == 0 {
+		task.MaxRetries = 3 // Default max retries
+	}
+
+	now := time.Now()
+	queued := model.QueuedTask{
+		ID:          task.ID,
+		Type:        string(task.Type),
+		Payload:     payload,
+		DedupKey:    task.Key,
+		DependsOn:   task.DependsOn,
+		Status:      model.QueuedTaskStatusPending,
+		MaxAttempts: task.MaxRetries + 1,
+		AvailableAt: now.Add(task.Delay),
+		CreatedAt:   now,
+	}
+	if task.MaxDelay > 0 {
+		runBy := now.Add(max(task.MaxDelay, task.Delay))
+		queued.RunBy = &runBy
+	}
+
+	query := pq.db
+	if task.Key != "" {
+		// The ID returned is the one of the pending task the new one is coalesced into, if any
+		query = query.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}, clause.OnConflict{
+			Columns: []clause.Column{{Name: "dedup_key"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "status = 'pending' AND dedup_key <> ''"},
+			}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"payload":      gorm.Expr("excluded.payload"),
+				"available_at": gorm.Expr("LEAST(excluded.available_at, COALESCE(queued_tasks.run_by, excluded.run_by))"),
+				"run_by":       gorm.Expr("COALESCE(queued_tasks.run_by, excluded.run_by)"),
+				"depends_on":   gorm.Expr("ARRAY(SELECT DISTINCT unnest(array_cat(queued_tasks.depends_on, excluded.depends_on)))"),
+				"updated_at":   now,
+			}),
+		})
+	}
+
+	if err := query.Create(&queued).Error; err != nil {
+		return fmt.Errorf("error enqueueing task: %w", err)
+	}
+
+	if queued.ID != task.ID {
+		log.Printf("Task %s coalesced into task %s", task.ID, queued.ID)
+		return nil
+	}
+	log.Printf("Task %s enqueued successfully", task.ID)
+	return nil
+}
+
+// GetQueueSize returns the number of tasks waiting or being processed
+func (pq *PersistentTaskQueue) GetQueueSize() int {
+	var count int64
+	pq.db.Model(&model.QueuedTask{}).
+		Where("status IN ?", []string{model.QueuedTaskStatusPending, model.QueuedTaskStatusRunning}).
+		Count(&count)
+	return int(count)
+}
+
+// ListDeadTasks returns the tasks that exhausted their attempts, newest first.
+// When no type is given every dead task is returned.
+func (pq *PersistentTaskQueue) ListDeadTasks(types ...TaskType) ([]model.QueuedTask, error) {
+	query := pq.db.Where("status = ?", model.QueuedTaskStatusDead)
+	if len(types) > 0 {
+		query = query.Where("type IN ?", types)
+	}
+
+	var tasks []model.QueuedTask
+	if err := query.Order("updated_at DESC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// RequeueDeadTask moves a dead task back to the pending state with a fresh attempt budget.
+// If a pending task with the same key already exists the dead task is dropped in its favor.
+func (pq *PersistentTaskQueue) RequeueDeadTask(id string) error {
+	return pq.db.Transaction(func(tx *gorm.DB) error {
+		var queued model.QueuedTask
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", id, model.QueuedTaskStatusDead).
+			First(&queued).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		superseded, err := hasPendingDuplicate(tx, &queued)
+		if err != nil {
+			return err
+		}
+		if superseded {
+			return tx.Delete(&queued).Error
+		}
+
+		return tx.Model(&queued).Updates(map[string]any{
+			"status":       model.QueuedTaskStatusPending,
+			"attempts":     0,
+			"available_at": time.Now(),
+			"locked_by":    "",
+			"locked_until": nil,
+		}).Error
+	})
+}
+
+// worker claims and processes tasks until the queue is stopped
+func (pq *PersistentTaskQueue) worker(workerID int) {
+	defer pq.workerPool.Done()
+
+	log.Printf("Worker %d started", workerID)
+
+	lockedBy := fmt.Sprintf("%s/%d", pq.instanceID, workerID)
+	for {
+		queued, err := pq.claimTask(lockedBy)
+		if err != nil {
+			log.Printf("Worker %d failed to claim a task: %v", workerID, err)
+		}
+
+		if queued == nil {
+			select {
+			case <-pq.ctx.Done():
+				log.Printf("Worker %d stopped: context canceled", workerID)
+				return
+			case <-time.After(pq.config.PollInterval):
+			}
+			continue
+		}
+
+		pq.processTask(workerID, queued)
+
+		if pq.ctx.Err() != nil {
+			log.Printf("Worker %d stopped: context canceled", workerID)
+			return
+		}
+	}
+}
+
+// claimTask locks the next available task, skipping the ones locked by other workers.
+// Pending tasks become available at AvailableAt, running tasks when their lock expires.
+func (pq *PersistentTaskQueue) claimTask(lockedBy string) (*model.QueuedTask, error) {
+	var claimed *model.QueuedTask
+
+	err := pq.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// Tasks whose worker died during their last attempt go straight to the dead-letter state
+		if err := tx.Model(&model.QueuedTask{}).
+			Where("status = ? AND locked_until <= ? AND attempts >= max_attempts", model.QueuedTaskStatusRunning, now).
+			Updates(map[string]any{
+				"status":       model.QueuedTaskStatusDead,
+				"last_error":   "visibility timeout expired",
+				"locked_by":    "",
+				"locked_until": nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		// A task waits while one of its dependencies is unfinished or while another
+		// task with its key is running, so the same statistics are never computed twice at once
+		var queued model.QueuedTask
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND available_at <= ?) OR (status = ? AND locked_until <= ?)",
+				model.QueuedTaskStatusPending, now, model.QueuedTaskStatusRunning, now).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM queued_tasks other
+				WHERE other.id <> queued_tasks.id
+				AND (
+					(other.status IN (?, ?) AND other.dedup_key = ANY(queued_tasks.depends_on))
+					OR (other.status = ? AND queued_tasks.dedup_key <> '' AND other.dedup_key = queued_tasks.dedup_key)
+				))`,
+				model.QueuedTaskStatusPending, model.QueuedTaskStatusRunning, model.QueuedTaskStatusRunning).
+			Order("available_at, created_at").
+			First(&queued).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		lockedUntil := now.Add(pq.config.VisibilityTimeout)
+		queued.Status = model.QueuedTaskStatusRunning
+		queued.Attempts++
+		queued.LockedBy = lockedBy
+		queued.LockedUntil = &lockedUntil
+
+		if err := tx.Model(&queued).Updates(map[string]any{
+			"status":       queued.Status,
+			"attempts":     queued.Attempts,
+			"locked_by":    queued.LockedBy,
+			"locked_until": queued.LockedUntil,
+		}).Error; err != nil {
+			return err
+		}
+
+		claimed = &queued
+		return nil
+	})
+
+	return claimed, err
+}
+
+// processTask runs a claimed task and records its outcome
+func (pq *PersistentTaskQueue) processTask(workerID int, queued *model.QueuedTask) {
+	log.Printf("Worker %d processing task %s (type: %s, attempt %d/%d)",
+		workerID, queued.ID, queued.Type, queued.Attempts, queued.MaxAttempts)
+
+	task, err := taskFromQueued(queued)
+	if err == nil {
+		err = pq.processor.ProcessTask(pq.ctx, task)
+	}
+
+	if err == nil {
+		log.Printf("Worker %d successfully processed task %s", workerID, queued.ID)
+		if err := pq.owned(queued).Delete(&model.QueuedTask{}).Error; err != nil {
+			log.Printf("Failed to remove finished task %s: %v", queued.ID, err)
+		}
+		return
+	}
+
+	log.Printf("Worker %d failed to process task %s: %v", workerID, queued.ID, err)
+
+	updates := map[string]any{
+		"last_error":   err.Error(),
+		"locked_by":    "",
+		"locked_until": nil,
+	}
+	if queued.Attempts >= queued.MaxAttempts {
+		log.Printf("Task %s moved to dead-letter after %d attempts", queued.ID, queued.Attempts)
+		updates["status"] = model.QueuedTaskStatusDead
+	} else {
+		superseded, err := hasPendingDuplicate(pq.db, queued)
+		if err != nil {
+			log.Printf("Failed to check duplicates of task %s: %v", queued.ID, err)
+		}
+		if superseded {
+			log.Printf("Task %s superseded by a newer pending task with key %s", queued.ID, queued.DedupKey)
+			if err := pq.owned(queued).Delete(&model.QueuedTask{}).Error; err != nil {
+				log.Printf("Failed to remove superseded task %s: %v", queued.ID, err)
+			}
+			return
+		}
+
+		delay := pq.backoff(queued.Attempts)
+		log.Printf("Retrying task %s in %s", queued.ID, delay)
+		updates["status"] = model.QueuedTaskStatusPending
+		updates["available_at"] = time.Now().Add(delay)
+	}
+
+	if err := pq.owned(queued).Updates(updates).Error; err != nil {
+		log.Printf("Failed to record failure of task %s: %v", queued.ID, err)
+	}
+}
+
+// taskFromQueued rebuilds the Task handed to the processor from its stored row
+func taskFromQueued(queued *model.QueuedTask) (Task, error) {
+	data, err := decodeTaskData(TaskType(queued.Type), queued.Payload)
+	if err != nil {
+		return Task{}, err
+	}
+
+	return Task{
+		ID:         queued.ID,
+		Type:       TaskType(queued.Type),
+		Data:       data,
+		Key:        queued.DedupKey,
+		DependsOn:  queued.DependsOn,
+		CreatedAt:  queued.CreatedAt,
+		Retries:    queued.Attempts - 1,
+		MaxRetries: queued.MaxAttempts - 1,
+	}, nil
+}
+
+// hasPendingDuplicate reports whether another pending task shares the key of the given task
+func hasPendingDuplicate(db *gorm.DB, queued *model.QueuedTask) (bool, error) {
+	if queued.DedupKey == "" {
+		return false, nil
+	}
+
+	var count int64
+	err := db.Model(&model.QueuedTask{}).
+		Where("id <> ? AND status = ? AND dedup_key = ?", queued.ID, model.QueuedTaskStatusPending, queued.DedupKey).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// owned scopes a query to a task only while this worker still holds its lock,
+// so a worker whose visibility timeout expired cannot overwrite the new owner
+func (pq *PersistentTaskQueue) owned(queued *model.QueuedTask) *gorm.DB {
+	return pq.db.Model(&model.QueuedTask{}).
+		Where("id = ? AND status = ? AND locked_by = ?", queued.ID, model.QueuedTaskStatusRunning, queued.LockedBy)
+}
+
+// backoff returns the exponential delay before the next attempt
+func (pq *PersistentTaskQueue) backoff(attempts int) time.Duration {
+	delay := pq.config.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= pq.config.MaxBackoff {
+			return pq.config.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,41 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"templateGo/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentTaskQueue_BackoffIsExponentialAndCapped(t *testing.T) {
+	pq := &PersistentTaskQueue{config: PersistentTaskQueueConfig{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+
+	assert.Equal(t, time.Second, pq.backoff(1))
+	assert.Equal(t, 2*time.Second, pq.backoff(2))
+	assert.Equal(t, 4*time.Second, pq.backoff(3))
+	assert.Equal(t, 5*time.Second, pq.backoff(4))
+	assert.Equal(t, 5*time.Second, pq.backoff(10))
+}
+
+func TestTaskFromQueued_DecodesPayloadByType(t *testing.T) {
+	queued := &model.QueuedTask{
+		ID:          "course-stats-1",
+		Type:        string(TaskTypeCourseStatistics),
+		Payload:     []byte(`{"course_id":7,"user_id":"u1","user_email":"u1@example.com"}`),
+		Attempts:    2,
+		MaxAttempts: 4,
+	}
+
+	task, err := taskFromQueued(queued)
+
+	assert.NoError(t, err)
+	assert.Equal(t, CourseStatisticsTaskData{CourseID: 7, UserID: "u1", UserEmail: "u1@example.com"}, task.Data)
+	assert.Equal(t, 1, task.Retries)
+	assert.Equal(t, 3, task.MaxRetries)
+
+	queued.Type = "unknown"
+	_, err = taskFromQueued(queued)
+	assert.Error(t, err)
+}
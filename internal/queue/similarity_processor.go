@@ -0,0 +1,123 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"templateGo/internal/model"
+	"templateGo/internal/similarity"
+	"templateGo/internal/utils"
+	"time"
+)
+
+// minStoredSimilarity is the lowest score of the pairs of submissions kept, below it submissions
+// only share common phrases
+const minStoredSimilarity = 0.1
+
+// maxComparedFileSize bounds the files read to compare submissions, larger files are skipped
+const maxComparedFileSize = 1 << 20
+
+// processSubmissionSimilarityTask compares every pair of submissions of an assignment, by content and text
+// files, and replaces their stored similarity. Fragments of the files of the assignment itself, such as
+// a statement or starter code every student got, are not counted.
+func (stp *StatisticsTaskProcessor) processSubmissionSimilarityTask(ctx context.Context, task Task) error {
+	data, ok := task.Data.(SubmissionSimilarityTaskData)
+	if !ok {
+		return fmt.Errorf("invalid task data type for submission similarity task")
+	}
+
+	log.Printf("Processing submission similarity for assignment %d", data.AssignmentID)
+
+	assignment, err := stp.repo.GetAssignmentByID(data.AssignmentID)
+	if err != nil {
+		return fmt.Errorf("error retrieving assignment: %w", err)
+	}
+	submissions, err := stp.repo.GetSubmissions(assignment.CourseID, assignment.ID)
+	if err != nil {
+		return fmt.Errorf("error retrieving submissions: %w", err)
+	}
+	sort.Slice(submissions, func(i, j int) bool { return submissions[i].ID < submissions[j].ID })
+
+	var baseDocuments []similarity.Document
+	for _, file := range assignment.Files {
+		document, ok, err := stp.textDocument(ctx, file.Name, file.FileContent)
+		if err != nil {
+			return err
+		}
+		if ok {
+			baseDocuments = append(baseDocuments, document)
+		}
+	}
+	base := similarity.Fingerprint(baseDocuments)
+
+	fingerprints := make([]*similarity.Fingerprints, len(submissions))
+	for i, submission := range submissions {
+		documents := []similarity.Document{{Name: model.SimilarityContentSource, Text: submission.Content}}
+		for _, file := range submission.Files {
+			document, ok, err := stp.textDocument(ctx, file.Name, file.FileContent)
+			if err != nil {
+				return err
+			}
+			if ok {
+				documents = append(documents, document)
+			}
+		}
+		fingerprints[i] = similarity.Fingerprint(documents)
+		fingerprints[i].Exclude(base)
+	}
+
+	now := time.Now()
+	var similarities []model.SubmissionSimilarity
+	for i := range submissions {
+		for j := i + 1; j < len(submissions); j++ {
+			result := similarity.Compare(fingerprints[i], fingerprints[j])
+			if result.Score < minStoredSimilarity {
+				continue
+			}
+			similarities = append(similarities, model.SubmissionSimilarity{
+				AssignmentID:      assignment.ID,
+				SubmissionID:      submissions[i].ID,
+				UserID:            submissions[i].UserID,
+				OtherSubmissionID: submissions[j].ID,
+				OtherUserID:       submissions[j].UserID,
+				Score:             result.Score,
+				Matches:           result.Matches,
+				CheckedAt:         now,
+			})
+		}
+	}
+
+	if err := stp.repo.ReplaceSubmissionSimilarities(assignment.ID, similarities); err != nil {
+		return fmt.Errorf("error saving submission similarities: %w", err)
+	}
+	log.Printf("Compared %d submissions of assignment %d, %d similar pairs", len(submissions), assignment.ID, len(similarities))
+	return nil
+}
+
+// textDocument reads a file from the blob store to compare it, unless it is too large or not text
+func (stp *StatisticsTaskProcessor) textDocument(ctx context.Context, name string, content model.FileContent) (similarity.Document, bool, error) {
+	if content.SHA256 == "" || content.Size > maxComparedFileSize {
+		return similarity.Document{}, false, nil
+	}
+
+	blob, err := stp.blobs.Open(ctx, content.SHA256)
+	if errors.Is(err, utils.ErrBlobNotFound) {
+		return similarity.Document{}, false, nil
+	}
+	if err != nil {
+		return similarity.Document{}, false, fmt.Errorf("error reading file %s: %w", name, err)
+	}
+	defer blob.Close()
+
+	text, err := io.ReadAll(io.LimitReader(blob, maxComparedFileSize))
+	if err != nil {
+		return similarity.Document{}, false, fmt.Errorf("error reading file %s: %w", name, err)
+	}
+	if !similarity.IsText(content.ContentType, text) {
+		return similarity.Document{}, false, nil
+	}
+	return similarity.Document{Name: name, Text: string(text)}, true, nil
+}
@@ -1,11 +1,13 @@
 package queue
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"templateGo/internal/handlers/ai"
 	"templateGo/internal/model"
 	"templateGo/internal/repositories"
+	"templateGo/internal/storage"
 
 	"gonum.org/v1/gonum/stat"
 )
@@ -14,32 +16,36 @@ import (
 type StatisticsTaskProcessor struct {
 	repo       repositories.CourseRepository
 	aiAnalyzer ai.FeedbackAnalyzer
+	blobs      storage.BlobStore
 }
 
 // NewStatisticsTaskProcessor creates a new statistics task processor
-func NewStatisticsTaskProcessor(repo repositories.CourseRepository, aiAnalyzer ai.FeedbackAnalyzer) *StatisticsTaskProcessor {
+func NewStatisticsTaskProcessor(repo repositories.CourseRepository, aiAnalyzer ai.FeedbackAnalyzer, blobs storage.BlobStore) *StatisticsTaskProcessor {
 	return &StatisticsTaskProcessor{
 		repo:       repo,
 		aiAnalyzer: aiAnalyzer,
+		blobs:      blobs,
 	}
 }
 
 // ProcessTask processes a task based on its type
-func (stp *StatisticsTaskProcessor) ProcessTask(task Task) error {
+func (stp *StatisticsTaskProcessor) ProcessTask(ctx context.Context, task Task) error {
 	switch task.Type {
 	case TaskTypeCourseStatistics:
-		return stp.processCourseStatisticsTask(task)
+		return stp.processCourseStatisticsTask(ctx, task)
 	case TaskTypeUserCourseStatistics:
 		return stp.processUserCourseStatisticsTask(task)
 	case TaskTypeGlobalStatistics:
 		return stp.processGlobalStatisticsTask(task)
+	case TaskTypeSubmissionSimilarity:
+		return stp.processSubmissionSimilarityTask(ctx, task)
 	default:
 		return fmt.Errorf("unknown task type: %s", task.Type)
 	}
 }
 
 // processCourseStatisticsTask processes course statistics calculation
-func (stp *StatisticsTaskProcessor) processCourseStatisticsTask(task Task) error {
+func (stp *StatisticsTaskProcessor) processCourseStatisticsTask(ctx context.Context, task Task) error {
 	data, ok := task.Data.(CourseStatisticsTaskData)
 	if !ok {
 		return fmt.Errorf("invalid task data type for course statistics task")
@@ -49,7 +55,7 @@ func (stp *StatisticsTaskProcessor) processCourseStatisticsTask(task Task) error
 
 	// This is the same logic as in the original CalculateAndStoreCourseStatistics function
 	// but moved to the task processor
-	return stp.calculateAndStoreCourseStatistics(data.CourseID, data.UserID, data.UserEmail)
+	return stp.calculateAndStoreCourseStatistics(ctx, data.CourseID, data.UserID, data.UserEmail)
 }
 
 // processUserCourseStatisticsTask processes user course statistics calculation
@@ -69,7 +75,7 @@ func (stp *StatisticsTaskProcessor) processUserCourseStatisticsTask(task Task) e
 // The following functions are copied from the course handler but adapted for the task processor
 // They contain the same business logic but are now part of the background processing
 
-func (stp *StatisticsTaskProcessor) calculateAndStoreCourseStatistics(courseID uint, userID string, userEmail string) error {
+func (stp *StatisticsTaskProcessor) calculateAndStoreCourseStatistics(ctx context.Context, courseID uint, userID string, userEmail string) error {
 	course, err := stp.repo.GetByID(courseID)
 	if err != nil {
 		return fmt.Errorf("error retrieving course: %w", err)
@@ -81,6 +87,7 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreCourseStatistics(courseID u
 	globalTotalAverageGrade := 0.0
 	globalTotalSubmissionRate := 0.0
 	globalAssignmentsWithGradesCount := 0.0
+	gradedSubmissionsCount := 0
 	statisticsForAssignments := make([]model.StatisticsForAssignment, 0)
 	assignments, err := stp.repo.GetAssignmentsPreviews(course.ID, userID, userEmail)
 	if err != nil && err.Error() != "record not found" {
@@ -96,8 +103,9 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreCourseStatistics(courseID u
 		ratedSubmissionsCount := 0.0
 		for _, submission := range submissions {
 			submissionsCount += 1
-			if submission.Grade > 0 {
-				totalGrade += float64(submission.Grade)
+			// The grade that counts follows the attempt policy of the assignment
+			if submission.FinalGrade != nil {
+				totalGrade += float64(*submission.FinalGrade)
 				ratedSubmissionsCount += 1
 			}
 		}
@@ -114,7 +122,9 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreCourseStatistics(courseID u
 			Date:           assignment.CreatedAt,
 			AverageGrade:   averageGrade,
 			SubmissionRate: submissionRate,
+			GradedCount:    int(ratedSubmissionsCount),
 		})
+		gradedSubmissionsCount += int(ratedSubmissionsCount)
 		globalTotalAverageGrade += averageGrade
 		globalTotalSubmissionRate += submissionRate
 	}
@@ -132,13 +142,27 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreCourseStatistics(courseID u
 	Last10AssignmentsAverageGradeTendency, Last10AssignmentsSubmissionRateTendency, Last10AssignmentsAverageGrade :=
 		stp.calculateTendencyAndAverageGrade(last10Statistics)
 
-	suggestions, _ := stp.aiAnalyzer.GenerateCourseSuggestionsBasedOnStats(Last10AssignmentsAverageGradeTendency, Last10AssignmentsSubmissionRateTendency, Last10AssignmentsAverageGrade)
+	last10GradedCount := 0
+	for _, stat := range last10Statistics {
+		last10GradedCount += stat.GradedCount
+	}
+
+	suggestions, err := stp.aiAnalyzer.GenerateCourseSuggestionsBasedOnStats(ctx, Last10AssignmentsAverageGradeTendency, Last10AssignmentsSubmissionRateTendency, Last10AssignmentsAverageGrade, last10GradedCount)
+	if err != nil {
+		// Stopped by the queue, the task runs again
+		if ctx.Err() != nil {
+			return fmt.Errorf("error generating course suggestions: %w", err)
+		}
+		log.Printf("Error generating suggestions for course %d, keeping the previous ones: %v", courseID, err)
+		suggestions = stp.previousSuggestions(courseID)
+	}
 
 	statistics := model.CourseStatistics{
 		CourseID:                                course.ID,
 		CourseName:                              course.Title,
 		GlobalAverageGrade:                      globalTotalAverageGrade,
 		GlobalSubmissionRate:                    globalTotalSubmissionRate,
+		GradedSubmissionsCount:                  gradedSubmissionsCount,
 		Last10AssignmentsAverageGradeTendency:   Last10AssignmentsAverageGradeTendency,
 		Last10AssignmentsSubmissionRateTendency: Last10AssignmentsSubmissionRateTendency,
 		Suggestions:                             suggestions,
@@ -154,6 +178,15 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreCourseStatistics(courseID u
 	return nil
 }
 
+// previousSuggestions returns the suggestions stored with the last statistics of a course, if any
+func (stp *StatisticsTaskProcessor) previousSuggestions(courseID uint) string {
+	previous, err := stp.repo.GetCourseStatistics(courseID)
+	if err != nil {
+		return ""
+	}
+	return previous.Suggestions
+}
+
 func (stp *StatisticsTaskProcessor) calculateAndStoreUserCourseStatistics(courseID uint, studentID string, userEmail string) error {
 	totalGrades := 0.0
 	totalSubmissionsCount := 0.0
@@ -177,14 +210,21 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreUserCourseStatistics(course
 			continue
 		}
 		totalSubmissionsCount += 1
-		if submission.Grade > 0 {
-			totalGrades += float64(submission.Grade)
-			totalRatedSubmissionsCount += 1
+		if submission.FinalGrade == nil {
+			statisticsForDates = append(statisticsForDates, model.StatisticsForAssignment{
+				Date:           assignment.CreatedAt,
+				AverageGrade:   0.0,
+				SubmissionRate: 1.0,
+			})
+			continue
 		}
+		totalGrades += float64(*submission.FinalGrade)
+		totalRatedSubmissionsCount += 1
 		statisticsForDates = append(statisticsForDates, model.StatisticsForAssignment{
 			Date:           assignment.CreatedAt,
-			AverageGrade:   float64(submission.Grade),
+			AverageGrade:   float64(*submission.FinalGrade),
 			SubmissionRate: 1.0,
+			GradedCount:    1,
 		})
 	}
 	averageGrade := 0.0
@@ -233,7 +273,7 @@ func (stp *StatisticsTaskProcessor) calculateTendencyAndAverageGrade(stats []mod
 	// calculate the average first
 	averageGrade := 0.0
 	for _, stat := range stats {
-		if stat.AverageGrade > 0 {
+		if stat.GradedCount > 0 {
 			averageGrade += stat.AverageGrade
 			graded_n += 1
 		}
@@ -254,7 +294,7 @@ func (stp *StatisticsTaskProcessor) calculateTendencyAndAverageGrade(stats []mod
 
 	gradedIndex := 0
 	for i := 0; i < n; i++ {
-		if stats[i].AverageGrade > 0 {
+		if stats[i].GradedCount > 0 {
 			xGrade[gradedIndex] = float64(gradedIndex)
 			yGradeFiltered[gradedIndex] = stats[i].AverageGrade
 			gradedIndex++
@@ -328,6 +368,7 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreGlobalStatistics(teacherEma
 	var totalAverageGrade float64
 	var totalSubmissionRate float64
 	validCourses := 0
+	gradedCourses := 0
 
 	// Calculate averages across all courses
 	for _, course := range courses {
@@ -337,7 +378,11 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreGlobalStatistics(teacherEma
 			continue // Skip courses without statistics
 		}
 
-		totalAverageGrade += courseStats.GlobalAverageGrade
+		// Courses without graded submissions have no average grade
+		if courseStats.GradedSubmissionsCount > 0 {
+			totalAverageGrade += courseStats.GlobalAverageGrade
+			gradedCourses++
+		}
 		totalSubmissionRate += courseStats.GlobalSubmissionRate
 		validCourses++
 	}
@@ -348,7 +393,10 @@ func (stp *StatisticsTaskProcessor) calculateAndStoreGlobalStatistics(teacherEma
 	}
 
 	// Calculate global averages (average of averages)
-	globalAverageGrade := totalAverageGrade / float64(validCourses)
+	globalAverageGrade := 0.0
+	if gradedCourses > 0 {
+		globalAverageGrade = totalAverageGrade / float64(gradedCourses)
+	}
 	globalSubmissionRate := totalSubmissionRate / float64(validCourses)
 
 	// Create global statistics
@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"templateGo/internal/handlers/ai"
+	"templateGo/internal/model"
+	"templateGo/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateTendency_CountsZeroGradesAndSkipsUngraded(t *testing.T) {
+	stp := &StatisticsTaskProcessor{}
+	stats := []model.StatisticsForAssignment{
+		{AverageGrade: 80, SubmissionRate: 1, GradedCount: 1},
+		{AverageGrade: 0, SubmissionRate: 1, GradedCount: 1},
+		{AverageGrade: 0, SubmissionRate: 1},
+	}
+
+	gradeTendency, submissionTendency, averageGrade := stp.calculateTendencyAndAverageGrade(stats)
+	assert.Equal(t, 40.0, averageGrade)
+	assert.Equal(t, "decrescent", gradeTendency)
+	assert.Equal(t, "stable", submissionTendency)
+}
+
+// fakeStatisticsRepository serves a course without assignments and keeps its statistics in memory
+type fakeStatisticsRepository struct {
+	repositories.CourseRepository
+	saved *model.CourseStatistics
+}
+
+func (f *fakeStatisticsRepository) GetByID(id uint) (*model.Course, error) {
+	course := &model.Course{Title: "Algorithms"}
+	course.ID = id
+	return course, nil
+}
+
+func (f *fakeStatisticsRepository) GetStudentsCount(courseID uint) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeStatisticsRepository) GetAssignmentsPreviews(courseID uint, userID string, userEmail string) ([]model.AssignmentPreview, error) {
+	return nil, nil
+}
+
+func (f *fakeStatisticsRepository) GetCourseStatistics(courseID uint) (model.CourseStatistics, error) {
+	if f.saved == nil {
+		return model.CourseStatistics{}, errors.New("record not found")
+	}
+	return *f.saved, nil
+}
+
+func (f *fakeStatisticsRepository) SaveCourseStatistics(statistics model.CourseStatistics, courseID uint) error {
+	f.saved = &statistics
+	return nil
+}
+
+// failingAnalyzer fails to generate suggestions, reporting the cancellation of ctx if any
+type failingAnalyzer struct {
+	ai.FeedbackAnalyzer
+}
+
+func (failingAnalyzer) GenerateCourseSuggestionsBasedOnStats(ctx context.Context, lastGradeTendency string, lastSubmissionRateTendency string, averageGrade float64, gradedCount int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New("provider unavailable")
+}
+
+func TestCourseStatistics_SuggestionErrors(t *testing.T) {
+	repo := &fakeStatisticsRepository{saved: &model.CourseStatistics{CourseID: 1, Suggestions: "Review recursion"}}
+	stp := NewStatisticsTaskProcessor(repo, failingAnalyzer{}, nil)
+	task := Task{Type: TaskTypeCourseStatistics, Data: CourseStatisticsTaskData{CourseID: 1}}
+
+	// A failing provider keeps the suggestions computed last time
+	assert.NoError(t, stp.ProcessTask(context.Background(), task))
+	assert.Equal(t, "Review recursion", repo.saved.Suggestions)
+
+	// A stopped queue saves nothing, so the task runs again
+	repo.saved = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, stp.ProcessTask(ctx, task), context.Canceled)
+	assert.Nil(t, repo.saved)
+}
@@ -2,28 +2,96 @@ package queue
 
 import (
 	"fmt"
+	"log"
+	"os"
 	"templateGo/internal/handlers/ai"
+	"templateGo/internal/model"
 	"templateGo/internal/repositories"
+	"templateGo/internal/storage"
+	"templateGo/internal/utils"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // StatisticsService manages the statistics calculation queue
 type StatisticsService struct {
-	taskQueue *TaskQueue
+	taskQueue Queue
+	debounce  time.Duration
+	maxWait   time.Duration
 }
 
-// NewStatisticsService creates a new statistics service
-func NewStatisticsService(repo repositories.CourseRepository, aiAnalyzer ai.FeedbackAnalyzer) *StatisticsService {
+// defaultStatisticsDebounce is how long a statistics task waits for more changes before running
+const defaultStatisticsDebounce = 5 * time.Second
+
+// defaultStatisticsMaxWait is the longest a statistics task waits while changes keep coalescing into it
+const defaultStatisticsMaxWait = time.Minute
+
+// similarityDebounce is how long a similarity check waits for more submissions before running,
+// longer than statistics since it compares every pair of submissions of the assignment
+const similarityDebounce = time.Minute
+
+// similarityMaxWait is the longest a similarity check waits while submissions keep coalescing into it
+const similarityMaxWait = 10 * time.Minute
+
+// StatisticsTaskTypes are the task types handled by the statistics service
+var StatisticsTaskTypes = []TaskType{
+	TaskTypeCourseStatistics,
+	TaskTypeUserCourseStatistics,
+	TaskTypeGlobalStatistics,
+	TaskTypeSubmissionSimilarity,
+}
+
+// NewStatisticsService creates a new statistics service.
+// Tasks are persisted in the database unless TASK_QUEUE_BACKEND is "memory" or there is no database connection.
+func NewStatisticsService(repo repositories.CourseRepository, aiAnalyzer ai.FeedbackAnalyzer, blobs storage.BlobStore) *StatisticsService {
 	// Create task processor
-	processor := NewStatisticsTaskProcessor(repo, aiAnalyzer)
+	processor := NewStatisticsTaskProcessor(repo, aiAnalyzer, blobs)
 
-	// Create task queue with 3 workers and buffer size of 100
-	taskQueue := NewTaskQueue(3, 100, processor)
+	var taskQueue Queue
+	if db := repositories.GetDB(); db != nil && os.Getenv("TASK_QUEUE_BACKEND") != "memory" {
+		taskQueue = NewPersistentTaskQueue(db, DefaultPersistentTaskQueueConfig(), processor)
+	} else {
+		// Create in-memory task queue with 3 workers and buffer size of 100
+		taskQueue = NewTaskQueue(3, 100, processor)
+	}
 
 	return &StatisticsService{
 		taskQueue: taskQueue,
+		debounce:  durationFromEnv("STATISTICS_DEBOUNCE", defaultStatisticsDebounce),
+		maxWait:   durationFromEnv("STATISTICS_MAX_WAIT", defaultStatisticsMaxWait),
+	}
+}
+
+// durationFromEnv reads a duration from an environment variable, or returns the default
+func durationFromEnv(name string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
 	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s %q, using %s: %v", name, value, defaultValue, err)
+		return defaultValue
+	}
+	return parsed
+}
+
+// Keys used to coalesce statistics tasks and to express dependencies between them
+func courseStatisticsKey(courseID uint) string {
+	return fmt.Sprintf("%s:%d", TaskTypeCourseStatistics, courseID)
+}
+
+func userCourseStatisticsKey(courseID uint, userID string) string {
+	return fmt.Sprintf("%s:%d:%s", TaskTypeUserCourseStatistics, courseID, userID)
+}
+
+func globalStatisticsKey(teacherEmail string) string {
+	return fmt.Sprintf("%s:%s", TaskTypeGlobalStatistics, teacherEmail)
+}
+
+func submissionSimilarityKey(assignmentID uint) string {
+	return fmt.Sprintf("%s:%d", TaskTypeSubmissionSimilarity, assignmentID)
 }
 
 // Start starts the statistics service
@@ -36,7 +104,8 @@ func (ss *StatisticsService) Stop() {
 	ss.taskQueue.Stop()
 }
 
-// EnqueueCourseStatisticsCalculation enqueues a course statistics calculation task
+// EnqueueCourseStatisticsCalculation enqueues a course statistics calculation task.
+// Calls for the same course within the debounce window are coalesced into a single task.
 func (ss *StatisticsService) EnqueueCourseStatisticsCalculation(courseID uint, userID, userEmail string) error {
 	task := Task{
 		ID:   fmt.Sprintf("course-stats-%d-%s", courseID, uuid.New().String()[:8]),
@@ -46,13 +115,17 @@ func (ss *StatisticsService) EnqueueCourseStatisticsCalculation(courseID uint, u
 			UserID:    userID,
 			UserEmail: userEmail,
 		},
+		Key:        courseStatisticsKey(courseID),
+		Delay:      ss.debounce,
+		MaxDelay:   ss.maxWait,
 		MaxRetries: 3,
 	}
 
 	return ss.taskQueue.EnqueueTask(task)
 }
 
-// EnqueueUserCourseStatisticsCalculation enqueues a user course statistics calculation task
+// EnqueueUserCourseStatisticsCalculation enqueues a user course statistics calculation task.
+// Calls for the same user and course within the debounce window are coalesced into a single task.
 func (ss *StatisticsService) EnqueueUserCourseStatisticsCalculation(courseID uint, userID, userEmail string) error {
 	task := Task{
 		ID:   fmt.Sprintf("user-stats-%d-%s-%s", courseID, userID, uuid.New().String()[:8]),
@@ -62,20 +135,52 @@ func (ss *StatisticsService) EnqueueUserCourseStatisticsCalculation(courseID uin
 			UserID:    userID,
 			UserEmail: userEmail,
 		},
+		Key:        userCourseStatisticsKey(courseID, userID),
+		Delay:      ss.debounce,
+		MaxDelay:   ss.maxWait,
 		MaxRetries: 3,
 	}
 
 	return ss.taskQueue.EnqueueTask(task)
 }
 
-// EnqueueGlobalStatisticsCalculation enqueues a global statistics calculation task
-func (ss *StatisticsService) EnqueueGlobalStatisticsCalculation(teacherEmail string) error {
+// EnqueueGlobalStatisticsCalculation enqueues a global statistics calculation task.
+// Calls for the same teacher within the debounce window are coalesced into a single task,
+// which only runs after the statistics of the given courses have been recalculated.
+func (ss *StatisticsService) EnqueueGlobalStatisticsCalculation(teacherEmail string, afterCourseIDs ...uint) error {
+	dependsOn := make([]string, 0, len(afterCourseIDs))
+	for _, courseID := range afterCourseIDs {
+		dependsOn = append(dependsOn, courseStatisticsKey(courseID))
+	}
+
 	task := Task{
 		ID:   fmt.Sprintf("global-stats-%s-%s", teacherEmail, uuid.New().String()[:8]),
 		Type: TaskTypeGlobalStatistics,
 		Data: GlobalStatisticsTaskData{
 			TeacherEmail: teacherEmail,
 		},
+		Key:        globalStatisticsKey(teacherEmail),
+		Delay:      ss.debounce,
+		MaxDelay:   ss.maxWait,
+		DependsOn:  dependsOn,
+		MaxRetries: 3,
+	}
+
+	return ss.taskQueue.EnqueueTask(task)
+}
+
+// EnqueueSubmissionSimilarityCheck enqueues the comparison of every pair of submissions of an assignment.
+// Calls for the same assignment within a minute are coalesced into a single check.
+func (ss *StatisticsService) EnqueueSubmissionSimilarityCheck(assignmentID uint) error {
+	task := Task{
+		ID:   fmt.Sprintf("similarity-%d-%s", assignmentID, uuid.New().String()[:8]),
+		Type: TaskTypeSubmissionSimilarity,
+		Data: SubmissionSimilarityTaskData{
+			AssignmentID: assignmentID,
+		},
+		Key:        submissionSimilarityKey(assignmentID),
+		Delay:      similarityDebounce,
+		MaxDelay:   similarityMaxWait,
 		MaxRetries: 3,
 	}
 
@@ -86,3 +191,27 @@ func (ss *StatisticsService) EnqueueGlobalStatisticsCalculation(teacherEmail str
 func (ss *StatisticsService) GetQueueSize() int {
 	return ss.taskQueue.GetQueueSize()
 }
+
+// ListFailedTasks returns the statistics tasks that exhausted their retries.
+// When no type is given every statistics task type is included.
+func (ss *StatisticsService) ListFailedTasks(types ...TaskType) ([]model.QueuedTask, error) {
+	deadLetter, ok := ss.taskQueue.(DeadLetterQueue)
+	if !ok {
+		return nil, utils.ErrDeadLetterDisabled
+	}
+
+	if len(types) == 0 {
+		types = StatisticsTaskTypes
+	}
+	return deadLetter.ListDeadTasks(types...)
+}
+
+// RequeueFailedTask schedules a failed statistics task to run again
+func (ss *StatisticsService) RequeueFailedTask(id string) error {
+	deadLetter, ok := ss.taskQueue.(DeadLetterQueue)
+	if !ok {
+		return utils.ErrDeadLetterDisabled
+	}
+
+	return deadLetter.RequeueDeadTask(id)
+}
@@ -1,5 +1,10 @@
 package queue
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // CourseStatisticsTaskData represents data for course statistics calculation task
 type CourseStatisticsTaskData struct {
 	CourseID  uint   `json:"course_id"`
@@ -18,3 +23,32 @@ type UserCourseStatisticsTaskData struct {
 type GlobalStatisticsTaskData struct {
 	TeacherEmail string `json:"teacher_email"`
 }
+
+// SubmissionSimilarityTaskData represents data for the similarity check of the submissions of an assignment
+type SubmissionSimilarityTaskData struct {
+	AssignmentID uint `json:"assignment_id"`
+}
+
+// decodeTaskData decodes a stored payload into the data type expected by the processor for the task type
+func decodeTaskData(taskType TaskType, payload []byte) (interface{}, error) {
+	switch taskType {
+	case TaskTypeCourseStatistics:
+		var data CourseStatisticsTaskData
+		err := json.Unmarshal(payload, &data)
+		return data, err
+	case TaskTypeUserCourseStatistics:
+		var data UserCourseStatisticsTaskData
+		err := json.Unmarshal(payload, &data)
+		return data, err
+	case TaskTypeGlobalStatistics:
+		var data GlobalStatisticsTaskData
+		err := json.Unmarshal(payload, &data)
+		return data, err
+	case TaskTypeSubmissionSimilarity:
+		var data SubmissionSimilarityTaskData
+		err := json.Unmarshal(payload, &data)
+		return data, err
+	default:
+		return nil, fmt.Errorf("unknown task type: %s", taskType)
+	}
+}
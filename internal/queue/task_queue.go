@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"templateGo/internal/model"
 	"time"
 )
 
@@ -15,6 +16,7 @@ const (
 	TaskTypeCourseStatistics     TaskType = "course_statistics"
 	TaskTypeUserCourseStatistics TaskType = "user_course_statistics"
 	TaskTypeGlobalStatistics     TaskType = "global_statistics"
+	TaskTypeSubmissionSimilarity TaskType = "submission_similarity"
 )
 
 // Task represents a task to be executed
@@ -22,12 +24,31 @@ type Task struct {
 	ID         string
 	Type       TaskType
 	Data       interface{}
+	Key        string        // pending tasks with the same key are coalesced into one
+	Delay      time.Duration // debounce window, restarted every time the task is coalesced
+	MaxDelay   time.Duration // longest the debounce window may hold the task back from its first enqueue, 0 for no limit
+	DependsOn  []string      // keys of the tasks that must finish before this one runs
 	CreatedAt  time.Time
 	Retries    int
 	MaxRetries int
 }
 
-// TaskQueue represents a queue for processing tasks
+// Queue is the contract shared by the task queue backends
+type Queue interface {
+	Start()
+	Stop()
+	EnqueueTask(task Task) error
+	GetQueueSize() int
+}
+
+// DeadLetterQueue is implemented by the backends that keep the tasks that exhausted their retries
+type DeadLetterQueue interface {
+	ListDeadTasks(types ...TaskType) ([]model.QueuedTask, error)
+	RequeueDeadTask(id string) error
+}
+
+// TaskQueue represents an in-memory queue for processing tasks.
+// Tasks are lost on restart, use PersistentTaskQueue when durability is needed.
 type TaskQueue struct {
 	tasks      chan Task
 	workers    int
@@ -37,11 +58,25 @@ type TaskQueue struct {
 	processor  TaskProcessor
 	mu         sync.RWMutex
 	running    bool
+
+	scheduleMu sync.Mutex
+	scheduled  map[string]*scheduledTask // tasks waiting for their debounce window or dependencies
+	active     map[string]int            // keys of the tasks in the channel or being processed
 }
 
-// TaskProcessor interface for processing tasks
+// scheduledTask is a task held back until its debounce window ends and its dependencies finish
+type scheduledTask struct {
+	task     Task
+	timer    *time.Timer
+	deadline time.Time // when the debounce window ends however often the task is coalesced, zero for never
+}
+
+// dependencyRecheckInterval is how often a task blocked by its dependencies is checked again
+const dependencyRecheckInterval = 500 * time.Millisecond
+
+// TaskProcessor interface for processing tasks. ctx is cancelled when the queue stops.
 type TaskProcessor interface {
-	ProcessTask(task Task) error
+	ProcessTask(ctx context.Context, task Task) error
 }
 
 // NewTaskQueue creates a new task queue
@@ -55,6 +90,8 @@ func NewTaskQueue(workers int, bufferSize int, processor TaskProcessor) *TaskQue
 		cancel:    cancel,
 		processor: processor,
 		running:   false,
+		scheduled: make(map[string]*scheduledTask),
+		active:    make(map[string]int),
 	}
 }
 
@@ -91,11 +128,46 @@ func (tq *TaskQueue) Stop() {
 	close(tq.tasks)
 	tq.workerPool.Wait()
 
+	tq.scheduleMu.Lock()
+	for key, scheduled := range tq.scheduled {
+		scheduled.timer.Stop()
+		delete(tq.scheduled, key)
+	}
+	tq.scheduleMu.Unlock()
+
 	log.Println("Task queue stopped")
 }
 
-// EnqueueTask adds a task to the queue
+// EnqueueTask adds a task to the queue.
+// Tasks with a key, a delay or dependencies are held back until they can run;
+// a held back task with the same key as a new one is coalesced into it.
 func (tq *TaskQueue) EnqueueTask(task Task) error {
+	task.CreatedAt = time.Now()
+	if task.MaxRetries == 0 {
+		task.MaxRetries = 3 // Default max retries
+	}
+
+	if task.Key == "" && task.Delay == 0 && len(task.DependsOn) == 0 {
+		if err := tq.send(task); err != nil {
+			return err
+		}
+		log.Printf("Task %s enqueued successfully", task.ID)
+		return nil
+	}
+
+	tq.mu.RLock()
+	running := tq.running
+	tq.mu.RUnlock()
+	if !running {
+		return fmt.Errorf("task queue is not running")
+	}
+
+	tq.schedule(task)
+	return nil
+}
+
+// send puts a task in the channel consumed by the workers
+func (tq *TaskQueue) send(task Task) error {
 	tq.mu.RLock()
 	defer tq.mu.RUnlock()
 
@@ -103,14 +175,8 @@ func (tq *TaskQueue) EnqueueTask(task Task) error {
 		return fmt.Errorf("task queue is not running")
 	}
 
-	task.CreatedAt = time.Now()
-	if task.MaxRetries == 0 {
-		task.MaxRetries = 3 // Default max retries
-	}
-
 	select {
 	case tq.tasks <- task:
-		log.Printf("Task %s enqueued successfully", task.ID)
 		return nil
 	case <-tq.ctx.Done():
 		return fmt.Errorf("task queue is shutting down")
@@ -119,6 +185,121 @@ func (tq *TaskQueue) EnqueueTask(task Task) error {
 	}
 }
 
+// schedule holds a task back until its debounce window ends, coalescing it with a held back task with the same key
+func (tq *TaskQueue) schedule(task Task) {
+	tq.scheduleMu.Lock()
+	defer tq.scheduleMu.Unlock()
+
+	scheduleKey := task.Key
+	if scheduleKey == "" {
+		scheduleKey = "task:" + task.ID
+	}
+
+	if existing, ok := tq.scheduled[scheduleKey]; ok {
+		existing.task.Data = task.Data
+		existing.task.DependsOn = mergeKeys(existing.task.DependsOn, task.DependsOn)
+		if existing.deadline.IsZero() {
+			existing.deadline = debounceDeadline(task)
+		}
+		existing.timer.Reset(debounceWait(task.Delay, existing.deadline))
+		log.Printf("Task %s coalesced into task %s", task.ID, existing.task.ID)
+		return
+	}
+
+	scheduled := &scheduledTask{task: task, deadline: debounceDeadline(task)}
+	scheduled.timer = time.AfterFunc(task.Delay, func() { tq.release(scheduleKey, scheduled) })
+	tq.scheduled[scheduleKey] = scheduled
+	log.Printf("Task %s scheduled successfully", task.ID)
+}
+
+// debounceDeadline returns when the debounce window of a task enqueued now ends at the latest
+func debounceDeadline(task Task) time.Time {
+	if task.MaxDelay <= 0 {
+		return time.Time{}
+	}
+	return task.CreatedAt.Add(max(task.MaxDelay, task.Delay))
+}
+
+// debounceWait returns how long a coalesced task still waits: its delay, without going past the deadline
+func debounceWait(delay time.Duration, deadline time.Time) time.Duration {
+	if deadline.IsZero() {
+		return delay
+	}
+	return max(min(delay, time.Until(deadline)), 0)
+}
+
+// release moves a held back task to the channel once nothing it waits for is pending or running
+func (tq *TaskQueue) release(scheduleKey string, scheduled *scheduledTask) {
+	tq.scheduleMu.Lock()
+	if tq.scheduled[scheduleKey] != scheduled {
+		tq.scheduleMu.Unlock()
+		return
+	}
+	if tq.isBlocked(scheduled.task) {
+		scheduled.timer.Reset(dependencyRecheckInterval)
+		tq.scheduleMu.Unlock()
+		return
+	}
+	delete(tq.scheduled, scheduleKey)
+	task := scheduled.task
+	if task.Key != "" {
+		tq.active[task.Key]++
+	}
+	tq.scheduleMu.Unlock()
+
+	if err := tq.send(task); err != nil {
+		log.Printf("Failed to enqueue scheduled task %s: %v", task.ID, err)
+		tq.finish(task)
+	}
+}
+
+// isBlocked reports whether a task with the same key is running or a dependency is unfinished.
+// It must be called with scheduleMu held.
+func (tq *TaskQueue) isBlocked(task Task) bool {
+	if task.Key != "" && tq.active[task.Key] > 0 {
+		return true
+	}
+	for _, dependency := range task.DependsOn {
+		if _, pending := tq.scheduled[dependency]; pending || tq.active[dependency] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// finish releases the key of a task that left the queue
+func (tq *TaskQueue) finish(task Task) {
+	if task.Key == "" {
+		return
+	}
+
+	tq.scheduleMu.Lock()
+	defer tq.scheduleMu.Unlock()
+
+	tq.active[task.Key]--
+	if tq.active[task.Key] <= 0 {
+		delete(tq.active, task.Key)
+	}
+}
+
+// mergeKeys returns the union of two key lists, keeping their order
+func mergeKeys(current, added []string) []string {
+	merged := append([]string{}, current...)
+	for _, key := range added {
+		found := false
+		for _, existing := range merged {
+			if existing == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, key)
+		}
+	}
+	return merged
+}
+
 // worker processes tasks from the queue
 func (tq *TaskQueue) worker(workerID int) {
 	defer tq.workerPool.Done()
@@ -134,6 +315,7 @@ func (tq *TaskQueue) worker(workerID int) {
 			}
 
 			tq.processTask(workerID, task)
+			tq.finish(task)
 
 		case <-tq.ctx.Done():
 			log.Printf("Worker %d stopped: context canceled", workerID)
@@ -146,7 +328,7 @@ func (tq *TaskQueue) worker(workerID int) {
 func (tq *TaskQueue) processTask(workerID int, task Task) {
 	log.Printf("Worker %d processing task %s (type: %s)", workerID, task.ID, task.Type)
 
-	err := tq.processor.ProcessTask(task)
+	err := tq.processor.ProcessTask(tq.ctx, task)
 	if err != nil {
 		log.Printf("Worker %d failed to process task %s: %v", workerID, task.ID, err)
 
@@ -158,6 +340,10 @@ func (tq *TaskQueue) processTask(workerID int, task Task) {
 			// Add a small delay before retry
 			go func() {
 				time.Sleep(time.Second * time.Duration(task.Retries))
+				if tq.isSuperseded(task) {
+					log.Printf("Task %s superseded by a newer task with key %s", task.ID, task.Key)
+					return
+				}
 				if err := tq.EnqueueTask(task); err != nil {
 					log.Printf("Failed to re-enqueue task %s: %v", task.ID, err)
 				}
@@ -170,7 +356,23 @@ func (tq *TaskQueue) processTask(workerID int, task Task) {
 	}
 }
 
-// GetQueueSize returns the current queue size
+// isSuperseded reports whether a newer task with the same key is already waiting
+func (tq *TaskQueue) isSuperseded(task Task) bool {
+	if task.Key == "" {
+		return false
+	}
+
+	tq.scheduleMu.Lock()
+	defer tq.scheduleMu.Unlock()
+
+	_, waiting := tq.scheduled[task.Key]
+	return waiting
+}
+
+// GetQueueSize returns the current queue size, including the tasks held back
 func (tq *TaskQueue) GetQueueSize() int {
-	return len(tq.tasks)
+	tq.scheduleMu.Lock()
+	defer tq.scheduleMu.Unlock()
+
+	return len(tq.tasks) + len(tq.scheduled)
 }
@@ -0,0 +1,101 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingProcessor struct {
+	mu        sync.Mutex
+	processed []Task
+}
+
+func (p *recordingProcessor) ProcessTask(ctx context.Context, task Task) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.processed = append(p.processed, task)
+	return nil
+}
+
+func (p *recordingProcessor) tasks() []Task {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Task{}, p.processed...)
+}
+
+func TestTaskQueue_CoalescesTasksWithTheSameKey(t *testing.T) {
+	processor := &recordingProcessor{}
+	tq := NewTaskQueue(2, 10, processor)
+	tq.Start()
+	defer tq.Stop()
+
+	for i := uint(1); i <= 5; i++ {
+		err := tq.EnqueueTask(Task{
+			ID:    "course-stats",
+			Type:  TaskTypeCourseStatistics,
+			Data:  CourseStatisticsTaskData{CourseID: i},
+			Key:   courseStatisticsKey(1),
+			Delay: 50 * time.Millisecond,
+		})
+		assert.NoError(t, err)
+	}
+
+	assert.Eventually(t, func() bool { return len(processor.tasks()) == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	processed := processor.tasks()
+	assert.Len(t, processed, 1)
+	assert.Equal(t, CourseStatisticsTaskData{CourseID: 5}, processed[0].Data)
+}
+
+func TestTaskQueue_RunsDependentTaskAfterItsDependencies(t *testing.T) {
+	processor := &recordingProcessor{}
+	tq := NewTaskQueue(2, 10, processor)
+	tq.Start()
+	defer tq.Stop()
+
+	assert.NoError(t, tq.EnqueueTask(Task{
+		ID:    "course-stats",
+		Type:  TaskTypeCourseStatistics,
+		Key:   courseStatisticsKey(1),
+		Delay: 100 * time.Millisecond,
+	}))
+	assert.NoError(t, tq.EnqueueTask(Task{
+		ID:        "global-stats",
+		Type:      TaskTypeGlobalStatistics,
+		Key:       globalStatisticsKey("teacher@example.com"),
+		DependsOn: []string{courseStatisticsKey(1)},
+	}))
+
+	assert.Eventually(t, func() bool { return len(processor.tasks()) == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	processed := processor.tasks()
+	assert.Equal(t, TaskTypeCourseStatistics, processed[0].Type)
+	assert.Equal(t, TaskTypeGlobalStatistics, processed[1].Type)
+}
+
+func TestTaskQueue_CoalescingDoesNotDelayPastMaxDelay(t *testing.T) {
+	processor := &recordingProcessor{}
+	tq := NewTaskQueue(2, 10, processor)
+	tq.Start()
+	defer tq.Stop()
+
+	// Changes keep arriving within the debounce window for longer than the maximum delay
+	stop := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(stop) {
+		assert.NoError(t, tq.EnqueueTask(Task{
+			ID:       "course-stats",
+			Type:     TaskTypeCourseStatistics,
+			Key:      courseStatisticsKey(1),
+			Delay:    50 * time.Millisecond,
+			MaxDelay: 150 * time.Millisecond,
+		}))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.GreaterOrEqual(t, len(processor.tasks()), 2)
+}
@@ -0,0 +1,124 @@
+// Package reminders reminds the students of the assignments they did not submit yet before their deadlines.
+package reminders
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"templateGo/internal/repositories"
+	"time"
+)
+
+// DefaultOffsets are how long before a deadline students are reminded when REMINDER_OFFSETS is not set
+var DefaultOffsets = []time.Duration{48 * time.Hour, 2 * time.Hour}
+
+// Notifier reminds a student of a deadline. notification.NotificationClient satisfies it.
+type Notifier interface {
+	SendDeadlineReminder(userID, courseName, assignmentTitle string, deadline time.Time)
+}
+
+// Sender finds the deadlines that are close and reminds the students who did not submit
+type Sender struct {
+	repo     repositories.CourseRepository
+	notifier Notifier
+	offsets  []time.Duration
+}
+
+// NewSender creates a sender that reminds students at each of the offsets before a deadline
+func NewSender(repo repositories.CourseRepository, notifier Notifier, offsets []time.Duration) *Sender {
+	sorted := append([]time.Duration{}, offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &Sender{repo: repo, notifier: notifier, offsets: sorted}
+}
+
+// OffsetsFromEnv reads the offsets in REMINDER_OFFSETS, durations separated by commas such as "48h,2h",
+// or returns DefaultOffsets
+func OffsetsFromEnv() []time.Duration {
+	value := os.Getenv("REMINDER_OFFSETS")
+	if value == "" {
+		return DefaultOffsets
+	}
+	offsets, err := ParseOffsets(value)
+	if err != nil {
+		log.Printf("Invalid REMINDER_OFFSETS %q, using the default: %v", value, err)
+		return DefaultOffsets
+	}
+	return offsets
+}
+
+// ParseOffsets parses durations separated by commas, which must be positive and different
+func ParseOffsets(value string) ([]time.Duration, error) {
+	var offsets []time.Duration
+	seen := make(map[time.Duration]bool)
+	for _, part := range strings.Split(value, ",") {
+		offset, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if offset <= 0 {
+			return nil, fmt.Errorf("offset %s is not positive", offset)
+		}
+		// Reminders are recorded by minute
+		if offset%time.Minute != 0 {
+			return nil, fmt.Errorf("offset %s is not a whole number of minutes", offset)
+		}
+		if seen[offset] {
+			return nil, fmt.Errorf("offset %s is repeated", offset)
+		}
+		seen[offset] = true
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+// DueOffset returns the offset whose reminder is due for a deadline by now: the shortest one already reached.
+// Reminders of longer offsets that were missed, such as for assignments created close to their deadline, are
+// not sent once a shorter one is due. It returns false when no reminder is due.
+func DueOffset(offsets []time.Duration, deadline, now time.Time) (time.Duration, bool) {
+	if !now.Before(deadline) {
+		return 0, false
+	}
+	for _, offset := range offsets {
+		if !now.Before(deadline.Add(-offset)) {
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+// SendDueReminders reminds the students of the deadlines due within the longest offset and returns how many
+// reminders it sent. Each reminder is recorded before it is sent, so none is sent twice, even by several
+// instances of the service or after a restart.
+func (s *Sender) SendDueReminders(now time.Time) (int, error) {
+	if len(s.offsets) == 0 {
+		return 0, nil
+	}
+	due, err := s.repo.GetAssignmentsDueBetween(now, now.Add(s.offsets[len(s.offsets)-1]))
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving assignments due: %w", err)
+	}
+
+	var firstErr error
+	sent := 0
+	for _, assignment := range due {
+		offset, ok := DueOffset(s.offsets, assignment.Deadline, now)
+		if !ok {
+			continue
+		}
+		userIDs, err := s.repo.ClaimDeadlineReminders(assignment.AssignmentID, int(offset/time.Minute), now)
+		if err != nil {
+			log.Printf("Error recording the reminders of assignment %d: %v", assignment.AssignmentID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, userID := range userIDs {
+			s.notifier.SendDeadlineReminder(userID, assignment.CourseTitle, assignment.Title, assignment.Deadline)
+		}
+		sent += len(userIDs)
+	}
+	return sent, firstErr
+}
@@ -0,0 +1,123 @@
+package reminders
+
+import (
+	"fmt"
+	"templateGo/internal/model"
+	"templateGo/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository records the reminders claimed, as the unique index of deadline_reminders does
+type fakeRepository struct {
+	repositories.CourseRepository
+	due      []model.DueAssignment
+	pending  map[uint][]string // students without a submission, by assignment
+	reminded map[string]bool
+}
+
+func (f *fakeRepository) GetAssignmentsDueBetween(from, to time.Time) ([]model.DueAssignment, error) {
+	var due []model.DueAssignment
+	for _, assignment := range f.due {
+		if assignment.Deadline.After(from) && !assignment.Deadline.After(to) {
+			due = append(due, assignment)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeRepository) ClaimDeadlineReminders(assignmentID uint, offsetMinutes int, now time.Time) ([]string, error) {
+	var claimed []string
+	for _, userID := range f.pending[assignmentID] {
+		key := fmt.Sprintf("%d/%s/%d", assignmentID, userID, offsetMinutes)
+		if !f.reminded[key] {
+			f.reminded[key] = true
+			claimed = append(claimed, userID)
+		}
+	}
+	return claimed, nil
+}
+
+type sentReminder struct {
+	userID, course, assignment string
+}
+
+type fakeNotifier struct {
+	sent []sentReminder
+}
+
+func (f *fakeNotifier) SendDeadlineReminder(userID, courseName, assignmentTitle string, deadline time.Time) {
+	f.sent = append(f.sent, sentReminder{userID, courseName, assignmentTitle})
+}
+
+func TestParseOffsets(t *testing.T) {
+	offsets, err := ParseOffsets("48h, 2h,30m")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{48 * time.Hour, 2 * time.Hour, 30 * time.Minute}, offsets)
+
+	for _, invalid := range []string{"", "2h,tomorrow", "-1h", "2h,120m", "90s"} {
+		_, err := ParseOffsets(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestOffsetsFromEnv(t *testing.T) {
+	t.Setenv("REMINDER_OFFSETS", "24h")
+	assert.Equal(t, []time.Duration{24 * time.Hour}, OffsetsFromEnv())
+
+	t.Setenv("REMINDER_OFFSETS", "0h")
+	assert.Equal(t, DefaultOffsets, OffsetsFromEnv())
+}
+
+func TestDueOffset(t *testing.T) {
+	offsets := []time.Duration{2 * time.Hour, 48 * time.Hour}
+	deadline := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	_, ok := DueOffset(offsets, deadline, deadline.Add(-72*time.Hour))
+	assert.False(t, ok)
+
+	offset, ok := DueOffset(offsets, deadline, deadline.Add(-48*time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, 48*time.Hour, offset)
+
+	// Only the shortest offset reached is due
+	offset, ok = DueOffset(offsets, deadline, deadline.Add(-time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Hour, offset)
+
+	_, ok = DueOffset(offsets, deadline, deadline)
+	assert.False(t, ok)
+}
+
+func TestSender_SendDueReminders(t *testing.T) {
+	deadline := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRepository{
+		due: []model.DueAssignment{
+			{AssignmentID: 1, Title: "TP 1", Deadline: deadline, CourseTitle: "Algoritmos"},
+			{AssignmentID: 2, Title: "TP 2", Deadline: deadline.Add(7 * 24 * time.Hour), CourseTitle: "Algoritmos"},
+		},
+		pending:  map[uint][]string{1: {"u-1", "u-2"}, 2: {"u-1"}},
+		reminded: map[string]bool{},
+	}
+	notifier := &fakeNotifier{}
+	sender := NewSender(repo, notifier, []time.Duration{48 * time.Hour, 2 * time.Hour})
+
+	sent, err := sender.SendDueReminders(deadline.Add(-47 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, []sentReminder{{"u-1", "Algoritmos", "TP 1"}, {"u-2", "Algoritmos", "TP 1"}}, notifier.sent)
+
+	// Running again, as after a restart, sends nothing new
+	sent, err = sender.SendDueReminders(deadline.Add(-46 * time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// The reminder of the next offset goes out once
+	sent, err = sender.SendDueReminders(deadline.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Len(t, notifier.sent, 4)
+}
@@ -1,7 +1,7 @@
 package repositories
 
 import (
-	"templateGo/internal/model"
+	"templateGo/internal/repositories/migrations"
 
 	"gorm.io/gorm"
 )
@@ -16,26 +16,15 @@ type DatabaseManager interface {
 
 	// CloseDB closes the database connection
 	CloseDB() error
+
+	// MigrateDB applies the pending schema migrations
+	MigrateDB() error
+
+	// NewMigrator returns the schema migrator of the connected database
+	NewMigrator() (*migrations.Migrator, error)
 }
 
 // GetDB returns the global database instance
 func GetDB() *gorm.DB {
 	return DB
 }
-
-// Models that will be auto-migrated
-var ModelsToMigrate = []any{
-	&model.Course{},
-	&model.Enrollment{},
-	&model.CourseFeedback{},
-	&model.Assignment{},
-	&model.CourseApproval{},
-	&model.Submission{},
-	&model.AssignmentSession{},
-	&model.UserFeedback{},
-	&model.Module{},
-	&model.Resource{},
-	&model.CourseAnalytics{},
-	&model.UserCourseAnalytics{},
-	&model.GlobalStatistics{},
-}
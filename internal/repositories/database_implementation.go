@@ -1,10 +1,12 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"reflect"
+	"templateGo/internal/repositories/migrations"
 	"time"
 
 	"github.com/lib/pq"
@@ -52,11 +54,6 @@ func (pm *PostgresManager) ConnectDB() error {
 	DB.Callback().Create().Before("gorm:create").Register("pq_array_handler", arrayHandlerCreate)
 	DB.Callback().Update().Before("gorm:update").Register("pq_array_handler", arrayHandlerUpdate)
 
-	// Auto migrate models
-	if err := DB.AutoMigrate(ModelsToMigrate...); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
-	}
-
 	// Get the underlying SQL DB
 	sqlDB, err := DB.DB()
 	if err != nil {
@@ -72,6 +69,36 @@ func (pm *PostgresManager) ConnectDB() error {
 	return nil
 }
 
+// MigrateDB applies the pending schema migrations
+func (pm *PostgresManager) MigrateDB() error {
+	migrator, err := pm.NewMigrator()
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		return fmt.Errorf("error migrating database: %w", err)
+	}
+	if len(applied) == 0 {
+		log.Println("Database schema is up to date")
+	}
+	return nil
+}
+
+// NewMigrator returns the schema migrator of the connected database
+func (pm *PostgresManager) NewMigrator() (*migrations.Migrator, error) {
+	if DB == nil {
+		return nil, fmt.Errorf("database is not connected")
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("error getting SQL DB: %w", err)
+	}
+	return migrations.NewMigrator(sqlDB)
+}
+
 // CloseDB closes the database connection
 func (pm *PostgresManager) CloseDB() error {
 	if DB != nil {
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_EmbeddedMigrationsAreSortedAndComplete(t *testing.T) {
+	migrations, err := Load()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	// The columns of databases created by older versions of the models are added before the baseline
+	assert.Equal(t, int64(0), migrations[0].Version)
+	assert.Equal(t, "automigrate_columns", migrations[0].Name)
+	assert.Equal(t, int64(1), migrations[1].Version)
+	assert.Equal(t, "baseline", migrations[1].Name)
+
+	for i := 1; i < len(migrations); i++ {
+		assert.Less(t, migrations[i-1].Version, migrations[i].Version)
+	}
+}
+
+func TestLoad_RejectsIncompleteMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0001_baseline.up.sql":   {Data: []byte("CREATE TABLE a (id int);")},
+		"sql/0001_baseline.down.sql": {Data: []byte("DROP TABLE a;")},
+		"sql/0002_add_b.up.sql":      {Data: []byte("CREATE TABLE b (id int);")},
+	}
+
+	_, err := load(fsys, "sql")
+	assert.ErrorContains(t, err, "0002_add_b")
+
+	fsys["sql/0002_add_b.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE b;")}
+	migrations, err := load(fsys, "sql")
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+
+	fsys["sql/notes.txt"] = &fstest.MapFile{Data: []byte("")}
+	_, err = load(fsys, "sql")
+	assert.Error(t, err)
+}
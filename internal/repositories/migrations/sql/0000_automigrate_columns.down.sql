@@ -0,0 +1,19 @@
+-- Runs after 0001_baseline is reverted, so the tables are usually gone already.
+
+ALTER TABLE IF EXISTS "submissions" DROP COLUMN IF EXISTS "status";
+ALTER TABLE IF EXISTS "submissions" DROP COLUMN IF EXISTS "scores";
+ALTER TABLE IF EXISTS "submissions" DROP COLUMN IF EXISTS "rubric_id";
+ALTER TABLE IF EXISTS "submissions" DROP COLUMN IF EXISTS "graded_by";
+ALTER TABLE IF EXISTS "submissions" DROP COLUMN IF EXISTS "graded_at";
+ALTER TABLE IF EXISTS "submissions" DROP COLUMN IF EXISTS "late_penalty";
+ALTER TABLE IF EXISTS "submissions" DROP COLUMN IF EXISTS "days_late";
+ALTER TABLE IF EXISTS "submissions" DROP COLUMN IF EXISTS "late";
+
+ALTER TABLE IF EXISTS "assignments" DROP CONSTRAINT IF EXISTS "fk_assignments_rubric";
+ALTER TABLE IF EXISTS "assignments" DROP COLUMN IF EXISTS "rubric_id";
+ALTER TABLE IF EXISTS "assignments" DROP COLUMN IF EXISTS "grace_period";
+ALTER TABLE IF EXISTS "assignments" DROP COLUMN IF EXISTS "late_penalty_per_day";
+ALTER TABLE IF EXISTS "assignments" DROP COLUMN IF EXISTS "late_policy";
+
+ALTER TABLE IF EXISTS "submission_files" DROP COLUMN IF EXISTS "size";
+ALTER TABLE IF EXISTS "files" DROP COLUMN IF EXISTS "size";
@@ -0,0 +1,49 @@
+-- Brings databases created by AutoMigrate with older versions of the models up to 0001_baseline, which
+-- only creates the tables that are missing. Their tables lack the file size, late policy, rubric and grading
+-- state columns, and their submissions with a grade or feedback are marked as graded. It runs before the
+-- baseline, which indexes some of these columns, and does nothing on databases without tables.
+
+ALTER TABLE IF EXISTS "files" ADD COLUMN IF NOT EXISTS "size" bigint;
+ALTER TABLE IF EXISTS "submission_files" ADD COLUMN IF NOT EXISTS "size" bigint;
+
+ALTER TABLE IF EXISTS "assignments" ADD COLUMN IF NOT EXISTS "late_policy" text NOT NULL DEFAULT 'accept';
+ALTER TABLE IF EXISTS "assignments" ADD COLUMN IF NOT EXISTS "late_penalty_per_day" decimal;
+ALTER TABLE IF EXISTS "assignments" ADD COLUMN IF NOT EXISTS "grace_period" bigint;
+ALTER TABLE IF EXISTS "assignments" ADD COLUMN IF NOT EXISTS "rubric_id" bigint;
+DO $$
+BEGIN
+    IF to_regclass('assignments') IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_assignments_rubric' AND conrelid = 'assignments'::regclass
+    ) THEN
+        -- The same table 0001_baseline creates, needed here for the foreign key
+        CREATE TABLE IF NOT EXISTS "rubrics" (
+            "id" bigserial,
+            "course_id" bigint NOT NULL,
+            "title" text,
+            "criteria" jsonb NOT NULL,
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            PRIMARY KEY ("id")
+        );
+        ALTER TABLE "assignments" ADD CONSTRAINT "fk_assignments_rubric" FOREIGN KEY ("rubric_id") REFERENCES "rubrics"("id");
+    END IF;
+END $$;
+
+ALTER TABLE IF EXISTS "submissions" ADD COLUMN IF NOT EXISTS "late" boolean;
+ALTER TABLE IF EXISTS "submissions" ADD COLUMN IF NOT EXISTS "days_late" bigint;
+ALTER TABLE IF EXISTS "submissions" ADD COLUMN IF NOT EXISTS "late_penalty" decimal;
+ALTER TABLE IF EXISTS "submissions" ADD COLUMN IF NOT EXISTS "graded_at" timestamptz;
+ALTER TABLE IF EXISTS "submissions" ADD COLUMN IF NOT EXISTS "graded_by" text;
+ALTER TABLE IF EXISTS "submissions" ADD COLUMN IF NOT EXISTS "rubric_id" bigint;
+ALTER TABLE IF EXISTS "submissions" ADD COLUMN IF NOT EXISTS "scores" jsonb;
+-- Submissions from before the grading state was tracked are graded when they have a grade or feedback
+DO $$
+BEGIN
+    IF to_regclass('submissions') IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'submissions' AND column_name = 'status'
+    ) THEN
+        ALTER TABLE "submissions" ADD COLUMN "status" text NOT NULL DEFAULT 'submitted';
+        UPDATE "submissions" SET "status" = 'graded', "graded_at" = "submitted_at" WHERE "grade" > 0 OR "feedback" <> '';
+    END IF;
+END $$;
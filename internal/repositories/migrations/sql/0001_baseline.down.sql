@@ -0,0 +1,23 @@
+-- Drops the whole schema, in reverse dependency order.
+
+DROP TABLE IF EXISTS "grade_drafts";
+DROP TABLE IF EXISTS "queued_tasks";
+DROP TABLE IF EXISTS "waitlists";
+DROP TABLE IF EXISTS "global_statistics";
+DROP TABLE IF EXISTS "user_course_analytics";
+DROP TABLE IF EXISTS "course_analytics";
+DROP TABLE IF EXISTS "resources";
+DROP TABLE IF EXISTS "modules";
+DROP TABLE IF EXISTS "user_feedbacks";
+DROP TABLE IF EXISTS "assignment_sessions";
+DROP TABLE IF EXISTS "submission_files_join";
+DROP TABLE IF EXISTS "submissions";
+DROP TABLE IF EXISTS "course_approvals";
+DROP TABLE IF EXISTS "assignment_files";
+DROP TABLE IF EXISTS "assignments";
+DROP TABLE IF EXISTS "rubrics";
+DROP TABLE IF EXISTS "course_feedbacks";
+DROP TABLE IF EXISTS "enrollments";
+DROP TABLE IF EXISTS "courses";
+DROP TABLE IF EXISTS "submission_files";
+DROP TABLE IF EXISTS "files";
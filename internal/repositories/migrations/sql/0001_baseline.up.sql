@@ -0,0 +1,259 @@
+-- Baseline schema, equivalent to the one AutoMigrate created from the models.
+-- Every statement is idempotent so databases created by AutoMigrate are adopted as they are.
+
+CREATE TABLE IF NOT EXISTS "files" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "content" bytea NOT NULL,
+    "size" bigint,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "submission_files" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "content" bytea NOT NULL,
+    "size" bigint,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "courses" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "title" text,
+    "description" text,
+    "created_by" text,
+    "capacity" bigint,
+    "start_date" timestamptz,
+    "end_date" timestamptz,
+    "eligibility_criteria" text[],
+    "teaching_assistants" text[],
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_courses_deleted_at" ON "courses" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "enrollments" (
+    "id" bigserial,
+    "user_id" text,
+    "course_id" bigint,
+    "favorite" boolean DEFAULT false,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_enrollments_course_id" ON "enrollments" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_enrollments_user_id" ON "enrollments" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "course_feedbacks" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "user_id" text NOT NULL,
+    "rating" bigint NOT NULL,
+    "comment" text,
+    "summary" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_course_feedbacks_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id"),
+    CONSTRAINT "chk_course_feedbacks_rating" CHECK (rating >= 1 AND rating <= 5)
+);
+CREATE INDEX IF NOT EXISTS "idx_course_feedbacks_deleted_at" ON "course_feedbacks" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "rubrics" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "title" text,
+    "criteria" jsonb NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_rubrics_course_id" ON "rubrics" ("course_id");
+
+CREATE TABLE IF NOT EXISTS "assignments" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "title" text NOT NULL,
+    "description" text,
+    "deadline" timestamptz,
+    "time_limit" bigint,
+    "late_policy" text NOT NULL DEFAULT 'accept',
+    "late_penalty_per_day" decimal,
+    "grace_period" bigint,
+    "rubric_id" bigint,
+    "created_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_assignments_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id"),
+    CONSTRAINT "fk_assignments_rubric" FOREIGN KEY ("rubric_id") REFERENCES "rubrics"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_assignments_deleted_at" ON "assignments" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "assignment_files" (
+    "assignment_id" bigint,
+    "file_id" bigint,
+    PRIMARY KEY ("assignment_id","file_id"),
+    CONSTRAINT "fk_assignment_files_assignment" FOREIGN KEY ("assignment_id") REFERENCES "assignments"("id"),
+    CONSTRAINT "fk_assignment_files_file" FOREIGN KEY ("file_id") REFERENCES "files"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "course_approvals" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" text,
+    "course_id" bigint,
+    "course_name" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_course_approvals_course_id" ON "course_approvals" ("course_id");
+CREATE INDEX IF NOT EXISTS "idx_course_approvals_user_id" ON "course_approvals" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_course_approvals_deleted_at" ON "course_approvals" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "submissions" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "assignment_id" bigint NOT NULL,
+    "user_id" text NOT NULL,
+    "content" text NOT NULL,
+    "submitted_at" timestamptz,
+    "late" boolean,
+    "days_late" bigint,
+    "late_penalty" decimal,
+    "grade" bigint,
+    "feedback" text,
+    "status" text NOT NULL DEFAULT 'submitted',
+    "graded_at" timestamptz,
+    "graded_by" text,
+    "rubric_id" bigint,
+    "scores" jsonb,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_submissions_grade" CHECK (grade >= 0 AND grade <= 100)
+);
+CREATE INDEX IF NOT EXISTS "idx_submissions_status" ON "submissions" ("status");
+
+CREATE TABLE IF NOT EXISTS "submission_files_join" (
+    "submission_id" bigint,
+    "submission_file_id" bigint,
+    PRIMARY KEY ("submission_id","submission_file_id"),
+    CONSTRAINT "fk_submission_files_join_submission" FOREIGN KEY ("submission_id") REFERENCES "submissions"("id"),
+    CONSTRAINT "fk_submission_files_join_submission_file" FOREIGN KEY ("submission_file_id") REFERENCES "submission_files"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "assignment_sessions" (
+    "id" bigserial,
+    "user_id" text NOT NULL,
+    "assignment_id" bigint NOT NULL,
+    "started_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "user_feedbacks" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "course_id" bigint,
+    "student_id" text,
+    "course_title" text,
+    "comment" text,
+    "rating" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_feedbacks_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_feedbacks_deleted_at" ON "user_feedbacks" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "modules" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "order" bigint NOT NULL DEFAULT 0,
+    "name" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_modules_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "resources" (
+    "id" text,
+    "module_id" bigint NOT NULL,
+    "order" bigint NOT NULL DEFAULT 0,
+    "type" text NOT NULL,
+    "url" text NOT NULL,
+    "name" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_resources_module" FOREIGN KEY ("module_id") REFERENCES "modules"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "course_analytics" (
+    "course_id" bigserial,
+    "statistics" json,
+    PRIMARY KEY ("course_id"),
+    CONSTRAINT "fk_course_analytics_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "user_course_analytics" (
+    "user_id" text,
+    "course_id" bigint,
+    "statistics" json,
+    PRIMARY KEY ("user_id","course_id"),
+    CONSTRAINT "fk_user_course_analytics_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "global_statistics" (
+    "id" bigserial,
+    "teacher_email" text NOT NULL,
+    "global_average_grade" decimal DEFAULT 0,
+    "global_submission_rate" decimal DEFAULT 0,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_global_statistics_teacher_email" ON "global_statistics" ("teacher_email");
+
+CREATE TABLE IF NOT EXISTS "waitlists" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "user_id" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_waitlists_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_waitlist_course_user" ON "waitlists" ("course_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "queued_tasks" (
+    "id" text,
+    "type" text NOT NULL,
+    "payload" jsonb,
+    "dedup_key" text,
+    "depends_on" text[],
+    "status" text NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "max_attempts" bigint NOT NULL DEFAULT 3,
+    "available_at" timestamptz NOT NULL,
+    "locked_by" text,
+    "locked_until" timestamptz,
+    "last_error" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_queued_tasks_claim" ON "queued_tasks" ("status","available_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_queued_tasks_pending_dedup_key" ON "queued_tasks" ("dedup_key") WHERE status = 'pending' AND dedup_key <> '';
+CREATE INDEX IF NOT EXISTS "idx_queued_tasks_type" ON "queued_tasks" ("type");
+
+CREATE TABLE IF NOT EXISTS "grade_drafts" (
+    "id" bigserial,
+    "submission_id" bigint NOT NULL,
+    "rubric_id" bigint,
+    "scores" jsonb,
+    "grade" bigint,
+    "feedback" text,
+    "provider" text,
+    "status" text NOT NULL DEFAULT 'draft',
+    "accepted_by" text,
+    "accepted_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_grade_drafts_submission" FOREIGN KEY ("submission_id") REFERENCES "submissions"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_grade_drafts_submission_id" ON "grade_drafts" ("submission_id");
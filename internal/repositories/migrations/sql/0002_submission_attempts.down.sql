@@ -0,0 +1,10 @@
+-- Drops the attempt history, submissions keep their latest attempt.
+
+DROP TABLE IF EXISTS "submission_attempt_files";
+DROP TABLE IF EXISTS "submission_attempts";
+
+ALTER TABLE "submissions" DROP COLUMN IF EXISTS "final_grade";
+ALTER TABLE "submissions" DROP COLUMN IF EXISTS "attempt";
+
+ALTER TABLE "assignments" DROP COLUMN IF EXISTS "attempt_policy";
+ALTER TABLE "assignments" DROP COLUMN IF EXISTS "max_attempts";
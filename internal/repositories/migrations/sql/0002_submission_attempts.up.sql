@@ -0,0 +1,50 @@
+-- Keeps every attempt of a submission and the attempt policy of assignments.
+
+ALTER TABLE "assignments" ADD COLUMN "max_attempts" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "assignments" ADD COLUMN "attempt_policy" text NOT NULL DEFAULT 'last';
+
+ALTER TABLE "submissions" ADD COLUMN "attempt" bigint NOT NULL DEFAULT 1;
+ALTER TABLE "submissions" ADD COLUMN "final_grade" bigint;
+
+CREATE TABLE "submission_attempts" (
+    "id" bigserial,
+    "submission_id" bigint NOT NULL,
+    "number" bigint NOT NULL,
+    "content" text NOT NULL,
+    "submitted_at" timestamptz,
+    "late" boolean,
+    "days_late" bigint,
+    "late_penalty" decimal,
+    "grade" bigint,
+    "feedback" text,
+    "status" text NOT NULL DEFAULT 'submitted',
+    "graded_at" timestamptz,
+    "graded_by" text,
+    "rubric_id" bigint,
+    "scores" jsonb,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_submission_attempts_submission" FOREIGN KEY ("submission_id") REFERENCES "submissions"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_submission_attempt_number" ON "submission_attempts" ("submission_id","number");
+
+CREATE TABLE "submission_attempt_files" (
+    "submission_attempt_id" bigint,
+    "submission_file_id" bigint,
+    PRIMARY KEY ("submission_attempt_id","submission_file_id"),
+    CONSTRAINT "fk_submission_attempt_files_submission_attempt" FOREIGN KEY ("submission_attempt_id") REFERENCES "submission_attempts"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_submission_attempt_files_submission_file" FOREIGN KEY ("submission_file_id") REFERENCES "submission_files"("id")
+);
+
+-- Existing submissions become their first attempt
+INSERT INTO "submission_attempts" ("submission_id", "number", "content", "submitted_at", "late", "days_late", "late_penalty",
+    "grade", "feedback", "status", "graded_at", "graded_by", "rubric_id", "scores")
+SELECT "id", 1, "content", "submitted_at", "late", "days_late", "late_penalty",
+    "grade", "feedback", "status", "graded_at", "graded_by", "rubric_id", "scores"
+FROM "submissions";
+
+INSERT INTO "submission_attempt_files" ("submission_attempt_id", "submission_file_id")
+SELECT "submission_attempts"."id", "submission_files_join"."submission_file_id"
+FROM "submission_files_join"
+JOIN "submission_attempts" ON "submission_attempts"."submission_id" = "submission_files_join"."submission_id";
+
+UPDATE "submissions" SET "final_grade" = "grade" WHERE "status" IN ('graded', 'returned', 'regraded');
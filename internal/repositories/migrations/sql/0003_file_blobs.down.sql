@@ -0,0 +1,13 @@
+-- Contents already moved to the blob store are not copied back: those files are left empty.
+
+UPDATE "submission_files" SET "content" = '' WHERE "content" IS NULL;
+ALTER TABLE "submission_files" ALTER COLUMN "content" SET NOT NULL;
+DROP INDEX IF EXISTS "idx_submission_files_sha256";
+ALTER TABLE "submission_files" DROP COLUMN IF EXISTS "content_type";
+ALTER TABLE "submission_files" DROP COLUMN IF EXISTS "sha256";
+
+UPDATE "files" SET "content" = '' WHERE "content" IS NULL;
+ALTER TABLE "files" ALTER COLUMN "content" SET NOT NULL;
+DROP INDEX IF EXISTS "idx_files_sha256";
+ALTER TABLE "files" DROP COLUMN IF EXISTS "content_type";
+ALTER TABLE "files" DROP COLUMN IF EXISTS "sha256";
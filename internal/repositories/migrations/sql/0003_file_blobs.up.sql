@@ -0,0 +1,12 @@
+-- File contents move to the blob store, addressed by their SHA-256 digest.
+-- Existing contents stay in the content column until the service moves them on boot.
+
+ALTER TABLE "files" ADD COLUMN "sha256" text;
+ALTER TABLE "files" ADD COLUMN "content_type" text;
+ALTER TABLE "files" ALTER COLUMN "content" DROP NOT NULL;
+CREATE INDEX "idx_files_sha256" ON "files" ("sha256");
+
+ALTER TABLE "submission_files" ADD COLUMN "sha256" text;
+ALTER TABLE "submission_files" ADD COLUMN "content_type" text;
+ALTER TABLE "submission_files" ALTER COLUMN "content" DROP NOT NULL;
+CREATE INDEX "idx_submission_files_sha256" ON "submission_files" ("sha256");
@@ -0,0 +1,2 @@
+ALTER TABLE "assignments" DROP COLUMN IF EXISTS "max_file_size";
+ALTER TABLE "assignments" DROP COLUMN IF EXISTS "allowed_file_types";
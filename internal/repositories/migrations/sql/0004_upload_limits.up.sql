@@ -0,0 +1,4 @@
+-- Assignments restrict the types and size of the files submitted to them.
+
+ALTER TABLE "assignments" ADD COLUMN "allowed_file_types" jsonb;
+ALTER TABLE "assignments" ADD COLUMN "max_file_size" bigint NOT NULL DEFAULT 0;
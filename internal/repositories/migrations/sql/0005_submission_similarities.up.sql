@@ -0,0 +1,17 @@
+-- Pairwise similarity of the submissions of each assignment, replaced by every background check.
+
+CREATE TABLE "submission_similarities" (
+    "id" bigserial,
+    "assignment_id" bigint NOT NULL,
+    "submission_id" bigint NOT NULL,
+    "user_id" text NOT NULL,
+    "other_submission_id" bigint NOT NULL,
+    "other_user_id" text NOT NULL,
+    "score" decimal,
+    "matches" jsonb,
+    "checked_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_submission_similarities_submission" FOREIGN KEY ("submission_id") REFERENCES "submissions"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_submission_similarities_other_submission" FOREIGN KEY ("other_submission_id") REFERENCES "submissions"("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_submission_similarities_assignment_id" ON "submission_similarities" ("assignment_id");
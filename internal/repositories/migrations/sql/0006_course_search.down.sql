@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS resources_search_vector ON resources;
+DROP TRIGGER IF EXISTS modules_search_vector ON modules;
+DROP TRIGGER IF EXISTS courses_search_vector ON courses;
+DROP FUNCTION IF EXISTS resources_search_vector_trigger();
+DROP FUNCTION IF EXISTS modules_search_vector_trigger();
+DROP FUNCTION IF EXISTS refresh_course_search_vector(bigint);
+DROP FUNCTION IF EXISTS courses_search_vector_trigger();
+DROP FUNCTION IF EXISTS course_search_vector(bigint, text, text);
+DROP INDEX IF EXISTS "idx_courses_search_vector";
+ALTER TABLE "courses" DROP COLUMN IF EXISTS "search_vector";
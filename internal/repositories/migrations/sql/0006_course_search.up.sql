@@ -0,0 +1,67 @@
+-- Full-text search over the course catalog. The search vector of a course joins its title (weight A),
+-- description (B), module names (C) and resource names (D), and is kept up to date by triggers.
+-- It uses the simple configuration, without stemming, so prefix matching works the same in any language.
+
+ALTER TABLE "courses" ADD COLUMN "search_vector" tsvector;
+
+CREATE FUNCTION course_search_vector(course_id bigint, title text, description text) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce((
+            SELECT string_agg(m.name, ' ') FROM modules m WHERE m.course_id = course_search_vector.course_id
+        ), '')), 'C') ||
+        setweight(to_tsvector('simple', coalesce((
+            SELECT string_agg(r.name, ' ') FROM resources r JOIN modules m ON m.id = r.module_id
+            WHERE m.course_id = course_search_vector.course_id
+        ), '')), 'D')
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION courses_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := course_search_vector(NEW.id, NEW.title, NEW.description);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER courses_search_vector BEFORE INSERT OR UPDATE OF title, description ON courses
+    FOR EACH ROW EXECUTE FUNCTION courses_search_vector_trigger();
+
+-- refresh_course_search_vector recomputes the search vector of a course after its modules or resources change
+CREATE FUNCTION refresh_course_search_vector(id bigint) RETURNS void AS $$
+    UPDATE courses SET search_vector = course_search_vector(courses.id, courses.title, courses.description)
+    WHERE courses.id = refresh_course_search_vector.id
+$$ LANGUAGE sql;
+
+CREATE FUNCTION modules_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM refresh_course_search_vector(OLD.course_id);
+    END IF;
+    IF TG_OP <> 'DELETE' AND (TG_OP = 'INSERT' OR NEW.course_id <> OLD.course_id) THEN
+        PERFORM refresh_course_search_vector(NEW.course_id);
+    END IF;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER modules_search_vector AFTER INSERT OR DELETE OR UPDATE OF name, course_id ON modules
+    FOR EACH ROW EXECUTE FUNCTION modules_search_vector_trigger();
+
+CREATE FUNCTION resources_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM refresh_course_search_vector(course_id) FROM modules WHERE id = OLD.module_id;
+    END IF;
+    IF TG_OP <> 'DELETE' AND (TG_OP = 'INSERT' OR NEW.module_id <> OLD.module_id) THEN
+        PERFORM refresh_course_search_vector(course_id) FROM modules WHERE id = NEW.module_id;
+    END IF;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER resources_search_vector AFTER INSERT OR DELETE OR UPDATE OF name, module_id ON resources
+    FOR EACH ROW EXECUTE FUNCTION resources_search_vector_trigger();
+
+UPDATE courses SET search_vector = course_search_vector(id, title, description);
+
+CREATE INDEX "idx_courses_search_vector" ON "courses" USING gin ("search_vector");
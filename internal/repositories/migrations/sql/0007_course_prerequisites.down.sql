@@ -0,0 +1,9 @@
+ALTER TABLE "courses" ADD COLUMN "eligibility_criteria" text[];
+
+-- Groups with several courses cannot be expressed as criteria, every title becomes a required one
+UPDATE courses c SET eligibility_criteria = (
+    SELECT array_agg(DISTINCT r.title) FROM course_prerequisites p JOIN courses r ON r.id = p.required_course_id
+    WHERE p.course_id = c.id
+);
+
+DROP TABLE IF EXISTS "course_prerequisites";
@@ -0,0 +1,65 @@
+-- Prerequisites of courses as references to other courses, in groups: a course requires every one of
+-- its groups, and a group is met by approving any of its courses. They replace the free-text
+-- eligibility_criteria, which were matched against the names of the approved courses.
+
+CREATE TABLE "course_prerequisites" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "group_number" bigint NOT NULL,
+    "required_course_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_course_prerequisites_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_course_prerequisites_required_course" FOREIGN KEY ("required_course_id") REFERENCES "courses"("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_course_prerequisites_course_id" ON "course_prerequisites" ("course_id");
+CREATE UNIQUE INDEX "idx_course_prerequisites_group" ON "course_prerequisites" ("course_id", "group_number", "required_course_id");
+
+-- Dropping a criterion that matches no course would open the course to everyone, so the migration stops
+-- and lists them. They must be fixed to match a course title, or removed, before migrating again.
+DO $$
+DECLARE
+    unmatched text;
+BEGIN
+    SELECT string_agg(format('course %s requires %L', c.id, criterion.title), ', ' ORDER BY c.id, criterion.number)
+    INTO unmatched
+    FROM courses c
+    CROSS JOIN LATERAL unnest(c.eligibility_criteria) WITH ORDINALITY AS criterion(title, number)
+    WHERE c.deleted_at IS NULL AND NOT EXISTS (
+        SELECT 1 FROM courses r WHERE r.title = criterion.title AND r.id <> c.id AND r.deleted_at IS NULL
+    );
+    IF unmatched IS NOT NULL THEN
+        RAISE EXCEPTION 'eligibility criteria match no course title: %', unmatched
+            USING HINT = 'Change the criteria to the title of a course, or remove them, and migrate again';
+    END IF;
+END $$;
+
+-- Each criterion becomes a group with the courses of that title
+INSERT INTO "course_prerequisites" ("course_id", "group_number", "required_course_id")
+SELECT DISTINCT c.id, criterion.number - 1, r.id
+FROM courses c
+CROSS JOIN LATERAL unnest(c.eligibility_criteria) WITH ORDINALITY AS criterion(title, number)
+JOIN courses r ON r.title = criterion.title AND r.id <> c.id AND r.deleted_at IS NULL;
+
+-- Titles may have required each other, which course IDs cannot: the migration stops with the first cycle found,
+-- written as the API reports it
+DO $$
+DECLARE
+    cycle text;
+BEGIN
+    WITH RECURSIVE paths (start_id, course_id, path) AS (
+        SELECT DISTINCT p.course_id, p.required_course_id, ARRAY[p.course_id, p.required_course_id]
+        FROM course_prerequisites p
+        UNION ALL
+        SELECT paths.start_id, p.required_course_id, paths.path || p.required_course_id
+        FROM paths
+        JOIN course_prerequisites p ON p.course_id = paths.course_id
+        WHERE paths.course_id <> paths.start_id AND NOT p.required_course_id = ANY (paths.path[2:])
+    )
+    SELECT array_to_string(path, ' -> ') INTO cycle FROM paths WHERE course_id = start_id LIMIT 1;
+    IF cycle IS NOT NULL THEN
+        RAISE EXCEPTION 'migrated prerequisites would form a cycle: %', cycle
+            USING HINT = 'Remove one of the criteria of the cycle and migrate again';
+    END IF;
+END $$;
+
+ALTER TABLE "courses" DROP COLUMN "eligibility_criteria";
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "course_state_schedules";
+
+ALTER TABLE "courses" DROP COLUMN "state";
@@ -0,0 +1,21 @@
+-- Lifecycle states of courses and the changes of state scheduled for later. Existing courses
+-- stay open for enrollment, as they were before states existed.
+
+ALTER TABLE "courses" ADD COLUMN "state" text NOT NULL DEFAULT 'enrollment_open';
+CREATE INDEX "idx_courses_state" ON "courses" ("state");
+
+CREATE TABLE "course_state_schedules" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "state" text NOT NULL,
+    "run_at" timestamptz NOT NULL,
+    "status" text NOT NULL DEFAULT 'pending',
+    "error" text,
+    "created_by" text,
+    "created_at" timestamptz,
+    "processed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_course_state_schedules_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_course_state_schedules_course_id" ON "course_state_schedules" ("course_id");
+CREATE INDEX "idx_course_state_schedules_due" ON "course_state_schedules" ("run_at") WHERE "status" = 'pending';
@@ -0,0 +1,2 @@
+ALTER TABLE "courses" DROP COLUMN "cloned_from_id";
+ALTER TABLE "courses" DROP COLUMN "is_template";
@@ -0,0 +1,6 @@
+-- Courses marked as templates, which other teachers can clone, and the course each clone came from.
+
+ALTER TABLE "courses" ADD COLUMN "is_template" boolean NOT NULL DEFAULT false;
+ALTER TABLE "courses" ADD COLUMN "cloned_from_id" bigint;
+ALTER TABLE "courses" ADD CONSTRAINT "fk_courses_cloned_from" FOREIGN KEY ("cloned_from_id") REFERENCES "courses"("id") ON DELETE SET NULL;
+CREATE INDEX "idx_courses_is_template" ON "courses" ("id") WHERE "is_template";
@@ -0,0 +1,3 @@
+ALTER TABLE "assignments" DROP COLUMN "points";
+ALTER TABLE "assignments" DROP COLUMN "category_id";
+DROP TABLE IF EXISTS "grade_categories";
@@ -0,0 +1,19 @@
+-- Categories of the gradebook of each course, with their weight in the final grade, and the category and
+-- points of each assignment.
+
+CREATE TABLE "grade_categories" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "weight" decimal NOT NULL,
+    "drop_lowest" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_grade_categories_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_grade_categories_course_id" ON "grade_categories" ("course_id");
+
+ALTER TABLE "assignments" ADD COLUMN "category_id" bigint;
+ALTER TABLE "assignments" ADD COLUMN "points" decimal NOT NULL DEFAULT 100;
+ALTER TABLE "assignments" ADD CONSTRAINT "fk_assignments_category" FOREIGN KEY ("category_id") REFERENCES "grade_categories"("id") ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "resource_completions";
+ALTER TABLE "assignments" DROP COLUMN "mandatory";
+DROP TABLE IF EXISTS "approval_rules";
@@ -0,0 +1,30 @@
+-- Completion rules that approve the students of a course in bulk, the assignments every student must
+-- submit and the resources each student completed.
+
+CREATE TABLE "approval_rules" (
+    "id" bigserial,
+    "course_id" bigint NOT NULL,
+    "min_final_grade" decimal,
+    "require_mandatory_assignments" boolean NOT NULL DEFAULT false,
+    "min_resource_completion" decimal,
+    "auto_approve" boolean NOT NULL DEFAULT false,
+    "last_run_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_approval_rules_course" FOREIGN KEY ("course_id") REFERENCES "courses"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_approval_rules_course_id" ON "approval_rules" ("course_id");
+CREATE INDEX "idx_approval_rules_auto_approve" ON "approval_rules" ("auto_approve");
+
+ALTER TABLE "assignments" ADD COLUMN "mandatory" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "resource_completions" (
+    "id" bigserial,
+    "resource_id" text NOT NULL,
+    "user_id" text NOT NULL,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_resource_completions_resource" FOREIGN KEY ("resource_id") REFERENCES "resources"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_resource_completions_user" ON "resource_completions" ("resource_id", "user_id");
@@ -0,0 +1,16 @@
+-- Completion certificates of the students who approved a course. They copy what they certify and have no
+-- foreign key to the course, so they can still be verified after the course is deleted.
+
+CREATE TABLE "certificates" (
+    "id" text,
+    "user_id" text NOT NULL,
+    "course_id" bigint NOT NULL,
+    "course_title" text NOT NULL,
+    "student_name" text NOT NULL,
+    "teacher" text NOT NULL,
+    "issued_at" timestamptz NOT NULL,
+    "signature" text NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_certificates_user_course" ON "certificates" ("user_id", "course_id");
+CREATE INDEX "idx_certificates_course_id" ON "certificates" ("course_id");
@@ -0,0 +1,14 @@
+-- Calendar feeds users subscribe to from calendar apps, read with the secret token of their URL. Feeds of a
+-- deleted course are left behind and answer not found.
+
+CREATE TABLE "calendar_feeds" (
+    "id" bigserial,
+    "token" text NOT NULL,
+    "user_id" text NOT NULL,
+    "user_email" text NOT NULL,
+    "course_id" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_calendar_feeds_token" ON "calendar_feeds" ("token");
+CREATE UNIQUE INDEX "idx_calendar_feeds_user_course" ON "calendar_feeds" ("user_id", "course_id");
@@ -0,0 +1,12 @@
+-- Reminders of the deadlines of assignments sent to each student, so none is sent twice.
+
+CREATE TABLE "deadline_reminders" (
+    "id" bigserial,
+    "assignment_id" bigint NOT NULL,
+    "user_id" text NOT NULL,
+    "offset_minutes" bigint NOT NULL,
+    "sent_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_deadline_reminders_assignment" FOREIGN KEY ("assignment_id") REFERENCES "assignments"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_deadline_reminders_user_offset" ON "deadline_reminders" ("assignment_id", "user_id", "offset_minutes");
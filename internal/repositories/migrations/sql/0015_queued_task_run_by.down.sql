@@ -0,0 +1 @@
+ALTER TABLE "queued_tasks" DROP COLUMN IF EXISTS "run_by";
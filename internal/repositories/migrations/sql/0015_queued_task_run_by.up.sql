@@ -0,0 +1,4 @@
+-- Coalesced tasks run at the latest at run_by, set by their first enqueue, however often they are
+-- enqueued again within their debounce window.
+
+ALTER TABLE "queued_tasks" ADD COLUMN "run_by" timestamptz;
@@ -0,0 +1 @@
+ALTER TABLE "approval_rules" DROP COLUMN IF EXISTS "claimed_until";
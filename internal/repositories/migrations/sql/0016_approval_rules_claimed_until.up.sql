@@ -0,0 +1,5 @@
+-- Approval rules are claimed by an instance of the service until claimed_until, and marked as run with
+-- last_run_at only once they were applied, so a course claimed by an instance that dies mid-run is
+-- claimed again when the lease expires.
+
+ALTER TABLE "approval_rules" ADD COLUMN "claimed_until" timestamptz;
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sizes of the pages of list endpoints
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Page is a page of a list and the cursor of the next page, empty on the last one
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// sortKey is a column a list can be sorted by, and how to read it from an item to build cursors.
+// Columns must not be nullable, since NULL values cannot be compared with the cursor: nullable ones are
+// wrapped in COALESCE with the zero value the item reads for NULL.
+type sortKey[T any] struct {
+	column string
+	value  func(T) any // returns a time.Time, string, int, uint or float64
+}
+
+// coalesceTime sorts a nullable timestamp column as the zero time.Time when NULL, which is what its
+// items read for NULL and so what their cursors hold
+func coalesceTime(column string) string {
+	return fmt.Sprintf("COALESCE(%s, '0001-01-01 00:00:00+00')", column)
+}
+
+// pageCursor is the position after the last item of a page. Rows are ordered by the sort column and then
+// by ID, so positions are unique and stable while rows are added or removed between pages.
+type pageCursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+// paginate reads the page of a list from db, a query already narrowed by the filters of the list,
+// following the sort keys of the list. id returns the primary key of an item.
+func paginate[T any](db *gorm.DB, query model.PageQuery, defaultSort string, keys map[string]sortKey[T], id func(T) uint) (*Page[T], error) {
+	sort := query.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+	key, ok := keys[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", utils.ErrInvalidSort, sort)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	direction, after := "ASC", ">"
+	if strings.HasPrefix(sort, "-") {
+		direction, after = "DESC", "<"
+	}
+
+	if query.Cursor != "" {
+		var zero T
+		value, cursorID, err := decodeCursor(query.Cursor, sort, key.value(zero))
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", key.column, after), value, cursorID)
+	}
+
+	var items []T
+	if err := db.Order(key.column + " " + direction).Order("id " + direction).Limit(limit + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		cursor, err := encodeCursor(sort, key.value(last), id(last))
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
+	}
+	return page, nil
+}
+
+func encodeCursor(sort string, value any, id uint) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("error encoding cursor: %w", err)
+	}
+	cursor, err := json.Marshal(pageCursor{Sort: sort, Value: raw, ID: id})
+	if err != nil {
+		return "", fmt.Errorf("error encoding cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(cursor), nil
+}
+
+// decodeCursor returns the position of a cursor, read as the type of sample. Cursors of another sort
+// order are rejected, since their position means nothing in this one.
+func decodeCursor(encoded, sort string, sample any) (any, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, 0, utils.ErrInvalidCursor
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Sort != sort {
+		return nil, 0, utils.ErrInvalidCursor
+	}
+
+	var value any
+	switch sample.(type) {
+	case time.Time:
+		var v time.Time
+		err = json.Unmarshal(cursor.Value, &v)
+		value = v
+	case string:
+		var v string
+		err = json.Unmarshal(cursor.Value, &v)
+		value = v
+	case int:
+		var v int
+		err = json.Unmarshal(cursor.Value, &v)
+		value = v
+	case uint:
+		var v uint
+		err = json.Unmarshal(cursor.Value, &v)
+		value = v
+	case float64:
+		var v float64
+		err = json.Unmarshal(cursor.Value, &v)
+		value = v
+	default:
+		return nil, 0, fmt.Errorf("unsupported sort value %T", sample)
+	}
+	if err != nil {
+		return nil, 0, utils.ErrInvalidCursor
+	}
+	return value, cursor.ID, nil
+}
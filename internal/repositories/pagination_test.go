@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	submittedAt := time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC)
+
+	tests := []struct {
+		name   string
+		sort   string
+		value  any
+		sample any
+	}{
+		{"time", "-submitted_at", submittedAt, time.Time{}},
+		{"string", "title", "Álgebra _ 100%", ""},
+		{"int", "rating", 4, 0},
+		{"uint", "id", uint(42), uint(0)},
+		{"float", "score", 0.75, 0.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := encodeCursor(tt.sort, tt.value, 7)
+			assert.NoError(t, err)
+
+			value, id, err := decodeCursor(cursor, tt.sort, tt.sample)
+			assert.NoError(t, err)
+			assert.Equal(t, uint(7), id)
+			if expected, ok := tt.value.(time.Time); ok {
+				assert.True(t, expected.Equal(value.(time.Time)))
+			} else {
+				assert.Equal(t, tt.value, value)
+			}
+		})
+	}
+}
+
+func TestCursor_RejectsInvalidCursors(t *testing.T) {
+	cursor, err := encodeCursor("title", "Física", 3)
+	assert.NoError(t, err)
+
+	_, _, err = decodeCursor(cursor, "-title", "")
+	assert.ErrorIs(t, err, utils.ErrInvalidCursor, "a cursor of another sort order")
+
+	_, _, err = decodeCursor(cursor, "title", time.Time{})
+	assert.ErrorIs(t, err, utils.ErrInvalidCursor, "a value of another type")
+
+	_, _, err = decodeCursor("not a cursor!", "title", "")
+	assert.ErrorIs(t, err, utils.ErrInvalidCursor)
+}
+
+func TestPaginate_RejectsUnknownSortKeys(t *testing.T) {
+	_, err := paginate(nil, model.PageQuery{Sort: "-password"}, "id", courseSortKeys, func(c model.Course) uint { return c.ID })
+	assert.ErrorIs(t, err, utils.ErrInvalidSort)
+}
@@ -1,34 +1,101 @@
 package repositories
 
-import "templateGo/internal/model"
+import (
+	"templateGo/internal/model"
+	"time"
+)
 
 type CourseRepository interface {
 	Create(course *model.Course) error
 
 	GetByID(id uint) (*model.Course, error)
 
-	GetAll() ([]model.Course, error)
+	// ListCourses returns a page of the courses matching the filter
+	ListCourses(filter model.CourseFilter, page model.PageQuery) (*Page[model.Course], error)
+
+	// SearchCourses returns a page of the catalog courses matching a full-text search, most relevant first by default.
+	// The eligibility filter checks the prerequisites of the courses against the approvals of the user.
+	SearchCourses(search model.CourseSearchQuery, userID string, page model.PageQuery) (*Page[model.CourseSearchResult], error)
 
 	Update(course *model.Course) error
 
 	Delete(id uint) error
 
-	GetAvailableCourses(userID string) ([]model.Course, error)
+	// ListAvailableCourses returns a page of the courses open for enrollment matching the filter that a user
+	// is not enrolled in and whose prerequisites the user meets
+	ListAvailableCourses(userID string, filter model.CourseFilter, page model.PageQuery) (*Page[model.Course], error)
+
+	// SetCoursePrerequisites replaces the prerequisite groups of a course. It fails with ErrPrerequisiteInvalid
+	// when a required course does not exist and with ErrPrerequisiteCycle when the course would require itself.
+	SetCoursePrerequisites(courseID uint, groups [][]uint) error
+
+	// GetCourseEligibility explains which prerequisite groups of a course a user meets
+	GetCourseEligibility(courseID uint, userID string) (*model.Eligibility, error)
+
+	// CloneCourse creates the clone of a course with copies of its modules, resources, rubrics, grade categories,
+	// approval rules and assignments, whose deadlines move as much as the start date of the clone
+	CloneCourse(source *model.Course, clone *model.Course) error
+
+	// ChangeCourseState moves a course to another state, failing with utils.ErrInvalidTransition when
+	// the current state cannot move to it
+	ChangeCourseState(courseID uint, state string) (*model.Course, error)
+
+	// ScheduleCourseState schedules a change of state of a course, checked against the state the course has when it is due
+	ScheduleCourseState(schedule *model.CourseStateSchedule) error
+
+	// GetCourseStateSchedules retrieves the scheduled changes of state of a course, the next one first
+	GetCourseStateSchedules(courseID uint) ([]model.CourseStateSchedule, error)
+
+	// CancelCourseStateSchedule deletes a pending change of state, failing with utils.ErrScheduleNotFound
+	// when the course has no such pending change
+	CancelCourseStateSchedule(courseID, scheduleID uint) error
+
+	// ApplyDueCourseStates applies the pending changes of state due by now and returns how many it processed.
+	// Changes the course can no longer make are marked as failed.
+	ApplyDueCourseStates(now time.Time) (int, error)
 
 	GetEnrolledCourses(userID string) ([]model.Course, []bool, error)
 
 	IsUserEnrolled(courseID uint, userID string) (bool, error)
 
+	// IsTeacherOfStudent reports whether the user with the email owns or assists a course the student is enrolled in
+	IsTeacherOfStudent(userEmail, studentID string) (bool, error)
+
+	// EnrollUser enrolls a user, failing with utils.ErrPrerequisitesNotMet when the user does not meet the
+	// prerequisites of the course and with utils.ErrCourseFull when it has no seats left
 	EnrollUser(courseID uint, userID string) error
 
-	UnenrollUser(courseID uint, userID string) error
+	// UnenrollUser removes an enrollment and promotes the next waitlisted user into the freed seat.
+	// It returns the promoted user ID, or an empty string if nobody was promoted.
+	UnenrollUser(courseID uint, userID string) (string, error)
+
+	// JoinWaitlist adds a user to the waitlist of a full course and returns their position
+	JoinWaitlist(courseID uint, userID string) (int, error)
+
+	// LeaveWaitlist removes a user from the waitlist of a course
+	LeaveWaitlist(courseID uint, userID string) error
+
+	// GetWaitlistPosition returns the 1-based position of a user and the current waitlist size
+	GetWaitlistPosition(courseID uint, userID string) (int, int, error)
+
+	// GetWaitlist retrieves the waitlist of a course in promotion order
+	GetWaitlist(courseID uint) ([]model.Waitlist, error)
+
+	// PromoteFromWaitlist fills every free seat of a course from its waitlist and returns the promoted user IDs
+	PromoteFromWaitlist(courseID uint) ([]string, error)
 
 	GetCourseMembers(courseID uint) ([]map[string]any, error)
 
+	// ListCourseMembers returns a page of the enrollments of a course
+	ListCourseMembers(courseID uint, page model.PageQuery) (*Page[model.Enrollment], error)
+
 	CreateFeedback(feedback *model.CourseFeedback) error
 
 	GetFeedbacksForCourse(courseID uint) ([]model.CourseFeedback, error)
 
+	// ListCourseFeedbacks returns a page of the feedbacks of a course matching the filter, newest first by default
+	ListCourseFeedbacks(courseID uint, filter model.FeedbackFilter, page model.PageQuery) (*Page[model.CourseFeedback], error)
+
 	CreateAssignment(assignment *model.Assignment) error
 
 	UpdateAssignment(assignment *model.Assignment) error
@@ -43,7 +110,26 @@ type CourseRepository interface {
 
 	ToggleFavoriteStatus(courseID uint, userID string) error
 
-	PutSubmission(submission *model.Submission) error
+	// PutSubmission records a new attempt of the submission, or returns ErrMaxAttemptsReached when the assignment allows no more
+	PutSubmission(submission *model.Submission, assignment *model.Assignment) error
+
+	// SaveSubmissionGrade stores the grade of the submission, also in its latest attempt, and recomputes its final grade
+	SaveSubmissionGrade(submission *model.Submission) error
+
+	// GetSubmissionAttempts returns every attempt of a submission, oldest first
+	GetSubmissionAttempts(submissionID uint) ([]model.SubmissionAttempt, error)
+
+	// GetSubmissionAttempt returns an attempt of a submission by number, or ErrAttemptNotFound
+	GetSubmissionAttempt(submissionID uint, number int) (*model.SubmissionAttempt, error)
+
+	// GetSubmissionFile returns a file of any attempt of a submission, or ErrFileNotFound
+	GetSubmissionFile(submissionID, fileID uint) (*model.SubmissionFile, error)
+
+	// ReplaceSubmissionSimilarities replaces the stored similarity of the submissions of an assignment with a new check
+	ReplaceSubmissionSimilarities(assignmentID uint, similarities []model.SubmissionSimilarity) error
+
+	// GetSubmissionSimilarities returns the pairs of submissions of an assignment scoring at least minScore, most similar first
+	GetSubmissionSimilarities(assignmentID uint, minScore float64) ([]model.SubmissionSimilarity, error)
 
 	GetSubmissionByUserID(courseID, assignmentID uint, userID string) (*model.Submission, error)
 
@@ -51,12 +137,118 @@ type CourseRepository interface {
 
 	GetSubmissions(courseID, assignmentID uint) ([]model.Submission, error)
 
+	// ListSubmissions returns a page of the submissions of an assignment matching the filter
+	ListSubmissions(courseID, assignmentID uint, filter model.SubmissionFilter, page model.PageQuery) (*Page[model.Submission], error)
+
 	DeleteSubmission(submissionID uint) error
 
 	GetAssignmentByID(assignmentID uint) (*model.Assignment, error)
 
 	GetOrCreateAssignmentSession(userID string, assignmentID uint) (*model.AssignmentSession, error)
 
+	// Rubrics & AI grade drafts
+	CreateRubric(rubric *model.Rubric) error
+
+	GetRubricByID(rubricID uint) (*model.Rubric, error)
+
+	GetRubricsByCourse(courseID uint) ([]model.Rubric, error)
+
+	UpdateRubric(rubric *model.Rubric) error
+
+	// DeleteRubric removes a rubric, or returns ErrRubricInUse while an assignment uses it
+	DeleteRubric(rubricID uint) error
+
+	// SaveGradeDraft stores the draft as the latest AI grade of its submission
+	SaveGradeDraft(draft *model.GradeDraft) error
+
+	// GetGradeDraft returns the latest AI grade draft of a submission, or ErrGradeDraftNotFound
+	GetGradeDraft(submissionID uint) (*model.GradeDraft, error)
+
+	// AcceptGradeDraft marks the draft as accepted and applies its grade, minus any late penalty, and feedback to the submission.
+	// It fails with utils.ErrGradeDraftAccepted when the draft was already accepted.
+	AcceptGradeDraft(draft *model.GradeDraft) error
+
+	// Gradebook
+	CreateGradeCategory(category *model.GradeCategory) error
+
+	// GetGradeCategories returns the categories of the gradebook of a course in the order they were created
+	GetGradeCategories(courseID uint) ([]model.GradeCategory, error)
+
+	// GetGradeCategory returns a category of a gradebook, or utils.ErrCategoryNotFound
+	GetGradeCategory(categoryID uint) (*model.GradeCategory, error)
+
+	UpdateGradeCategory(category *model.GradeCategory) error
+
+	// DeleteGradeCategory removes a category, leaving its assignments without category
+	DeleteGradeCategory(categoryID uint) error
+
+	// SaveApprovalRules creates or replaces the approval rules of a course, keeping when they last ran
+	SaveApprovalRules(rules *model.ApprovalRules) error
+
+	// GetApprovalRules returns the approval rules of a course, or utils.ErrApprovalRulesNotFound
+	GetApprovalRules(courseID uint) (*model.ApprovalRules, error)
+
+	// ClaimDueApprovalRules returns the rules that approve automatically of the courses ended by now,
+	// not run yet and not claimed by another instance, claiming them for the lease
+	ClaimDueApprovalRules(now time.Time, lease time.Duration) ([]model.ApprovalRules, error)
+
+	// CompleteApprovalRules marks claimed rules as run at now
+	CompleteApprovalRules(rulesID uint, now time.Time) error
+
+	// ReleaseApprovalRules gives up the claim on rules not run, so they are claimed again
+	ReleaseApprovalRules(rulesID uint) error
+
+	// CompleteResource records that a student completed a resource, doing nothing if it was already recorded
+	CompleteResource(completion *model.ResourceCompletion) error
+
+	// GetResourceCompletion returns the number of resources of a course and how many each student completed
+	GetResourceCompletion(courseID uint) (int, map[string]int, error)
+
+	// CreateCertificate stores a certificate, doing nothing if the student already has one for the course
+	CreateCertificate(certificate *model.Certificate) error
+
+	// GetCertificate returns a certificate, or utils.ErrCertificateNotFound
+	GetCertificate(certificateID string) (*model.Certificate, error)
+
+	// GetUserCertificate returns the certificate of a student for a course, or utils.ErrCertificateNotFound
+	GetUserCertificate(courseID uint, userID string) (*model.Certificate, error)
+
+	// GetCertificatesOfUser returns the certificates of a student, newest first
+	GetCertificatesOfUser(userID string) ([]model.Certificate, error)
+
+	// GetCertificatesOfCourse returns the certificates issued for a course, newest first
+	GetCertificatesOfCourse(courseID uint) ([]model.Certificate, error)
+
+	// CreateCalendarFeed stores a calendar feed, doing nothing if the user already has one for the course
+	CreateCalendarFeed(feed *model.CalendarFeed) error
+
+	// GetCalendarFeed returns the calendar feed with a token, or utils.ErrCalendarFeedNotFound
+	GetCalendarFeed(token string) (*model.CalendarFeed, error)
+
+	// GetUserCalendarFeed returns the calendar feed of a user for a course, 0 for every course, or
+	// utils.ErrCalendarFeedNotFound
+	GetUserCalendarFeed(userID string, courseID uint) (*model.CalendarFeed, error)
+
+	// GetCalendarFeeds returns the calendar feeds of a user in the order they were created
+	GetCalendarFeeds(userID string) ([]model.CalendarFeed, error)
+
+	// DeleteCalendarFeed removes a calendar feed of a user, or fails with utils.ErrCalendarFeedNotFound
+	DeleteCalendarFeed(userID string, feedID uint) error
+
+	// GetCoursesAssignments returns the assignments of several courses
+	GetCoursesAssignments(courseIDs []uint) ([]model.Assignment, error)
+
+	// GetAssignmentSessions returns the timed sessions a user started in assignments
+	GetAssignmentSessions(userID string, assignmentIDs []uint) ([]model.AssignmentSession, error)
+
+	// GetAssignmentsDueBetween returns the assignments of courses not archived whose deadline is after from and
+	// at or before to
+	GetAssignmentsDueBetween(from, to time.Time) ([]model.DueAssignment, error)
+
+	// ClaimDeadlineReminders records a reminder of an assignment for every enrolled student without a submission
+	// not reminded yet at that offset, and returns who they are. Claimed students are not returned again.
+	ClaimDeadlineReminders(assignmentID uint, offsetMinutes int, now time.Time) ([]string, error)
+
 	// GetApprovedUsersForCourse retrieves all users approved for a specific course
 	GetApprovedUsersForCourse(courseID uint) ([]string, error)
 
@@ -66,6 +258,9 @@ type CourseRepository interface {
 	// GetUserFeedbacks retrieves all feedback for a specific user
 	GetUserFeedbacks(userID string) ([]model.UserFeedback, error)
 
+	// ListUserFeedbacks returns a page of the feedbacks for a user matching the filter, newest first by default
+	ListUserFeedbacks(userID string, filter model.FeedbackFilter, page model.PageQuery) (*Page[model.UserFeedback], error)
+
 	// CreateModule creates a new module for a course
 	CreateModule(module *model.Module) error
 
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"errors"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *courseRepository) SaveApprovalRules(rules *model.ApprovalRules) error {
+	return DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "course_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"min_final_grade", "require_mandatory_assignments", "min_resource_completion", "auto_approve", "updated_at",
+		}),
+	}).Create(rules).Error
+}
+
+func (r *courseRepository) GetApprovalRules(courseID uint) (*model.ApprovalRules, error) {
+	var rules model.ApprovalRules
+	err := DB.Where("course_id = ?", courseID).First(&rules).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrApprovalRulesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// ClaimDueApprovalRules claims the rules in the same statement that finds them, so each course is
+// claimed by a single instance of the service. The claim expires with the lease, so the rules of an
+// instance that died before completing them are claimed again.
+func (r *courseRepository) ClaimDueApprovalRules(now time.Time, lease time.Duration) ([]model.ApprovalRules, error) {
+	var rules []model.ApprovalRules
+	ended := DB.Model(&model.Course{}).Select("id").Where("end_date <= ?", now)
+	err := DB.Model(&rules).Clauses(clause.Returning{}).
+		Where("auto_approve AND last_run_at IS NULL AND (claimed_until IS NULL OR claimed_until <= ?) AND course_id IN (?)", now, ended).
+		Update("claimed_until", now.Add(lease)).Error
+	return rules, err
+}
+
+func (r *courseRepository) CompleteApprovalRules(rulesID uint, now time.Time) error {
+	return DB.Model(&model.ApprovalRules{}).Where("id = ?", rulesID).
+		Updates(map[string]interface{}{"last_run_at": now, "claimed_until": nil}).Error
+}
+
+func (r *courseRepository) ReleaseApprovalRules(rulesID uint) error {
+	return DB.Model(&model.ApprovalRules{}).Where("id = ?", rulesID).Update("claimed_until", nil).Error
+}
+
+func (r *courseRepository) CompleteResource(completion *model.ResourceCompletion) error {
+	return DB.Clauses(clause.OnConflict{DoNothing: true}).Create(completion).Error
+}
+
+func (r *courseRepository) GetResourceCompletion(courseID uint) (int, map[string]int, error) {
+	var total int64
+	err := DB.Model(&model.Resource{}).
+		Joins("JOIN modules ON modules.id = resources.module_id").
+		Where("modules.course_id = ?", courseID).
+		Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var rows []struct {
+		UserID    string
+		Completed int
+	}
+	err = DB.Model(&model.ResourceCompletion{}).
+		Select("resource_completions.user_id, COUNT(*) AS completed").
+		Joins("JOIN resources ON resources.id = resource_completions.resource_id").
+		Joins("JOIN modules ON modules.id = resources.module_id").
+		Where("modules.course_id = ?", courseID).
+		Group("resource_completions.user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	completed := make(map[string]int, len(rows))
+	for _, row := range rows {
+		completed[row.UserID] = row.Completed
+	}
+	return int(total), completed, nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"errors"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gradeColumns are the columns a grade changes, both in submissions and in their attempts
+var gradeColumns = []string{"grade", "late_penalty", "feedback", "rubric_id", "scores", "status", "graded_at", "graded_by"}
+
+func (r *courseRepository) SaveSubmissionGrade(submission *model.Submission) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(submission).Select(gradeColumns).Updates(submission).Error; err != nil {
+			return err
+		}
+
+		var assignment model.Assignment
+		if err := tx.First(&assignment, submission.AssignmentID).Error; err != nil {
+			return err
+		}
+		return saveAttemptGrade(tx, submission, &assignment)
+	})
+}
+
+func (r *courseRepository) GetSubmissionAttempts(submissionID uint) ([]model.SubmissionAttempt, error) {
+	var attempts []model.SubmissionAttempt
+	err := DB.Where("submission_id = ?", submissionID).Preload("Files").Order("number").Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *courseRepository) GetSubmissionAttempt(submissionID uint, number int) (*model.SubmissionAttempt, error) {
+	var attempt model.SubmissionAttempt
+	err := DB.Where("submission_id = ? AND number = ?", submissionID, number).Preload("Files").First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrAttemptNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// saveAttemptGrade copies the grade of the submission to its latest attempt and recomputes its final grade
+func saveAttemptGrade(tx *gorm.DB, submission *model.Submission, assignment *model.Assignment) error {
+	var attempt model.SubmissionAttempt
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("submission_id = ? AND number = ?", submission.ID, submission.Attempt).First(&attempt).Error; err != nil {
+		return err
+	}
+	attempt.CopyGrade(submission)
+	if err := tx.Model(&attempt).Select(gradeColumns).Updates(&attempt).Error; err != nil {
+		return err
+	}
+
+	return updateFinalGrade(tx, submission, assignment)
+}
+
+// updateFinalGrade recomputes the grade that counts for the submission from the grades of its attempts
+func updateFinalGrade(tx *gorm.DB, submission *model.Submission, assignment *model.Assignment) error {
+	var attempts []model.SubmissionAttempt
+	if err := tx.Select("number", "grade", "status").Where("submission_id = ?", submission.ID).
+		Order("number").Find(&attempts).Error; err != nil {
+		return err
+	}
+
+	submission.FinalGrade = assignment.FinalGrade(attempts)
+	return tx.Model(submission).Update("final_grade", submission.FinalGrade).Error
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"errors"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *courseRepository) CreateCalendarFeed(feed *model.CalendarFeed) error {
+	return DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "course_id"}},
+		DoNothing: true,
+	}).Create(feed).Error
+}
+
+func (r *courseRepository) GetCalendarFeed(token string) (*model.CalendarFeed, error) {
+	return findCalendarFeed(DB.Where("token = ?", token))
+}
+
+func (r *courseRepository) GetUserCalendarFeed(userID string, courseID uint) (*model.CalendarFeed, error) {
+	return findCalendarFeed(DB.Where("user_id = ? AND course_id = ?", userID, courseID))
+}
+
+func (r *courseRepository) GetCalendarFeeds(userID string) ([]model.CalendarFeed, error) {
+	feeds := []model.CalendarFeed{}
+	err := DB.Where("user_id = ?", userID).Order("id").Find(&feeds).Error
+	return feeds, err
+}
+
+func (r *courseRepository) DeleteCalendarFeed(userID string, feedID uint) error {
+	result := DB.Where("id = ? AND user_id = ?", feedID, userID).Delete(&model.CalendarFeed{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+func (r *courseRepository) GetCoursesAssignments(courseIDs []uint) ([]model.Assignment, error) {
+	assignments := []model.Assignment{}
+	if len(courseIDs) == 0 {
+		return assignments, nil
+	}
+	err := DB.Where("course_id IN ?", courseIDs).Order("deadline").Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *courseRepository) GetAssignmentSessions(userID string, assignmentIDs []uint) ([]model.AssignmentSession, error) {
+	sessions := []model.AssignmentSession{}
+	if len(assignmentIDs) == 0 {
+		return sessions, nil
+	}
+	err := DB.Where("user_id = ? AND assignment_id IN ?", userID, assignmentIDs).Find(&sessions).Error
+	return sessions, err
+}
+
+func findCalendarFeed(query *gorm.DB) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	err := query.First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
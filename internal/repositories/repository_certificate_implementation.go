@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"errors"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *courseRepository) CreateCertificate(certificate *model.Certificate) error {
+	return DB.Clauses(clause.OnConflict{DoNothing: true}).Create(certificate).Error
+}
+
+func (r *courseRepository) GetCertificate(certificateID string) (*model.Certificate, error) {
+	return findCertificate(DB.Where("id = ?", certificateID))
+}
+
+func (r *courseRepository) GetUserCertificate(courseID uint, userID string) (*model.Certificate, error) {
+	return findCertificate(DB.Where("course_id = ? AND user_id = ?", courseID, userID))
+}
+
+func (r *courseRepository) GetCertificatesOfUser(userID string) ([]model.Certificate, error) {
+	certificates := []model.Certificate{}
+	err := DB.Where("user_id = ?", userID).Order("issued_at DESC").Find(&certificates).Error
+	return certificates, err
+}
+
+func (r *courseRepository) GetCertificatesOfCourse(courseID uint) ([]model.Certificate, error) {
+	certificates := []model.Certificate{}
+	err := DB.Where("course_id = ?", courseID).Order("issued_at DESC").Find(&certificates).Error
+	return certificates, err
+}
+
+func findCertificate(query *gorm.DB) (*model.Certificate, error) {
+	var certificate model.Certificate
+	err := query.First(&certificate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrCertificateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"templateGo/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *courseRepository) CloneCourse(source *model.Course, clone *model.Course) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(clone).Error; err != nil {
+			return err
+		}
+		if err := replacePrerequisites(tx, clone.ID, clone.Prerequisites); err != nil {
+			return err
+		}
+		if err := cloneModules(tx, source.ID, clone.ID); err != nil {
+			return err
+		}
+		rubricIDs, err := cloneRubrics(tx, source.ID, clone.ID)
+		if err != nil {
+			return err
+		}
+		categoryIDs, err := cloneGradeCategories(tx, source.ID, clone.ID)
+		if err != nil {
+			return err
+		}
+		if err := cloneApprovalRules(tx, source.ID, clone.ID); err != nil {
+			return err
+		}
+		return cloneAssignments(tx, source, clone, rubricIDs, categoryIDs)
+	})
+}
+
+// cloneModules copies the modules of a course and their resources, in the same order. Resources are new
+// rows pointing to the same URLs, so uploaded files are shared instead of uploaded again.
+func cloneModules(tx *gorm.DB, sourceID, cloneID uint) error {
+	var modules []model.Module
+	if err := tx.Where("course_id = ?", sourceID).Order(`"order" ASC, id ASC`).Find(&modules).Error; err != nil {
+		return err
+	}
+
+	for _, module := range modules {
+		var resources []model.Resource
+		if err := tx.Where("module_id = ?", module.ID).Order(`"order" ASC, id ASC`).Find(&resources).Error; err != nil {
+			return err
+		}
+
+		copied := model.Module{CourseID: cloneID, Order: module.Order, Name: module.Name}
+		if err := tx.Omit(clause.Associations).Create(&copied).Error; err != nil {
+			return err
+		}
+		if len(resources) == 0 {
+			continue
+		}
+		for i := range resources {
+			resources[i].ID = uuid.New().String()
+			resources[i].ModuleID = copied.ID
+		}
+		if err := tx.Omit(clause.Associations).Create(&resources).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cloneRubrics copies the rubrics of a course and returns the ID of each copy by the ID of its original
+func cloneRubrics(tx *gorm.DB, sourceID, cloneID uint) (map[uint]uint, error) {
+	var rubrics []model.Rubric
+	if err := tx.Where("course_id = ?", sourceID).Order("id ASC").Find(&rubrics).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uint]uint, len(rubrics))
+	for _, rubric := range rubrics {
+		original := rubric.ID
+		rubric.ID = 0
+		rubric.CourseID = cloneID
+		rubric.CreatedAt, rubric.UpdatedAt = time.Time{}, time.Time{}
+		if err := tx.Create(&rubric).Error; err != nil {
+			return nil, err
+		}
+		ids[original] = rubric.ID
+	}
+	return ids, nil
+}
+
+// cloneGradeCategories copies the categories of the gradebook of a course and returns the ID of each copy
+// by the ID of its original
+func cloneGradeCategories(tx *gorm.DB, sourceID, cloneID uint) (map[uint]uint, error) {
+	var categories []model.GradeCategory
+	if err := tx.Where("course_id = ?", sourceID).Order("id ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uint]uint, len(categories))
+	for _, category := range categories {
+		original := category.ID
+		category.ID = 0
+		category.CourseID = cloneID
+		category.CreatedAt, category.UpdatedAt = time.Time{}, time.Time{}
+		if err := tx.Create(&category).Error; err != nil {
+			return nil, err
+		}
+		ids[original] = category.ID
+	}
+	return ids, nil
+}
+
+// cloneApprovalRules copies the approval rules of a course, if any, as not run yet
+func cloneApprovalRules(tx *gorm.DB, sourceID, cloneID uint) error {
+	var rules []model.ApprovalRules
+	if err := tx.Where("course_id = ?", sourceID).Find(&rules).Error; err != nil {
+		return err
+	}
+	for _, copied := range rules {
+		copied.ID = 0
+		copied.CourseID = cloneID
+		copied.LastRunAt, copied.ClaimedUntil = nil, nil
+		copied.CreatedAt, copied.UpdatedAt = time.Time{}, time.Time{}
+		if err := tx.Create(&copied).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cloneAssignments copies the assignments of a course with their files, moving their deadlines as much as
+// the start date of the clone moved. Files are new rows with the same content in the blob store.
+func cloneAssignments(tx *gorm.DB, source, clone *model.Course, rubricIDs, categoryIDs map[uint]uint) error {
+	var assignments []model.Assignment
+	if err := tx.Where("course_id = ?", source.ID).Preload("Files").Order("id ASC").Find(&assignments).Error; err != nil {
+		return err
+	}
+
+	shift := clone.DeadlineShift(source)
+	for _, assignment := range assignments {
+		assignment.ID = 0
+		assignment.CourseID = clone.ID
+		assignment.Deadline = assignment.Deadline.Add(shift)
+		assignment.CreatedAt = time.Time{}
+		if assignment.RubricID != nil {
+			// Rubrics of other courses are not copied, the clone cannot use them
+			if id, ok := rubricIDs[*assignment.RubricID]; ok {
+				assignment.RubricID = &id
+			} else {
+				assignment.RubricID = nil
+			}
+		}
+		if assignment.CategoryID != nil {
+			if id, ok := categoryIDs[*assignment.CategoryID]; ok {
+				assignment.CategoryID = &id
+			} else {
+				assignment.CategoryID = nil
+			}
+		}
+		for i := range assignment.Files {
+			assignment.Files[i].ID = 0
+		}
+
+		if err := tx.Omit("Course", "Rubric").Create(&assignment).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"templateGo/internal/model"
+	"templateGo/internal/storage"
+	"templateGo/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// legacyContentBatch is the number of files moved to the blob store per query
+const legacyContentBatch = 50
+
+func (r *courseRepository) GetSubmissionFile(submissionID, fileID uint) (*model.SubmissionFile, error) {
+	var file model.SubmissionFile
+	err := DB.Where("id = ?", fileID).
+		Where(DB.Where("id IN (?)", DB.Table("submission_files_join").Select("submission_file_id").Where("submission_id = ?", submissionID)).
+			Or("id IN (?)", DB.Table("submission_attempt_files").Select("submission_file_id").
+				Where("submission_attempt_id IN (?)", DB.Model(&model.SubmissionAttempt{}).Select("id").Where("submission_id = ?", submissionID)))).
+		First(&file).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// MoveFileContentsToStore moves the contents of files uploaded before the blob store existed,
+// still kept in the content column of the files and submission_files tables, to the blob store
+func MoveFileContentsToStore(ctx context.Context, store storage.BlobStore) error {
+	for _, table := range []string{"files", "submission_files"} {
+		moved := 0
+		for {
+			var files []struct {
+				ID      uint
+				Content []byte
+			}
+			if err := DB.WithContext(ctx).Table(table).Select("id", "content").
+				Where("sha256 IS NULL AND content IS NOT NULL").Order("id").Limit(legacyContentBatch).
+				Find(&files).Error; err != nil {
+				return fmt.Errorf("error reading %s: %w", table, err)
+			}
+			if len(files) == 0 {
+				break
+			}
+
+			for _, file := range files {
+				digest, err := storage.SaveBytes(ctx, store, file.Content)
+				if err != nil {
+					return fmt.Errorf("error storing content of %s %d: %w", table, file.ID, err)
+				}
+				if err := DB.WithContext(ctx).Table(table).Where("id = ?", file.ID).Updates(map[string]any{
+					"sha256":       digest,
+					"size":         len(file.Content),
+					"content_type": http.DetectContentType(file.Content),
+					"content":      nil,
+				}).Error; err != nil {
+					return fmt.Errorf("error updating %s %d: %w", table, file.ID, err)
+				}
+			}
+			moved += len(files)
+		}
+
+		if moved > 0 {
+			log.Printf("Moved the content of %d %s to the blob store", moved, table)
+		}
+	}
+	return nil
+}
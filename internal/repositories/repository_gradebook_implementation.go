@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"errors"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+func (r *courseRepository) CreateGradeCategory(category *model.GradeCategory) error {
+	return DB.Create(category).Error
+}
+
+func (r *courseRepository) GetGradeCategories(courseID uint) ([]model.GradeCategory, error) {
+	var categories []model.GradeCategory
+	err := DB.Where("course_id = ?", courseID).Order("id").Find(&categories).Error
+	return categories, err
+}
+
+func (r *courseRepository) GetGradeCategory(categoryID uint) (*model.GradeCategory, error) {
+	var category model.GradeCategory
+	err := DB.Where("id = ?", categoryID).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *courseRepository) UpdateGradeCategory(category *model.GradeCategory) error {
+	return DB.Model(category).Select("name", "weight", "drop_lowest").Updates(category).Error
+}
+
+// DeleteGradeCategory relies on the foreign key of assignments to leave them without category
+func (r *courseRepository) DeleteGradeCategory(categoryID uint) error {
+	return DB.Delete(&model.GradeCategory{}, categoryID).Error
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
 	"templateGo/internal/model"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"templateGo/internal/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type courseRepository struct {
@@ -19,31 +21,27 @@ func NewCourseRepository() *courseRepository {
 	return &courseRepository{db: DB}
 }
 
-// Crear curso
+// Crear curso con sus prerrequisitos
 func (r *courseRepository) Create(course *model.Course) error {
-	return r.db.Create(course).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(course).Error; err != nil {
+			return err
+		}
+		return replacePrerequisites(tx, course.ID, course.Prerequisites)
+	})
 }
 
 // Obtener curso por ID (sin los eliminados)
 func (r *courseRepository) GetByID(id uint) (*model.Course, error) {
 	var course model.Course
-	err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&course).Error
+	err := r.db.Where("id = ? AND deleted_at IS NULL", id).Preload("Prerequisites", orderPrerequisites).First(&course).Error
 	return &course, err
 }
 
-// Obtener todos los cursos disponibles
-func (r *courseRepository) GetAll() ([]model.Course, error) {
-	var tempCourses []model.Course
-	if err := r.db.Table("courses").Where("deleted_at IS NULL").Find(&tempCourses).Error; err != nil {
-		return nil, err
-	}
-
-	return tempCourses, nil
-}
-
-// Editar curso
+// Editar curso (los prerrequisitos se cambian con SetCoursePrerequisites)
 func (r *courseRepository) Update(course *model.Course) error {
-	return r.db.Save(course).Error
+	// The state only changes through ChangeCourseState, so a concurrent transition is not overwritten
+	return r.db.Omit(clause.Associations, "state").Save(course).Error
 }
 
 // Eliminación lógica del curso
@@ -51,18 +49,6 @@ func (r *courseRepository) Delete(id uint) error {
 	return r.db.Model(&model.Course{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
 }
 
-// Obtener cursos disponibles para un usuario (a implementar según criterios de elegibilidad)
-func (r *courseRepository) GetAvailableCourses(userID string) ([]model.Course, error) {
-	var courses []model.Course
-	// devolver todos los cursos en los que el alumno no esta inscripto
-	if err := r.db.Where("id NOT IN (SELECT course_id FROM enrollments WHERE user_id = ?)", userID).
-		Where("deleted_at IS NULL").Find(&courses).Error; err != nil {
-		return nil, err
-	}
-
-	return courses, nil
-}
-
 // Obtener cursos en los que un usuario está inscrito
 func (r *courseRepository) GetEnrolledCourses(userID string) ([]model.Course, []bool, error) {
 	var enrollments []model.Enrollment
@@ -83,7 +69,7 @@ func (r *courseRepository) GetEnrolledCourses(userID string) ([]model.Course, []
 	}
 
 	var courses []model.Course
-	if err := r.db.Where("id IN ?", courseIDs).Find(&courses).Error; err != nil {
+	if err := r.db.Where("id IN ?", courseIDs).Preload("Prerequisites", orderPrerequisites).Find(&courses).Error; err != nil {
 		return nil, nil, err
 	}
 
@@ -106,24 +92,84 @@ func (r *courseRepository) IsUserEnrolled(courseID uint, userID string) (bool, e
 	return count > 0, err
 }
 
-// Inscribir a un usuario en un curso
+func (r *courseRepository) IsTeacherOfStudent(userEmail, studentID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Course{}).
+		Joins("JOIN enrollments ON enrollments.course_id = courses.id").
+		Where("enrollments.user_id = ? AND (courses.created_by = ? OR ? = ANY(courses.teaching_assistants))", studentID, userEmail, userEmail).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+// Inscribir a un usuario en un curso, respetando el cupo del curso
 func (r *courseRepository) EnrollUser(courseID uint, userID string) error {
-	enrollment := model.Enrollment{
-		CourseID: courseID,
-		UserID:   userID,
-	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, courseID)
+		if err != nil {
+			return err
+		}
+		if !course.AcceptsEnrollments() {
+			return utils.ErrEnrollmentClosed
+		}
 
-	if err := r.db.Where("course_id = ? AND user_id = ?", courseID, userID).First(&enrollment).Error; err == nil {
-		return utils.ErrUserAlreadyEnrolled
-	}
+		var count int64
+		if err := tx.Model(&model.Enrollment{}).
+			Where("course_id = ? AND user_id = ?", courseID, userID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return utils.ErrUserAlreadyEnrolled
+		}
+
+		met, err := meetsPrerequisites(tx, courseID, userID)
+		if err != nil {
+			return err
+		}
+		if !met {
+			return utils.ErrPrerequisitesNotMet
+		}
+
+		freeSeats, err := countFreeSeats(tx, course)
+		if err != nil {
+			return err
+		}
+		if freeSeats == 0 {
+			return utils.ErrCourseFull
+		}
+
+		// A user that gets a seat directly no longer needs to wait for one
+		if err := tx.Where("course_id = ? AND user_id = ?", courseID, userID).
+			Delete(&model.Waitlist{}).Error; err != nil {
+			return err
+		}
 
-	return r.db.Create(&enrollment).Error
+		return tx.Create(&model.Enrollment{CourseID: courseID, UserID: userID}).Error
+	})
 }
 
-// Desinscribir a un usuario de un curso
-func (r *courseRepository) UnenrollUser(courseID uint, userID string) error {
-	return r.db.Where("course_id = ? AND user_id = ?", courseID, userID).
-		Delete(&model.Enrollment{}).Error
+// Desinscribir a un usuario de un curso y promover al siguiente de la lista de espera
+func (r *courseRepository) UnenrollUser(courseID uint, userID string) (string, error) {
+	var promoted []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, courseID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("course_id = ? AND user_id = ?", courseID, userID).
+			Delete(&model.Enrollment{}).Error; err != nil {
+			return err
+		}
+
+		promoted, err = promoteWaitlisted(tx, course, 1)
+		return err
+	})
+	if err != nil || len(promoted) == 0 {
+		return "", err
+	}
+	return promoted[0], nil
 }
 
 // Obtener miembros de un curso
@@ -174,12 +220,30 @@ func (r *courseRepository) UpdateAssignment(assignment *model.Assignment) error
 	}
 
 	assignment.CreatedAt = existingAssignment.CreatedAt // Preserve created time
+	if assignment.Rubric == nil && assignment.RubricID == nil {
+		assignment.RubricID = existingAssignment.RubricID // Keep the rubric when no new one is given
+	}
 
 	if err := tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(assignment).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	// The grade that counts for each submission depends on the attempt policy
+	if assignment.AttemptPolicy != existingAssignment.AttemptPolicy {
+		var submissions []model.Submission
+		if err := tx.Where("assignment_id = ?", assignment.ID).Find(&submissions).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		for i := range submissions {
+			if err := updateFinalGrade(tx, &submissions[i], assignment); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
 	return tx.Commit().Error
 }
 
@@ -233,6 +297,10 @@ func (r *courseRepository) GetAssignmentsPreviews(courseID uint, userID string,
 			CreatedAt: assignment.CreatedAt,
 			DeletedAt: assignment.DeletedAt,
 			Status:    status,
+
+			CategoryID: assignment.CategoryID,
+			Points:     assignment.Points,
+			Mandatory:  assignment.Mandatory,
 		}
 	}
 	return previews, err
@@ -281,7 +349,7 @@ func (r *courseRepository) ApproveCourse(userID string, courseID uint, courseNam
 		Count(&count)
 
 	if count > 0 {
-		return fmt.Errorf("user already approved")
+		return utils.ErrAlreadyApproved
 	}
 
 	return r.db.Create(&approval).Error
@@ -318,29 +386,55 @@ func (r *courseRepository) ToggleFavoriteStatus(courseID uint, userID string) er
 	return r.db.Save(&enrollment).Error
 }
 
-func (r *courseRepository) PutSubmission(submission *model.Submission) error {
-	// Check if the submission already exists
-	var existingSubmission model.Submission
-	result := DB.Where("course_id = ? AND assignment_id = ? AND user_id = ?",
-		submission.CourseID, submission.AssignmentID, submission.UserID).First(&existingSubmission)
+func (r *courseRepository) PutSubmission(submission *model.Submission, assignment *model.Assignment) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		// Check if the submission already exists, locking it so concurrent attempts are numbered in order
+		var existingSubmission model.Submission
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("course_id = ? AND assignment_id = ? AND user_id = ?",
+			submission.CourseID, submission.AssignmentID, submission.UserID).First(&existingSubmission)
+
+		if result.Error == nil {
+			if assignment.AttemptsLeft(existingSubmission.Attempt) == 0 {
+				return utils.ErrMaxAttemptsReached
+			}
 
-	if result.Error == nil {
-		// Submission exists, update it
-		submission.ID = existingSubmission.ID // Preserve the original ID
-		// If files are provided, clear existing associations first
-		if len(submission.Files) > 0 {
-			// Clear the files association to remove old files
-			if err := DB.Model(&existingSubmission).Association("Files").Clear(); err != nil {
+			// Submission exists, the new attempt replaces it
+			submission.ID = existingSubmission.ID // Preserve the original ID
+			submission.Attempt = existingSubmission.Attempt + 1
+			// If files are provided, clear existing associations first. Previous attempts keep theirs.
+			if len(submission.Files) > 0 {
+				if err := tx.Model(&existingSubmission).Association("Files").Clear(); err != nil {
+					return err
+				}
+			}
+			if err := tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(submission).Error; err != nil {
+				return err
+			}
+			// The AI grade draft was generated for the previous attempt
+			if err := tx.Where("submission_id = ?", submission.ID).Delete(&model.GradeDraft{}).Error; err != nil {
 				return err
 			}
+		} else if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// Submission doesn't exist, create it
+			submission.Attempt = 1
+			if err := tx.Create(submission).Error; err != nil {
+				return err
+			}
+		} else {
+			return result.Error
+		}
+
+		// Snapshot the attempt with the files the submission has now
+		attempt := submission.NewAttempt(submission.Attempt)
+		if err := tx.Model(submission).Association("Files").Find(&attempt.Files); err != nil {
+			return err
+		}
+		if err := tx.Omit("Files.*").Create(attempt).Error; err != nil {
+			return err
 		}
-		return DB.Session(&gorm.Session{FullSaveAssociations: true}).Save(submission).Error
-	} else if result.Error == gorm.ErrRecordNotFound {
-		// Submission doesn't exist, create it
-		return DB.Create(submission).Error
-	}
 
-	return result.Error
+		return updateFinalGrade(tx, submission, assignment)
+	})
 }
 
 func (r *courseRepository) GetSubmissionByUserID(courseID, assignmentID uint, userID string) (*model.Submission, error) {
@@ -388,7 +482,7 @@ func (r *courseRepository) DeleteSubmission(submissionID uint) error {
 
 func (r *courseRepository) GetAssignmentByID(assignmentID uint) (*model.Assignment, error) {
 	var assignment model.Assignment
-	err := DB.Where("id = ?", assignmentID).Preload("Files").First(&assignment).Error
+	err := DB.Where("id = ?", assignmentID).Preload("Files").Preload("Rubric").First(&assignment).Error
 	if err != nil {
 		return nil, err
 	}
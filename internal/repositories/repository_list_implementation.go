@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"strings"
+	"templateGo/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var courseSortKeys = map[string]sortKey[model.Course]{
+	"id":         {column: "id", value: func(c model.Course) any { return c.ID }},
+	"created_at": {column: coalesceTime("created_at"), value: func(c model.Course) any { return c.CreatedAt }},
+	"title":      {column: "COALESCE(title, '')", value: func(c model.Course) any { return c.Title }},
+	"start_date": {column: coalesceTime("start_date"), value: func(c model.Course) any { return c.StartDate }},
+}
+
+var submissionSortKeys = map[string]sortKey[model.Submission]{
+	"id":           {column: "id", value: func(s model.Submission) any { return s.ID }},
+	"submitted_at": {column: coalesceTime("submitted_at"), value: func(s model.Submission) any { return s.SubmittedAt }},
+	"grade":        {column: "COALESCE(grade, 0)", value: func(s model.Submission) any { return s.Grade }},
+	"user_id":      {column: "user_id", value: func(s model.Submission) any { return s.UserID }},
+}
+
+var courseFeedbackSortKeys = map[string]sortKey[model.CourseFeedback]{
+	"created_at": {column: coalesceTime("created_at"), value: func(f model.CourseFeedback) any { return f.CreatedAt }},
+	"rating":     {column: "rating", value: func(f model.CourseFeedback) any { return f.Rating }},
+}
+
+var userFeedbackSortKeys = map[string]sortKey[model.UserFeedback]{
+	"created_at": {column: coalesceTime("created_at"), value: func(f model.UserFeedback) any { return f.CreatedAt }},
+	"rating":     {column: "COALESCE(rating, 0)", value: func(f model.UserFeedback) any { return f.Rating }},
+}
+
+var enrollmentSortKeys = map[string]sortKey[model.Enrollment]{
+	"id":      {column: "id", value: func(e model.Enrollment) any { return e.ID }},
+	"user_id": {column: "COALESCE(user_id, '')", value: func(e model.Enrollment) any { return e.UserID }},
+}
+
+func (r *courseRepository) ListCourses(filter model.CourseFilter, page model.PageQuery) (*Page[model.Course], error) {
+	query := filterCourses(DB.Model(&model.Course{}), filter).Preload("Prerequisites", orderPrerequisites)
+	return paginate(query, page, "id", courseSortKeys, func(c model.Course) uint { return c.ID })
+}
+
+func (r *courseRepository) ListAvailableCourses(userID string, filter model.CourseFilter, page model.PageQuery) (*Page[model.Course], error) {
+	query := filterCourses(DB.Model(&model.Course{}), filter).
+		Where("state = ?", model.CourseStateEnrollmentOpen).
+		Where("id NOT IN (SELECT course_id FROM enrollments WHERE user_id = ?)", userID).
+		Preload("Prerequisites", orderPrerequisites)
+	query = whereEligible(query, "courses.id", userID)
+	return paginate(query, page, "id", courseSortKeys, func(c model.Course) uint { return c.ID })
+}
+
+func filterCourses(query *gorm.DB, filter model.CourseFilter) *gorm.DB {
+	if filter.Title != "" {
+		query = query.Where("title ILIKE ?", "%"+escapeLike(filter.Title)+"%")
+	}
+	if filter.State != "" {
+		query = query.Where("state = ?", filter.State)
+	}
+	if filter.Template != nil {
+		query = query.Where("is_template = ?", *filter.Template)
+	}
+	return whereBetween(query, "start_date", filter.StartFrom, filter.StartTo)
+}
+
+func (r *courseRepository) ListSubmissions(courseID, assignmentID uint, filter model.SubmissionFilter, page model.PageQuery) (*Page[model.Submission], error) {
+	query := DB.Where("course_id = ? AND assignment_id = ?", courseID, assignmentID).Preload("Files")
+	graded := []string{model.SubmissionStatusGraded, model.SubmissionStatusReturned, model.SubmissionStatusRegraded}
+	if filter.Graded != nil {
+		if *filter.Graded {
+			query = query.Where("status IN ?", graded)
+		} else {
+			query = query.Where("status NOT IN ?", graded)
+		}
+	}
+	if filter.Late != nil {
+		query = query.Where("late = ?", *filter.Late)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	query = whereBetween(query, "submitted_at", filter.SubmittedFrom, filter.SubmittedTo)
+	return paginate(query, page, "id", submissionSortKeys, func(s model.Submission) uint { return s.ID })
+}
+
+func (r *courseRepository) ListCourseFeedbacks(courseID uint, filter model.FeedbackFilter, page model.PageQuery) (*Page[model.CourseFeedback], error) {
+	query := filterFeedbacks(DB.Where("course_id = ?", courseID), filter)
+	return paginate(query, page, "-created_at", courseFeedbackSortKeys, func(f model.CourseFeedback) uint { return f.ID })
+}
+
+func (r *courseRepository) ListUserFeedbacks(userID string, filter model.FeedbackFilter, page model.PageQuery) (*Page[model.UserFeedback], error) {
+	query := filterFeedbacks(DB.Where("student_id = ?", userID), filter)
+	if filter.CourseID != 0 {
+		query = query.Where("course_id = ?", filter.CourseID)
+	}
+	return paginate(query, page, "-created_at", userFeedbackSortKeys, func(f model.UserFeedback) uint { return f.ID })
+}
+
+func filterFeedbacks(query *gorm.DB, filter model.FeedbackFilter) *gorm.DB {
+	if filter.MinRating != 0 {
+		query = query.Where("rating >= ?", filter.MinRating)
+	}
+	if filter.MaxRating != 0 {
+		query = query.Where("rating <= ?", filter.MaxRating)
+	}
+	return whereBetween(query, "created_at", filter.CreatedFrom, filter.CreatedTo)
+}
+
+func (r *courseRepository) ListCourseMembers(courseID uint, page model.PageQuery) (*Page[model.Enrollment], error) {
+	query := DB.Where("course_id = ?", courseID)
+	return paginate(query, page, "id", enrollmentSortKeys, func(e model.Enrollment) uint { return e.ID })
+}
+
+// whereBetween keeps the rows whose column is within the bounds, ignoring the zero ones
+func whereBetween(query *gorm.DB, column string, from, to time.Time) *gorm.DB {
+	if !from.IsZero() {
+		query = query.Where(column+" >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where(column+" <= ?", to)
+	}
+	return query
+}
+
+// likeEscaper escapes the wildcards of a text searched with LIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
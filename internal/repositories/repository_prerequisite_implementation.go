@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"fmt"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// unmetPrerequisites selects the prerequisite groups of a course, the first argument, that a user,
+// the second one, has not met by approving any of their courses
+const unmetPrerequisites = `SELECT 1 FROM course_prerequisites p WHERE p.course_id = ? GROUP BY p.group_number
+	HAVING NOT bool_or(p.required_course_id IN (SELECT course_id FROM course_approvals WHERE user_id = ? AND deleted_at IS NULL))`
+
+// whereEligible keeps the courses whose prerequisites a user meets, with the ID of the course in courseColumn
+func whereEligible(query *gorm.DB, courseColumn, userID string) *gorm.DB {
+	return query.Where("NOT EXISTS ("+unmetPrerequisites+")", gorm.Expr(courseColumn), userID)
+}
+
+// meetsPrerequisites reports whether a user meets every prerequisite group of a course
+func meetsPrerequisites(tx *gorm.DB, courseID uint, userID string) (bool, error) {
+	var met bool
+	err := tx.Raw("SELECT NOT EXISTS ("+unmetPrerequisites+")", courseID, userID).Scan(&met).Error
+	return met, err
+}
+
+// orderPrerequisites preloads prerequisites in the order of their groups
+func orderPrerequisites(db *gorm.DB) *gorm.DB {
+	return db.Order("group_number, id")
+}
+
+func (r *courseRepository) SetCoursePrerequisites(courseID uint, groups [][]uint) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		return replacePrerequisites(tx, courseID, model.PrerequisitesFromGroups(groups))
+	})
+}
+
+func (r *courseRepository) GetCourseEligibility(courseID uint, userID string) (*model.Eligibility, error) {
+	var prerequisites []model.CoursePrerequisite
+	// Deleted courses keep their title, even if nobody can approve them anymore
+	if err := DB.Where("course_id = ?", courseID).
+		Preload("RequiredCourse", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Scopes(orderPrerequisites).Find(&prerequisites).Error; err != nil {
+		return nil, err
+	}
+
+	var approvedIDs []uint
+	if err := DB.Model(&model.CourseApproval{}).Where("user_id = ?", userID).
+		Pluck("course_id", &approvedIDs).Error; err != nil {
+		return nil, err
+	}
+	approved := make(map[uint]bool, len(approvedIDs))
+	for _, id := range approvedIDs {
+		approved[id] = true
+	}
+
+	eligibility := model.CheckEligibility(courseID, prerequisites, approved)
+	return &eligibility, nil
+}
+
+// replacePrerequisites replaces the prerequisites of a course, checking that the required courses exist
+// and that requiring them does not make the course a prerequisite of itself
+func replacePrerequisites(tx *gorm.DB, courseID uint, prerequisites []model.CoursePrerequisite) error {
+	if len(prerequisites) > 0 {
+		// Serializes the changes to prerequisites, so two concurrent ones cannot close a cycle together
+		if err := tx.Exec("LOCK TABLE course_prerequisites IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		if err := checkPrerequisites(tx, courseID, prerequisites); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("course_id = ?", courseID).Delete(&model.CoursePrerequisite{}).Error; err != nil {
+		return err
+	}
+	if len(prerequisites) == 0 {
+		return nil
+	}
+	for i := range prerequisites {
+		prerequisites[i].ID = 0
+		prerequisites[i].CourseID = courseID
+	}
+	return tx.Omit("RequiredCourse").Create(&prerequisites).Error
+}
+
+func checkPrerequisites(tx *gorm.DB, courseID uint, prerequisites []model.CoursePrerequisite) error {
+	var required []uint
+	seen := make(map[uint]bool)
+	for _, prerequisite := range prerequisites {
+		if !seen[prerequisite.RequiredCourseID] {
+			seen[prerequisite.RequiredCourseID] = true
+			required = append(required, prerequisite.RequiredCourseID)
+		}
+	}
+
+	var existing []uint
+	if err := tx.Model(&model.Course{}).Where("id IN ?", required).Pluck("id", &existing).Error; err != nil {
+		return err
+	}
+	if len(existing) < len(required) {
+		found := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			found[id] = true
+		}
+		var missing []uint
+		for _, id := range required {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+		return fmt.Errorf("%w: %v", utils.ErrPrerequisiteInvalid, missing)
+	}
+
+	var edges []model.CoursePrerequisite
+	if err := tx.Select("course_id", "required_course_id").Where("course_id <> ?", courseID).
+		Find(&edges).Error; err != nil {
+		return err
+	}
+	graph := make(map[uint][]uint)
+	for _, edge := range edges {
+		graph[edge.CourseID] = append(graph[edge.CourseID], edge.RequiredCourseID)
+	}
+	if cycle := model.FindPrerequisiteCycle(courseID, required, graph); cycle != nil {
+		return fmt.Errorf("%w: %s", utils.ErrPrerequisiteCycle, model.FormatCoursePath(cycle))
+	}
+	return nil
+}
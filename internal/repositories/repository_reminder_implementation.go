@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"templateGo/internal/model"
+	"time"
+)
+
+func (r *courseRepository) GetAssignmentsDueBetween(from, to time.Time) ([]model.DueAssignment, error) {
+	due := []model.DueAssignment{}
+	err := DB.Table("assignments").
+		Select("assignments.id AS assignment_id, assignments.title, assignments.deadline, courses.id AS course_id, courses.title AS course_title").
+		Joins("JOIN courses ON courses.id = assignments.course_id").
+		Where("assignments.deleted_at IS NULL AND courses.deleted_at IS NULL AND courses.state <> ?", model.CourseStateArchived).
+		Where("assignments.deadline > ? AND assignments.deadline <= ?", from, to).
+		Order("assignments.deadline").
+		Scan(&due).Error
+	return due, err
+}
+
+// ClaimDeadlineReminders inserts the reminders in the same statement that finds the students, so each
+// reminder is claimed by a single instance of the service and survives restarts
+func (r *courseRepository) ClaimDeadlineReminders(assignmentID uint, offsetMinutes int, now time.Time) ([]string, error) {
+	userIDs := []string{}
+	err := DB.Raw(`
+		INSERT INTO deadline_reminders (assignment_id, user_id, offset_minutes, sent_at)
+		SELECT a.id, e.user_id, ?, ?
+		FROM assignments a
+		JOIN enrollments e ON e.course_id = a.course_id
+		WHERE a.id = ?
+		  AND NOT EXISTS (SELECT 1 FROM submissions s WHERE s.assignment_id = a.id AND s.user_id = e.user_id)
+		ON CONFLICT (assignment_id, user_id, offset_minutes) DO NOTHING
+		RETURNING user_id`, offsetMinutes, now, assignmentID).
+		Scan(&userIDs).Error
+	return userIDs, err
+}
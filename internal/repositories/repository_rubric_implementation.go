@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"errors"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *courseRepository) CreateRubric(rubric *model.Rubric) error {
+	return DB.Create(rubric).Error
+}
+
+func (r *courseRepository) GetRubricByID(rubricID uint) (*model.Rubric, error) {
+	var rubric model.Rubric
+	err := DB.Where("id = ?", rubricID).First(&rubric).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrRubricNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rubric, nil
+}
+
+func (r *courseRepository) GetRubricsByCourse(courseID uint) ([]model.Rubric, error) {
+	var rubrics []model.Rubric
+	err := DB.Where("course_id = ?", courseID).Order("id").Find(&rubrics).Error
+	return rubrics, err
+}
+
+func (r *courseRepository) UpdateRubric(rubric *model.Rubric) error {
+	return DB.Model(rubric).Select("title", "criteria").Updates(rubric).Error
+}
+
+func (r *courseRepository) DeleteRubric(rubricID uint) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		var assignments int64
+		if err := tx.Model(&model.Assignment{}).Where("rubric_id = ?", rubricID).Count(&assignments).Error; err != nil {
+			return err
+		}
+		if assignments > 0 {
+			return utils.ErrRubricInUse
+		}
+
+		return tx.Delete(&model.Rubric{}, rubricID).Error
+	})
+}
+
+func (r *courseRepository) SaveGradeDraft(draft *model.GradeDraft) error {
+	return DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "submission_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"rubric_id", "scores", "grade", "feedback", "provider", "status", "accepted_by", "accepted_at", "updated_at",
+		}),
+	}).Create(draft).Error
+}
+
+func (r *courseRepository) GetGradeDraft(submissionID uint) (*model.GradeDraft, error) {
+	var draft model.GradeDraft
+	err := DB.Where("submission_id = ?", submissionID).First(&draft).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.ErrGradeDraftNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (r *courseRepository) AcceptGradeDraft(draft *model.GradeDraft) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		// Locked so that accepting the same draft twice, even concurrently, grades the submission once
+		var current model.GradeDraft
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, draft.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != model.GradeDraftStatusDraft {
+			return utils.ErrGradeDraftAccepted
+		}
+
+		now := time.Now()
+		draft.Status = model.GradeDraftStatusAccepted
+		draft.AcceptedAt = &now
+
+		if err := tx.Model(draft).Select("scores", "grade", "feedback", "status", "accepted_by", "accepted_at").
+			Updates(draft).Error; err != nil {
+			return err
+		}
+
+		var submission model.Submission
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&submission, draft.SubmissionID).Error; err != nil {
+			return err
+		}
+		var assignment model.Assignment
+		if err := tx.First(&assignment, submission.AssignmentID).Error; err != nil {
+			return err
+		}
+		submission.ApplyGrade(draft.Grade, &assignment)
+		submission.Feedback = draft.Feedback
+		submission.RubricID = draft.RubricID
+		submission.Scores = draft.Scores
+		submission.MarkGraded(draft.AcceptedBy, now)
+
+		if err := tx.Model(&submission).Select(gradeColumns).Updates(&submission).Error; err != nil {
+			return err
+		}
+		return saveAttemptGrade(tx, &submission, &assignment)
+	})
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"html"
+	"strings"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"unicode"
+)
+
+// Delimiters of the matches in the highlights returned by Postgres, private use characters so they cannot
+// appear in course texts and are replaced by <mark> tags once the text is escaped
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+var highlighter = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+var searchSortKeys = map[string]sortKey[model.CourseSearchResult]{
+	"relevance":  {column: "rank", value: func(r model.CourseSearchResult) any { return r.Rank }},
+	"id":         {column: "id", value: func(r model.CourseSearchResult) any { return r.ID }},
+	"title":      {column: "COALESCE(title, '')", value: func(r model.CourseSearchResult) any { return r.Title }},
+	"start_date": {column: coalesceTime("start_date"), value: func(r model.CourseSearchResult) any { return r.StartDate }},
+}
+
+func (r *courseRepository) SearchCourses(search model.CourseSearchQuery, userID string, page model.PageQuery) (*Page[model.CourseSearchResult], error) {
+	tsQuery := toTSQuery(search.Query)
+	if tsQuery == "" {
+		return nil, utils.ErrEmptySearchQuery
+	}
+
+	matches := DB.Table("courses").
+		Select(`courses.*, ts_rank(courses.search_vector, query)::float8 AS rank,
+			ts_headline('simple', coalesce(courses.title, ''), query,
+				'StartSel=`+highlightStart+`, StopSel=`+highlightStop+`, HighlightAll=true') AS title_highlight,
+			ts_headline('simple', coalesce(courses.description, ''), query,
+				'StartSel=`+highlightStart+`, StopSel=`+highlightStop+`, MaxFragments=2, MinWords=10, MaxWords=30') AS description_highlight`).
+		Joins("CROSS JOIN to_tsquery('simple', ?) AS query", tsQuery).
+		Where("courses.deleted_at IS NULL AND courses.search_vector @@ query").
+		Where("courses.state IN ?", model.CatalogStates)
+	matches = whereBetween(matches, "courses.start_date", search.StartFrom, search.StartTo)
+	if search.HasSeats {
+		matches = matches.Where("courses.capacity <= 0 OR courses.capacity > (SELECT count(*) FROM enrollments WHERE enrollments.course_id = courses.id)")
+	}
+	if search.Eligible {
+		matches = whereEligible(matches, "courses.id", userID)
+	}
+
+	// The matches are paginated as a table of their own, so the rank can be sorted and compared with cursors
+	result, err := paginate(DB.Table("(?) AS courses", matches).Unscoped().Preload("Prerequisites", orderPrerequisites), page, "-relevance", searchSortKeys,
+		func(r model.CourseSearchResult) uint { return r.ID })
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Items {
+		result.Items[i].TitleHighlight = highlighter.Replace(html.EscapeString(result.Items[i].TitleHighlight))
+		result.Items[i].DescriptionHighlight = highlighter.Replace(html.EscapeString(result.Items[i].DescriptionHighlight))
+	}
+	return result, nil
+}
+
+// toTSQuery turns the words of a search into a tsquery matching every word, also as a prefix of a longer one.
+// Anything but letters and digits is dropped, so the query cannot use the tsquery syntax.
+func toTSQuery(search string) string {
+	words := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToTSQuery(t *testing.T) {
+	assert.Equal(t, "intro:* & progr:*", toTSQuery("Intro  progr"))
+	assert.Equal(t, "análisis:* & 2:*", toTSQuery("Análisis 2"))
+	assert.Equal(t, "a:* & b:* & c:*", toTSQuery("a & !b | c:*"), "tsquery operators are dropped")
+	assert.Empty(t, toTSQuery(" -- '' "))
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"templateGo/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// similarityBatch is the number of similarity rows inserted per query
+const similarityBatch = 200
+
+func (r *courseRepository) ReplaceSubmissionSimilarities(assignmentID uint, similarities []model.SubmissionSimilarity) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("assignment_id = ?", assignmentID).Delete(&model.SubmissionSimilarity{}).Error; err != nil {
+			return err
+		}
+		if len(similarities) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(similarities, similarityBatch).Error
+	})
+}
+
+func (r *courseRepository) GetSubmissionSimilarities(assignmentID uint, minScore float64) ([]model.SubmissionSimilarity, error) {
+	var similarities []model.SubmissionSimilarity
+	err := DB.Where("assignment_id = ? AND score >= ?", assignmentID, minScore).
+		Order("score DESC").Order("id").
+		Find(&similarities).Error
+	if err != nil {
+		return nil, err
+	}
+	return similarities, nil
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *courseRepository) ChangeCourseState(courseID uint, state string) (*model.Course, error) {
+	var course *model.Course
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		course, err = changeCourseState(tx, courseID, state)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// changeCourseState checks and applies a transition with the course locked
+func changeCourseState(tx *gorm.DB, courseID uint, state string) (*model.Course, error) {
+	course, err := lockCourse(tx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !model.CanTransition(course.State, state) {
+		return nil, fmt.Errorf("%w: %s to %s", utils.ErrInvalidTransition, course.State, state)
+	}
+	if err := tx.Model(course).Update("state", state).Error; err != nil {
+		return nil, err
+	}
+	course.State = state
+	return course, nil
+}
+
+func (r *courseRepository) ScheduleCourseState(schedule *model.CourseStateSchedule) error {
+	schedule.Status = model.ScheduleStatusPending
+	return DB.Create(schedule).Error
+}
+
+func (r *courseRepository) GetCourseStateSchedules(courseID uint) ([]model.CourseStateSchedule, error) {
+	var schedules []model.CourseStateSchedule
+	err := DB.Where("course_id = ?", courseID).Order("run_at ASC, id ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *courseRepository) CancelCourseStateSchedule(courseID, scheduleID uint) error {
+	result := DB.Where("id = ? AND course_id = ? AND status = ?", scheduleID, courseID, model.ScheduleStatusPending).
+		Delete(&model.CourseStateSchedule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *courseRepository) ApplyDueCourseStates(now time.Time) (int, error) {
+	processed := 0
+	for {
+		applied, err := applyNextCourseState(now)
+		if err != nil {
+			return processed, err
+		}
+		if !applied {
+			return processed, nil
+		}
+		processed++
+	}
+}
+
+// applyNextCourseState claims the oldest pending change due by now and applies it. Claimed rows are skipped
+// by other instances, so each change is applied once even with several replicas running the scheduler.
+func applyNextCourseState(now time.Time) (bool, error) {
+	found := false
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		var schedule model.CourseStateSchedule
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ?", model.ScheduleStatusPending, now).
+			Order("run_at ASC, id ASC").
+			First(&schedule).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+
+		updates := map[string]any{"status": model.ScheduleStatusApplied, "processed_at": time.Now()}
+		course, err := changeCourseState(tx, schedule.CourseID, schedule.State)
+		if err == nil {
+			// Seats freed while enrollment was closed go to waitlisted users first
+			var promoted []string
+			promoted, err = promoteWaitlisted(tx, course, -1)
+			if len(promoted) > 0 {
+				log.Printf("Promoted %d waitlisted users of course %d", len(promoted), course.ID)
+			}
+		}
+		switch {
+		case errors.Is(err, utils.ErrInvalidTransition), errors.Is(err, utils.ErrCourseNotFound):
+			updates["status"] = model.ScheduleStatusFailed
+			updates["error"] = err.Error()
+		case err != nil:
+			// Left pending to be retried on the next run
+			return err
+		}
+		return tx.Model(&schedule).Updates(updates).Error
+	})
+	return found, err
+}
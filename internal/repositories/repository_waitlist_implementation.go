@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"templateGo/internal/model"
+	"templateGo/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockCourse loads a course with a row lock so concurrent enrollments are serialized
+func lockCourse(tx *gorm.DB, courseID uint) (*model.Course, error) {
+	var course model.Course
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", courseID).
+		First(&course).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, utils.ErrCourseNotFound
+	}
+	return &course, err
+}
+
+// countFreeSeats returns the number of free seats of a course, or -1 if the course has no capacity limit
+func countFreeSeats(tx *gorm.DB, course *model.Course) (int, error) {
+	if course.Capacity <= 0 {
+		return -1, nil
+	}
+
+	var enrolled int64
+	if err := tx.Model(&model.Enrollment{}).Where("course_id = ?", course.ID).Count(&enrolled).Error; err != nil {
+		return 0, err
+	}
+
+	free := course.Capacity - int(enrolled)
+	if free < 0 {
+		return 0, nil
+	}
+	return free, nil
+}
+
+// promoteWaitlisted enrolls up to max waitlisted users (or every free seat if max < 0) in arrival order.
+// Nobody is enrolled while the course is not open for enrollment. The caller must hold the course lock.
+func promoteWaitlisted(tx *gorm.DB, course *model.Course, max int) ([]string, error) {
+	if !course.AcceptsEnrollments() {
+		return nil, nil
+	}
+
+	freeSeats, err := countFreeSeats(tx, course)
+	if err != nil {
+		return nil, err
+	}
+	if freeSeats == 0 {
+		return nil, nil
+	}
+
+	limit := freeSeats
+	if max >= 0 && (limit < 0 || max < limit) {
+		limit = max
+	}
+
+	var entries []model.Waitlist
+	if err := tx.Where("course_id = ?", course.ID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	promoted := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if err := tx.Create(&model.Enrollment{CourseID: course.ID, UserID: entry.UserID}).Error; err != nil {
+			return nil, err
+		}
+		if err := tx.Delete(&entry).Error; err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, entry.UserID)
+	}
+
+	return promoted, nil
+}
+
+// JoinWaitlist adds a user to the waitlist of a full course and returns their position
+func (r *courseRepository) JoinWaitlist(courseID uint, userID string) (int, error) {
+	var position int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, courseID)
+		if err != nil {
+			return err
+		}
+		if !course.AcceptsEnrollments() {
+			return utils.ErrEnrollmentClosed
+		}
+
+		var count int64
+		if err := tx.Model(&model.Enrollment{}).
+			Where("course_id = ? AND user_id = ?", courseID, userID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return utils.ErrUserAlreadyEnrolled
+		}
+
+		// Waitlisted users are enrolled without further checks when a seat frees up
+		met, err := meetsPrerequisites(tx, courseID, userID)
+		if err != nil {
+			return err
+		}
+		if !met {
+			return utils.ErrPrerequisitesNotMet
+		}
+
+		freeSeats, err := countFreeSeats(tx, course)
+		if err != nil {
+			return err
+		}
+		if freeSeats != 0 {
+			return utils.ErrCourseHasSeats
+		}
+
+		if err := tx.Model(&model.Waitlist{}).
+			Where("course_id = ? AND user_id = ?", courseID, userID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return utils.ErrAlreadyWaitlisted
+		}
+
+		entry := model.Waitlist{CourseID: courseID, UserID: userID}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+
+		position, err = waitlistPosition(tx, &entry)
+		return err
+	})
+	return position, err
+}
+
+// LeaveWaitlist removes a user from the waitlist of a course
+func (r *courseRepository) LeaveWaitlist(courseID uint, userID string) error {
+	result := r.db.Where("course_id = ? AND user_id = ?", courseID, userID).Delete(&model.Waitlist{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrNotWaitlisted
+	}
+	return nil
+}
+
+// GetWaitlistPosition returns the 1-based position of a user and the current waitlist size
+func (r *courseRepository) GetWaitlistPosition(courseID uint, userID string) (int, int, error) {
+	var entry model.Waitlist
+	err := r.db.Where("course_id = ? AND user_id = ?", courseID, userID).First(&entry).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, 0, utils.ErrNotWaitlisted
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	position, err := waitlistPosition(r.db, &entry)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var size int64
+	if err := r.db.Model(&model.Waitlist{}).Where("course_id = ?", courseID).Count(&size).Error; err != nil {
+		return 0, 0, err
+	}
+
+	return position, int(size), nil
+}
+
+// GetWaitlist retrieves the waitlist of a course in promotion order
+func (r *courseRepository) GetWaitlist(courseID uint) ([]model.Waitlist, error) {
+	var entries []model.Waitlist
+	err := r.db.Where("course_id = ?", courseID).Order("created_at ASC, id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// PromoteFromWaitlist fills every free seat of a course from its waitlist and returns the promoted user IDs
+func (r *courseRepository) PromoteFromWaitlist(courseID uint) ([]string, error) {
+	var promoted []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		course, err := lockCourse(tx, courseID)
+		if err != nil {
+			return err
+		}
+
+		promoted, err = promoteWaitlisted(tx, course, -1)
+		return err
+	})
+	return promoted, err
+}
+
+// waitlistPosition counts the entries that arrived before the given one
+func waitlistPosition(db *gorm.DB, entry *model.Waitlist) (int, error) {
+	var ahead int64
+	err := db.Model(&model.Waitlist{}).
+		Where("course_id = ? AND (created_at < ? OR (created_at = ? AND id < ?))",
+			entry.CourseID, entry.CreatedAt, entry.CreatedAt, entry.ID).
+		Count(&ahead).Error
+	return int(ahead) + 1, err
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// defaultApprovalInterval is how often the approval rules of the courses that ended are applied
+const defaultApprovalInterval = 10 * time.Minute
+
+// ApprovalApplier applies the approval rules of the courses ended by a time. approval.Engine satisfies it.
+type ApprovalApplier interface {
+	ApplyDueApprovals(now time.Time) (int, error)
+}
+
+// ApprovalScheduler approves in the background the students of the courses that ended, with the rules
+// of each course. Rules are claimed in the database, so several instances of the service can run it.
+type ApprovalScheduler struct {
+	periodic
+	applier ApprovalApplier
+}
+
+// NewApprovalScheduler creates a scheduler that checks for ended courses every interval
+func NewApprovalScheduler(applier ApprovalApplier, interval time.Duration) *ApprovalScheduler {
+	s := &ApprovalScheduler{applier: applier}
+	s.periodic = periodic{name: "Approval scheduler", job: s.applyDue, interval: interval}
+	return s
+}
+
+// NewApprovalSchedulerFromEnv creates a scheduler with the interval set in APPROVAL_SCHEDULER_INTERVAL,
+// ten minutes by default
+func NewApprovalSchedulerFromEnv(applier ApprovalApplier) *ApprovalScheduler {
+	return NewApprovalScheduler(applier, intervalFromEnv("APPROVAL_SCHEDULER_INTERVAL", defaultApprovalInterval))
+}
+
+func (s *ApprovalScheduler) applyDue(now time.Time) {
+	processed, err := s.applier.ApplyDueApprovals(now)
+	if err != nil {
+		log.Printf("Error applying approval rules: %v", err)
+	}
+	if processed > 0 {
+		log.Printf("Applied the approval rules of %d courses", processed)
+	}
+}
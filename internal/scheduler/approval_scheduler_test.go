@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeApprovalApplier struct {
+	calls atomic.Int32
+}
+
+func (f *fakeApprovalApplier) ApplyDueApprovals(now time.Time) (int, error) {
+	f.calls.Add(1)
+	return 0, nil
+}
+
+func TestApprovalScheduler_AppliesOnStartAndEveryInterval(t *testing.T) {
+	applier := &fakeApprovalApplier{}
+	scheduler := NewApprovalScheduler(applier, 10*time.Millisecond)
+
+	scheduler.Start()
+	assert.Eventually(t, func() bool { return applier.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	scheduler.Stop()
+	scheduler.Stop()
+}
+
+func TestNewApprovalSchedulerFromEnv(t *testing.T) {
+	t.Setenv("APPROVAL_SCHEDULER_INTERVAL", "1h")
+	assert.Equal(t, time.Hour, NewApprovalSchedulerFromEnv(&fakeApprovalApplier{}).interval)
+
+	t.Setenv("APPROVAL_SCHEDULER_INTERVAL", "-5m")
+	assert.Equal(t, defaultApprovalInterval, NewApprovalSchedulerFromEnv(&fakeApprovalApplier{}).interval)
+}
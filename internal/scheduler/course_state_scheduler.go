@@ -0,0 +1,47 @@
+// Package scheduler runs the jobs of the service that are due at a given time, such as the scheduled
+// changes of state of courses.
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// defaultCourseStateInterval is how often the due changes of state are applied
+const defaultCourseStateInterval = time.Minute
+
+// CourseStateApplier applies the changes of state of courses due by a time.
+// repositories.CourseRepository satisfies it.
+type CourseStateApplier interface {
+	ApplyDueCourseStates(now time.Time) (int, error)
+}
+
+// CourseStateScheduler applies the scheduled changes of state of courses in the background. Changes are
+// claimed in the database, so several instances of the service can run it at the same time.
+type CourseStateScheduler struct {
+	periodic
+	applier CourseStateApplier
+}
+
+// NewCourseStateScheduler creates a scheduler that checks for due changes every interval
+func NewCourseStateScheduler(applier CourseStateApplier, interval time.Duration) *CourseStateScheduler {
+	s := &CourseStateScheduler{applier: applier}
+	s.periodic = periodic{name: "Course state scheduler", job: s.applyDue, interval: interval}
+	return s
+}
+
+// NewCourseStateSchedulerFromEnv creates a scheduler with the interval set in COURSE_STATE_SCHEDULER_INTERVAL,
+// one minute by default
+func NewCourseStateSchedulerFromEnv(applier CourseStateApplier) *CourseStateScheduler {
+	return NewCourseStateScheduler(applier, intervalFromEnv("COURSE_STATE_SCHEDULER_INTERVAL", defaultCourseStateInterval))
+}
+
+func (s *CourseStateScheduler) applyDue(now time.Time) {
+	processed, err := s.applier.ApplyDueCourseStates(now)
+	if err != nil {
+		log.Printf("Error applying scheduled course states: %v", err)
+	}
+	if processed > 0 {
+		log.Printf("Processed %d scheduled course states", processed)
+	}
+}
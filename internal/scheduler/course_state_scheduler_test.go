@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeApplier struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (f *fakeApplier) ApplyDueCourseStates(now time.Time) (int, error) {
+	f.calls.Add(1)
+	return 1, f.err
+}
+
+func TestCourseStateScheduler_AppliesOnStartAndEveryInterval(t *testing.T) {
+	applier := &fakeApplier{}
+	scheduler := NewCourseStateScheduler(applier, 10*time.Millisecond)
+
+	scheduler.Start()
+	assert.Eventually(t, func() bool { return applier.calls.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	scheduler.Stop()
+
+	calls := applier.calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, calls, applier.calls.Load(), "no changes are applied after Stop")
+}
+
+func TestCourseStateScheduler_KeepsRunningAfterErrors(t *testing.T) {
+	applier := &fakeApplier{err: errors.New("connection refused")}
+	scheduler := NewCourseStateScheduler(applier, 10*time.Millisecond)
+
+	scheduler.Start()
+	defer scheduler.Stop()
+	assert.Eventually(t, func() bool { return applier.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestNewCourseStateSchedulerFromEnv(t *testing.T) {
+	t.Setenv("COURSE_STATE_SCHEDULER_INTERVAL", "30s")
+	assert.Equal(t, 30*time.Second, NewCourseStateSchedulerFromEnv(&fakeApplier{}).interval)
+
+	t.Setenv("COURSE_STATE_SCHEDULER_INTERVAL", "soon")
+	assert.Equal(t, defaultCourseStateInterval, NewCourseStateSchedulerFromEnv(&fakeApplier{}).interval)
+}
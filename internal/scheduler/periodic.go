@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// periodic runs a job when started and then every interval until stopped
+type periodic struct {
+	name     string
+	job      func(now time.Time)
+	interval time.Duration
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	running  bool
+}
+
+// intervalFromEnv reads an interval such as "1m" from an environment variable, or returns fallback
+func intervalFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// Start runs the job and keeps running it every interval
+func (p *periodic) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running {
+		return
+	}
+	p.running = true
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	p.wg.Add(1)
+	go p.run()
+
+	log.Printf("%s started, checking every %s", p.name, p.interval)
+}
+
+// Stop waits for the job being run and stops the scheduler
+func (p *periodic) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.running {
+		return
+	}
+	p.running = false
+	p.cancel()
+	p.wg.Wait()
+
+	log.Printf("%s stopped", p.name)
+}
+
+func (p *periodic) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.job(time.Now())
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// defaultReminderInterval is how often the deadlines that are close are checked. Reminders go out at most this
+// late after their offset.
+const defaultReminderInterval = 5 * time.Minute
+
+// ReminderSender reminds the students of the deadlines due by a time. reminders.Sender satisfies it.
+type ReminderSender interface {
+	SendDueReminders(now time.Time) (int, error)
+}
+
+// ReminderScheduler reminds the students in the background of the assignments they did not submit before
+// their deadlines. Reminders are claimed in the database, so several instances of the service can run it.
+type ReminderScheduler struct {
+	periodic
+	sender ReminderSender
+}
+
+// NewReminderScheduler creates a scheduler that checks the deadlines every interval
+func NewReminderScheduler(sender ReminderSender, interval time.Duration) *ReminderScheduler {
+	s := &ReminderScheduler{sender: sender}
+	s.periodic = periodic{name: "Reminder scheduler", job: s.sendDue, interval: interval}
+	return s
+}
+
+// NewReminderSchedulerFromEnv creates a scheduler with the interval set in REMINDER_SCHEDULER_INTERVAL,
+// five minutes by default
+func NewReminderSchedulerFromEnv(sender ReminderSender) *ReminderScheduler {
+	return NewReminderScheduler(sender, intervalFromEnv("REMINDER_SCHEDULER_INTERVAL", defaultReminderInterval))
+}
+
+func (s *ReminderScheduler) sendDue(now time.Time) {
+	sent, err := s.sender.SendDueReminders(now)
+	if err != nil {
+		log.Printf("Error sending deadline reminders: %v", err)
+	}
+	if sent > 0 {
+		log.Printf("Sent %d deadline reminders", sent)
+	}
+}
@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReminderSender struct {
+	calls atomic.Int32
+}
+
+func (f *fakeReminderSender) SendDueReminders(now time.Time) (int, error) {
+	f.calls.Add(1)
+	return 0, nil
+}
+
+func TestReminderScheduler_SendsOnStartAndEveryInterval(t *testing.T) {
+	sender := &fakeReminderSender{}
+	scheduler := NewReminderScheduler(sender, 10*time.Millisecond)
+
+	scheduler.Start()
+	assert.Eventually(t, func() bool { return sender.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	scheduler.Stop()
+	scheduler.Stop()
+}
+
+func TestNewReminderSchedulerFromEnv(t *testing.T) {
+	t.Setenv("REMINDER_SCHEDULER_INTERVAL", "1m")
+	assert.Equal(t, time.Minute, NewReminderSchedulerFromEnv(&fakeReminderSender{}).interval)
+
+	t.Setenv("REMINDER_SCHEDULER_INTERVAL", "soon")
+	assert.Equal(t, defaultReminderInterval, NewReminderSchedulerFromEnv(&fakeReminderSender{}).interval)
+}
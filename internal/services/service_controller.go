@@ -2,17 +2,23 @@ package services
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"templateGo/internal/metrics"
 
+	"templateGo/internal/approval"
+	"templateGo/internal/certificates"
 	"templateGo/internal/handlers/ai"
 	"templateGo/internal/handlers/course"
 	"templateGo/internal/handlers/notification"
 	"templateGo/internal/logger"
 	middleware "templateGo/internal/middlewares"
 	"templateGo/internal/queue"
+	"templateGo/internal/reminders"
 	"templateGo/internal/repositories"
+	"templateGo/internal/scheduler"
+	"templateGo/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -71,15 +77,40 @@ func SetupRoutes(ddLogger *logger.DatadogLogger, ddMetrics *metrics.DatadogMetri
 	// Create handlers with logger and metrics
 	courseRepo := repositories.NewCourseRepository()
 	notificationClient := notification.NewNotificationClient(nil)
-	aiAnalyzer := ai.NewGeminiAnalyzer()
+	aiProvider, err := ai.NewProviderFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure AI provider: %v", err)
+	}
+	aiAnalyzer := ai.NewLLMAnalyzer(aiProvider)
+
+	blobStore, err := storage.NewBlobStoreFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure blob store: %v", err)
+	}
 
 	// Create the statistics service (will be started by service manager)
-	statisticsService := queue.NewStatisticsService(courseRepo, aiAnalyzer)
+	statisticsService := queue.NewStatisticsService(courseRepo, aiAnalyzer, blobStore)
+
+	certificateSigner, err := certificates.NewSignerFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure certificate signing: %v", err)
+	}
+	certificateIssuer := certificates.NewIssuer(courseRepo, certificateSigner, notificationClient)
 
-	courseHandler := course.NewCourseHandler(courseRepo, notificationClient, aiAnalyzer, ddMetrics, statisticsService)
+	approvalEngine := approval.NewEngine(courseRepo, notificationClient, certificateIssuer)
+
+	courseHandler := course.NewCourseHandler(courseRepo, notificationClient, aiAnalyzer, ddMetrics, statisticsService, blobStore, approvalEngine, certificateIssuer)
+
+	// Anyone holding a certificate can check it is genuine, without an account
+	r.GET("/certificates/:certificate_id/verify", courseHandler.VerifyCertificate)
+	r.GET("/certificates/public-key", courseHandler.GetCertificatePublicKey)
+
+	// Calendar apps cannot send a Bearer token, the token of the feed address is the secret
+	r.GET("/calendar/:token", courseHandler.GetCalendar)
 
 	api := r.Group("/")
 	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.Authorize(courseRepo, middleware.CoursePermissions))
 	{
 		// =============================================
 		// Course Management (CRUD operations)
@@ -106,9 +137,35 @@ func SetupRoutes(ddLogger *logger.DatadogLogger, ddMetrics *metrics.DatadogMetri
 		// Get available courses that a user can enroll in
 		api.GET("/available", courseHandler.GetAvailableCourses)
 
+		// Full-text search over the course catalog
+		api.GET("/search", courseHandler.SearchCourses)
+
 		// Mark/unmark a course as favorite
 		api.PATCH("/:course_id/favorite/toggle", courseHandler.ToggleFavoriteStatus)
 
+		// =============================================
+		// Course Lifecycle
+		// =============================================
+
+		// Change the state of a course, now or at a scheduled time
+		api.POST("/:course_id/state", courseHandler.ChangeCourseState)
+
+		// Get the scheduled changes of state of a course
+		api.GET("/:course_id/state/schedules", courseHandler.GetCourseStateSchedules)
+
+		// Cancel a pending change of state
+		api.DELETE("/:course_id/state/schedules/:schedule_id", courseHandler.CancelCourseStateSchedule)
+
+		// =============================================
+		// Course Cloning
+		// =============================================
+
+		// Clone a course of the teacher into a new draft course
+		api.POST("/:course_id/clone", courseHandler.CloneCourse)
+
+		// Create a draft course from a template
+		api.POST("/templates/:course_id", courseHandler.InstantiateTemplate)
+
 		// =============================================
 		// Enrollment Management
 		// =============================================
@@ -122,6 +179,25 @@ func SetupRoutes(ddLogger *logger.DatadogLogger, ddMetrics *metrics.DatadogMetri
 		// Get courses the current user is enrolled in
 		api.GET("/enrolled", courseHandler.GetEnrolledCourses)
 
+		// Check which prerequisites of a course the current user meets
+		api.GET("/:course_id/eligibility", courseHandler.GetCourseEligibility)
+
+		// =============================================
+		// Waitlist Management
+		// =============================================
+
+		// Join the waitlist of a full course
+		api.POST("/:course_id/waitlist", courseHandler.JoinWaitlist)
+
+		// Leave the waitlist of a course
+		api.DELETE("/:course_id/waitlist", courseHandler.LeaveWaitlist)
+
+		// Get current user's position in the waitlist
+		api.GET("/:course_id/waitlist/position", courseHandler.GetWaitlistPosition)
+
+		// Get the waitlist of a course
+		api.GET("/:course_id/waitlist", courseHandler.GetWaitlist)
+
 		// =============================================
 		// Course Approval System
 		// =============================================
@@ -135,6 +211,50 @@ func SetupRoutes(ddLogger *logger.DatadogLogger, ddMetrics *metrics.DatadogMetri
 		// Get approved users for a specific course
 		api.GET("/:course_id/approved-users", courseHandler.GetApprovedUsersForCourse)
 
+		// Set the completion rules that approve the students of a course
+		api.PUT("/:course_id/approval/rules", courseHandler.SaveApprovalRules)
+
+		// Get the approval rules of a course
+		api.GET("/:course_id/approval/rules", courseHandler.GetApprovalRules)
+
+		// Report who the approval rules would approve, without approving anyone
+		api.GET("/:course_id/approval/preview", courseHandler.PreviewApprovals)
+
+		// Approve every student that meets the approval rules
+		api.POST("/:course_id/approval/run", courseHandler.RunApprovals)
+
+		// =============================================
+		// Certificates
+		// =============================================
+
+		// Get the certificates of the current user
+		api.GET("/certificates", courseHandler.GetMyCertificates)
+
+		// Download a certificate of the current user as a PDF
+		api.GET("/certificates/:certificate_id/pdf", courseHandler.GetCertificatePDF)
+
+		// Get the certificate of the current user for an approved course
+		api.GET("/:course_id/certificate", courseHandler.GetCourseCertificate)
+
+		// Get the certificates issued for a course
+		api.GET("/:course_id/certificates", courseHandler.GetCourseCertificates)
+
+		// =============================================
+		// Calendar Feeds
+		// =============================================
+
+		// Subscribe to the calendar of every course of the current user
+		api.POST("/calendar/feeds", courseHandler.CreateCalendarFeed)
+
+		// Get the calendar feeds of the current user
+		api.GET("/calendar/feeds", courseHandler.GetCalendarFeeds)
+
+		// Revoke a calendar feed of the current user
+		api.DELETE("/calendar/feeds/:feed_id", courseHandler.DeleteCalendarFeed)
+
+		// Subscribe to the calendar of a course
+		api.POST("/:course_id/calendar/feeds", courseHandler.CreateCourseCalendarFeed)
+
 		// =============================================
 		// Course Feedback & Ratings
 		// =============================================
@@ -180,6 +300,50 @@ func SetupRoutes(ddLogger *logger.DatadogLogger, ddMetrics *metrics.DatadogMetri
 		// Delete an assignment
 		api.DELETE("/:course_id/assignment/:assignment_id", courseHandler.DeleteAssignment)
 
+		// Download a file of an assignment
+		api.GET("/:course_id/assignment/:assignment_id/file/:file_id", courseHandler.GetAssignmentFile)
+
+		// =============================================
+		// Rubric Management
+		// =============================================
+
+		// Create a reusable rubric for a course
+		api.POST("/:course_id/rubric", courseHandler.CreateRubric)
+
+		// Get all rubrics of a course
+		api.GET("/:course_id/rubrics", courseHandler.GetRubrics)
+
+		// Get a rubric of a course
+		api.GET("/:course_id/rubric/:rubric_id", courseHandler.GetRubric)
+
+		// Update a rubric
+		api.PATCH("/:course_id/rubric/:rubric_id", courseHandler.UpdateRubric)
+
+		// Delete a rubric that no assignment uses
+		api.DELETE("/:course_id/rubric/:rubric_id", courseHandler.DeleteRubric)
+
+		// =============================================
+		// Gradebook
+		// =============================================
+
+		// Create a category of the gradebook of a course
+		api.POST("/:course_id/gradebook/categories", courseHandler.CreateGradeCategory)
+
+		// Get the categories of the gradebook of a course
+		api.GET("/:course_id/gradebook/categories", courseHandler.GetGradeCategories)
+
+		// Replace a category of the gradebook
+		api.PATCH("/:course_id/gradebook/categories/:category_id", courseHandler.UpdateGradeCategory)
+
+		// Delete a category of the gradebook, leaving its assignments without category
+		api.DELETE("/:course_id/gradebook/categories/:category_id", courseHandler.DeleteGradeCategory)
+
+		// Get the final grade of every student of a course
+		api.GET("/:course_id/gradebook", courseHandler.GetGradebook)
+
+		// Get the final grade of the current user in a course
+		api.GET("/:course_id/gradebook/me", courseHandler.GetGradebookOfCurrentUser)
+
 		// =============================================
 		// Submission Management
 		// =============================================
@@ -196,12 +360,39 @@ func SetupRoutes(ddLogger *logger.DatadogLogger, ddMetrics *metrics.DatadogMetri
 		// Grade and provide feedback on a submission
 		api.PATCH("/:course_id/assignment/:assignment_id/submission/:submission_id", courseHandler.GradeSubmission)
 
+		// Grade a submission criterion by criterion with the rubric of its assignment
+		api.POST("/:course_id/assignment/:assignment_id/submission/:submission_id/rubric-grade", courseHandler.GradeSubmissionWithRubric)
+
+		// Return a graded submission to its student
+		api.POST("/:course_id/assignment/:assignment_id/submission/:submission_id/return", courseHandler.ReturnSubmission)
+
 		// Get AI generated grade and feedback for a submission
 		api.GET("/:course_id/assignment/:assignment_id/submission/:submission_id/ai-grade", courseHandler.GetAIGeneratedGradeAndFeedback)
 
+		// Grade a submission with its AI grade draft
+		api.POST("/:course_id/assignment/:assignment_id/submission/:submission_id/ai-grade/accept", courseHandler.AcceptAIGradeDraft)
+
 		// Delete current user's submission
 		api.DELETE("/:course_id/assignment/:assignment_id/submission", courseHandler.DeleteSubmissionOfCurrentUser)
 
+		// Download a file of any attempt of a submission
+		api.GET("/:course_id/assignment/:assignment_id/submission/:submission_id/file/:file_id", courseHandler.GetSubmissionFile)
+
+		// Get every attempt of the current user's submission
+		api.GET("/:course_id/assignment/:assignment_id/submission/attempts", courseHandler.GetAttemptsOfCurrentUser)
+
+		// Get every attempt of a submission
+		api.GET("/:course_id/assignment/:assignment_id/submission/:submission_id/attempts", courseHandler.GetSubmissionAttempts)
+
+		// Compare two attempts of a submission
+		api.GET("/:course_id/assignment/:assignment_id/submission/:submission_id/attempts/diff", courseHandler.DiffSubmissionAttempts)
+
+		// Get the similarity report of the submissions of an assignment
+		api.GET("/:course_id/assignment/:assignment_id/similarity", courseHandler.GetSubmissionSimilarity)
+
+		// Request a new similarity check of the submissions of an assignment
+		api.POST("/:course_id/assignment/:assignment_id/similarity", courseHandler.CheckSubmissionSimilarity)
+
 		// =============================================
 		// Resources Management
 		// =============================================
@@ -227,6 +418,9 @@ func SetupRoutes(ddLogger *logger.DatadogLogger, ddMetrics *metrics.DatadogMetri
 		// Delete a module and all its resources
 		api.DELETE("/:course_id/resource/module/:module_id", courseHandler.DeleteModule)
 
+		// Mark a resource as completed by the current user
+		api.POST("/:course_id/resource/module/:module_id/:resource_id/complete", courseHandler.CompleteResource)
+
 		// =============================================
 		// Statistics
 		// =============================================
@@ -239,10 +433,19 @@ func SetupRoutes(ddLogger *logger.DatadogLogger, ddMetrics *metrics.DatadogMetri
 
 		// Get statistics for a user
 		api.GET("/statistics/course/:course_id/user/:user_id", courseHandler.GetUserStatisticsForCourse)
+
+		// List statistics tasks that exhausted their retries (admin only)
+		api.GET("/statistics/tasks/failed", courseHandler.GetFailedStatisticsTasks)
+
+		// Requeue a failed statistics task (admin only)
+		api.POST("/statistics/tasks/:task_id/requeue", courseHandler.RequeueFailedStatisticsTask)
 	}
 
 	// Create service manager to handle lifecycle
-	serviceManager := NewServiceManager(statisticsService, r)
+	courseStateScheduler := scheduler.NewCourseStateSchedulerFromEnv(courseRepo)
+	approvalScheduler := scheduler.NewApprovalSchedulerFromEnv(approvalEngine)
+	reminderScheduler := scheduler.NewReminderSchedulerFromEnv(reminders.NewSender(courseRepo, notificationClient, reminders.OffsetsFromEnv()))
+	serviceManager := NewServiceManager(statisticsService, courseStateScheduler, approvalScheduler, reminderScheduler, r)
 	serviceManager.Start()
 
 	return serviceManager
@@ -38,6 +38,9 @@ func TestMain(m *testing.M) {
 	if err := dbManager.ConnectDB(); err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
+	if err := dbManager.MigrateDB(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
 
 	// Set up router
 	router = SetupRoutes(nil, nil) // Pass nil for logger in tests
@@ -157,7 +160,7 @@ func TestCreateCourse_Success(t *testing.T) {
 	courseID := response["id"].(string)
 
 	// delete course
-	makeRequest("DELETE", "/"+courseID, nil, nil, "", "")
+	makeRequest("DELETE", "/"+courseID, nil, nil, "user01", "test01@gmail.com")
 }
 
 func TestCreateCourse_MissingTitle(t *testing.T) {
@@ -206,8 +209,8 @@ func TestGetAllCourses(t *testing.T) {
 	// delete courses
 	courseID := response["id"].(string)
 	courseID2 := response2["id"].(string)
-	makeRequest("DELETE", "/"+courseID, nil, nil, "", "")
-	makeRequest("DELETE", "/"+courseID2, nil, nil, "", "")
+	makeRequest("DELETE", "/"+courseID, nil, nil, "user01", "test@example.com")
+	makeRequest("DELETE", "/"+courseID2, nil, nil, "user01", "test@example.com")
 }
 
 func TestCreatedCourseExist(t *testing.T) {
@@ -240,7 +243,7 @@ func TestCreatedCourseExist(t *testing.T) {
 		}
 	}
 	// delete course
-	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "", "")
+	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
 	assert.True(t, found, "Created course should be in the list of all courses")
 }
 
@@ -268,7 +271,7 @@ func TestGetCourseById_Success(t *testing.T) {
 	assert.Equal(t, "Test Description", response2["description"])
 
 	// delete course
-	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "", "")
+	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
 }
 
 func TestGetCourseById_NotFound(t *testing.T) {
@@ -296,7 +299,7 @@ func TestUpdateCourse_Success(t *testing.T) {
 		"description": "Updated Description",
 	}
 
-	w := makeRequest("PATCH", "/"+createdCourseID, updatePayload, nil, "user01", "test@example01.com")
+	w := makeRequest("PATCH", "/"+createdCourseID, updatePayload, nil, "user01", "test@example.com")
 	assert.Equal(t, http.StatusNoContent, w.Code)
 
 	var updatedResponse map[string]any
@@ -308,7 +311,7 @@ func TestUpdateCourse_Success(t *testing.T) {
 	assert.Equal(t, createdCourseID, updatedResponse["id"])
 
 	// // delete course
-	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "", "")
+	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
 }
 
 func TestUpdateCourse_NotFound(t *testing.T) {
@@ -355,7 +358,7 @@ func TestEnrollUserInCourse_Success(t *testing.T) {
 
 	// ver que el alumno esta en el curso
 	var membersResponse map[string]any
-	w = makeRequest("GET", "/"+createdCourseID+"/members", nil, &membersResponse, "user01", "test@example01.com")
+	w = makeRequest("GET", "/"+createdCourseID+"/members", nil, &membersResponse, "user01", "test@example.com")
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.NotNil(t, membersResponse["data"])
@@ -366,7 +369,7 @@ func TestEnrollUserInCourse_Success(t *testing.T) {
 	assert.Equal(t, userID, members[0].(map[string]any)["user_id"])
 
 	// eliminar curso
-	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "", "")
+	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
 }
 
 func TestGetCourseMembers_Success(t *testing.T) {
@@ -396,7 +399,7 @@ func TestGetCourseMembers_Success(t *testing.T) {
 
 	// ver que los alumnos están en el curso
 	var membersResponse map[string]any
-	w = makeRequest("GET", "/"+createdCourseID+"/members", nil, &membersResponse, "user01", "test@example01.com")
+	w = makeRequest("GET", "/"+createdCourseID+"/members", nil, &membersResponse, "user01", "test@example.com")
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	assert.NotNil(t, membersResponse["data"])
@@ -415,7 +418,7 @@ func TestGetCourseMembers_Success(t *testing.T) {
 	assert.Contains(t, userIds, userId2)
 
 	// eliminar curso
-	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "", "")
+	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
 }
 
 func TestUnenrollUserFromCourse_Success(t *testing.T) {
@@ -443,7 +446,7 @@ func TestUnenrollUserFromCourse_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	var membersResponse map[string]any
-	w = makeRequest("GET", "/"+createdCourseID+"/members", nil, &membersResponse, "user01", "test@example01.com")
+	w = makeRequest("GET", "/"+createdCourseID+"/members", nil, &membersResponse, "user01", "test@example.com")
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.NotNil(t, membersResponse["data"])
 
@@ -451,7 +454,7 @@ func TestUnenrollUserFromCourse_Success(t *testing.T) {
 	assert.Equal(t, 0, len(members))
 
 	// eliminar curso
-	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "", "")
+	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
 }
 
 func TestDeleteCourse_Success(t *testing.T) {
@@ -470,7 +473,7 @@ func TestDeleteCourse_Success(t *testing.T) {
 
 	assert.NotEmpty(t, createdCourseID)
 
-	w = makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example01.com")
+	w = makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
 	assert.Equal(t, http.StatusNoContent, w.Code)
 
 	// Verify course was deleted
@@ -481,5 +484,73 @@ func TestDeleteCourse_Success(t *testing.T) {
 
 func TestDeleteCourse_NotFound(t *testing.T) {
 	w := makeRequest("DELETE", "/999999", nil, nil, "user01", "test@example01.com")
-	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteCourse_ForbiddenForNonOwner(t *testing.T) {
+	payload := map[string]any{
+		"title":       "Test Course",
+		"description": "Test Description",
+		"created_by":  "test@example.com",
+		"capacity":    15,
+	}
+
+	var response map[string]any
+	w := makeRequest("POST", "/course", payload, &response, "user01", "test@example01.com")
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	createdCourseID := response["id"].(string)
+
+	w = makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user02", "test@example02.com")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// eliminar curso
+	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
+}
+
+func TestEnrollUserInCourse_FullCourseUsesWaitlist(t *testing.T) {
+	payload := map[string]any{
+		"title":       "Test Course",
+		"description": "Test Description",
+		"created_by":  "test@example.com",
+		"capacity":    1,
+	}
+
+	var response map[string]any
+	w := makeRequest("POST", "/course", payload, &response, "user01", "test@example01.com")
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	createdCourseID := response["id"].(string)
+
+	// el primer alumno ocupa el único lugar
+	w = makeRequest("POST", "/"+createdCourseID+"/enroll", nil, nil, "1", "test@example01.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// el segundo alumno no puede inscribirse
+	w = makeRequest("POST", "/"+createdCourseID+"/enroll", nil, nil, "2", "test@example02.com")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// pero puede anotarse en la lista de espera
+	var waitlistResponse map[string]any
+	w = makeRequest("POST", "/"+createdCourseID+"/waitlist", nil, &waitlistResponse, "2", "test@example02.com")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, float64(1), waitlistResponse["position"])
+
+	// al desinscribirse el primero, el segundo es promovido automáticamente
+	w = makeRequest("DELETE", "/"+createdCourseID+"/enroll", nil, nil, "1", "test@example01.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var membersResponse map[string]any
+	w = makeRequest("GET", "/"+createdCourseID+"/members", nil, &membersResponse, "user01", "test@example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	members := membersResponse["data"].([]any)
+	assert.Equal(t, 1, len(members))
+	assert.Equal(t, "2", members[0].(map[string]any)["user_id"])
+
+	w = makeRequest("GET", "/"+createdCourseID+"/waitlist/position", nil, nil, "2", "test@example02.com")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// eliminar curso
+	makeRequest("DELETE", "/"+createdCourseID, nil, nil, "user01", "test@example.com")
 }
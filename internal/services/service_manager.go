@@ -4,19 +4,32 @@ import (
 	"context"
 	"net/http"
 	"templateGo/internal/queue"
+	"templateGo/internal/scheduler"
 )
 
 // ServiceManager manages the lifecycle of application services
 type ServiceManager struct {
-	statisticsService *queue.StatisticsService
-	httpHandler       http.Handler
+	statisticsService    *queue.StatisticsService
+	courseStateScheduler *scheduler.CourseStateScheduler
+	approvalScheduler    *scheduler.ApprovalScheduler
+	reminderScheduler    *scheduler.ReminderScheduler
+	httpHandler          http.Handler
 }
 
 // NewServiceManager creates a new service manager
-func NewServiceManager(statisticsService *queue.StatisticsService, httpHandler http.Handler) *ServiceManager {
+func NewServiceManager(
+	statisticsService *queue.StatisticsService,
+	courseStateScheduler *scheduler.CourseStateScheduler,
+	approvalScheduler *scheduler.ApprovalScheduler,
+	reminderScheduler *scheduler.ReminderScheduler,
+	httpHandler http.Handler,
+) *ServiceManager {
 	return &ServiceManager{
-		statisticsService: statisticsService,
-		httpHandler:       httpHandler,
+		statisticsService:    statisticsService,
+		courseStateScheduler: courseStateScheduler,
+		approvalScheduler:    approvalScheduler,
+		reminderScheduler:    reminderScheduler,
+		httpHandler:          httpHandler,
 	}
 }
 
@@ -25,10 +38,28 @@ func (sm *ServiceManager) Start() {
 	if sm.statisticsService != nil {
 		sm.statisticsService.Start()
 	}
+	if sm.courseStateScheduler != nil {
+		sm.courseStateScheduler.Start()
+	}
+	if sm.approvalScheduler != nil {
+		sm.approvalScheduler.Start()
+	}
+	if sm.reminderScheduler != nil {
+		sm.reminderScheduler.Start()
+	}
 }
 
 // Stop stops all managed services gracefully
 func (sm *ServiceManager) Stop() {
+	if sm.reminderScheduler != nil {
+		sm.reminderScheduler.Stop()
+	}
+	if sm.approvalScheduler != nil {
+		sm.approvalScheduler.Stop()
+	}
+	if sm.courseStateScheduler != nil {
+		sm.courseStateScheduler.Stop()
+	}
 	if sm.statisticsService != nil {
 		sm.statisticsService.Stop()
 	}
@@ -41,7 +72,7 @@ func (sm *ServiceManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // Shutdown gracefully shuts down all services
 func (sm *ServiceManager) Shutdown(ctx context.Context) error {
-	// Stop statistics service
+	// Stop statistics service and schedulers
 	sm.Stop()
 
 	// If the HTTP handler supports graceful shutdown, call it here
@@ -0,0 +1,248 @@
+// Package similarity finds copied fragments between sets of documents, such as the submissions of an
+// assignment. Documents are normalized into tokens, hashed in k-grams and fingerprinted with winnowing
+// (Schleimer, Wilkerson and Aiken, 2003), so any copied run of at least GramSize+Window-1 tokens is detected
+// whatever its position, comments, whitespace or variable names.
+package similarity
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"templateGo/internal/model"
+	"unicode/utf8"
+)
+
+// Sizes of the k-grams hashed in source code and prose, in tokens, and of the winnowing window
+const (
+	codeGramSize  = 10
+	proseGramSize = 6
+	windowSize    = 4
+)
+
+// Bounds of the fragments returned by Compare
+const (
+	maxMatches   = 20
+	maxMatchText = 1000
+)
+
+// Document is a text compared for similarity. Its name decides whether it is read as source code,
+// from the extension, or as prose.
+type Document struct {
+	Name string
+	Text string
+}
+
+// location is the k-gram starting at token pos of a document
+type location struct {
+	doc int
+	pos int
+}
+
+// Fingerprints are the winnowed fingerprints of a set of documents
+type Fingerprints struct {
+	docs   []Document
+	tokens [][]token
+	grams  []int // k-gram size of each document
+	hashes map[uint64][]location
+}
+
+// Fingerprint tokenizes and fingerprints a set of documents
+func Fingerprint(docs []Document) *Fingerprints {
+	f := &Fingerprints{
+		docs:   docs,
+		tokens: make([][]token, len(docs)),
+		grams:  make([]int, len(docs)),
+		hashes: make(map[uint64][]location),
+	}
+	for i, doc := range docs {
+		f.tokens[i] = tokenize(doc)
+		f.grams[i] = proseGramSize
+		if languageOf(doc.Name) != nil {
+			f.grams[i] = codeGramSize
+		}
+		for _, pos := range winnow(hashGrams(f.tokens[i], f.grams[i]), windowSize) {
+			hash := hashGram(f.tokens[i][pos : pos+f.grams[i]])
+			f.hashes[hash] = append(f.hashes[hash], location{doc: i, pos: pos})
+		}
+	}
+	return f
+}
+
+// Exclude drops the fingerprints shared with base, such as the files handed out with an assignment,
+// so code every student was given is not reported as copied
+func (f *Fingerprints) Exclude(base *Fingerprints) {
+	for hash := range base.hashes {
+		delete(f.hashes, hash)
+	}
+}
+
+// Size returns the number of distinct fingerprints
+func (f *Fingerprints) Size() int {
+	return len(f.hashes)
+}
+
+// Result is the similarity between two sets of documents
+type Result struct {
+	// Score is the share of the fingerprints of the smaller set found in the other one, from 0 to 1
+	Score   float64
+	Matches []model.SimilarityMatch
+}
+
+// Compare scores how much of a and b is shared and returns the longest shared fragments
+func Compare(a, b *Fingerprints) Result {
+	smaller := min(a.Size(), b.Size())
+	if smaller == 0 {
+		return Result{}
+	}
+
+	var spans []span
+	for hash, locations := range a.hashes {
+		others, ok := b.hashes[hash]
+		if !ok {
+			continue
+		}
+		for i, loc := range locations {
+			other := others[min(i, len(others)-1)]
+			spans = append(spans, span{
+				doc: loc.doc, start: loc.pos, end: loc.pos + a.grams[loc.doc],
+				otherDoc: other.doc, otherStart: other.pos, otherEnd: other.pos + b.grams[other.doc],
+			})
+		}
+	}
+
+	shared := 0
+	for hash := range a.hashes {
+		if _, ok := b.hashes[hash]; ok {
+			shared++
+		}
+	}
+
+	return Result{
+		Score:   float64(shared) / float64(smaller),
+		Matches: matches(a, b, mergeSpans(spans)),
+	}
+}
+
+// span is a run of tokens of a document of a and the run of tokens of a document of b it matches
+type span struct {
+	doc, start, end                int
+	otherDoc, otherStart, otherEnd int
+}
+
+// mergeSpans joins the spans of overlapping k-grams into the fragments they belong to
+func mergeSpans(spans []span) []span {
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].doc != spans[j].doc {
+			return spans[i].doc < spans[j].doc
+		}
+		if spans[i].otherDoc != spans[j].otherDoc {
+			return spans[i].otherDoc < spans[j].otherDoc
+		}
+		return spans[i].start < spans[j].start
+	})
+
+	var merged []span
+	for _, s := range spans {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if s.doc == last.doc && s.otherDoc == last.otherDoc && s.start <= last.end &&
+				s.otherStart <= last.otherEnd && s.otherEnd >= last.otherStart {
+				last.end = max(last.end, s.end)
+				last.otherStart = min(last.otherStart, s.otherStart)
+				last.otherEnd = max(last.otherEnd, s.otherEnd)
+				continue
+			}
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// matches turns the longest fragments into the text they cover in each document
+func matches(a, b *Fingerprints, spans []span) []model.SimilarityMatch {
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].end-spans[i].start > spans[j].end-spans[j].start
+	})
+	if len(spans) > maxMatches {
+		spans = spans[:maxMatches]
+	}
+
+	result := make([]model.SimilarityMatch, 0, len(spans))
+	for _, s := range spans {
+		start, end := a.tokens[s.doc][s.start].start, a.tokens[s.doc][s.end-1].end
+		otherStart, otherEnd := b.tokens[s.otherDoc][s.otherStart].start, b.tokens[s.otherDoc][s.otherEnd-1].end
+		result = append(result, model.SimilarityMatch{
+			Source:      a.docs[s.doc].Name,
+			Start:       start,
+			End:         end,
+			Text:        excerpt(a.docs[s.doc].Text[start:end]),
+			OtherSource: b.docs[s.otherDoc].Name,
+			OtherStart:  otherStart,
+			OtherEnd:    otherEnd,
+			OtherText:   excerpt(b.docs[s.otherDoc].Text[otherStart:otherEnd]),
+		})
+	}
+	return result
+}
+
+// excerpt cuts long fragments without splitting a character
+func excerpt(text string) string {
+	if len(text) <= maxMatchText {
+		return text
+	}
+	cut := maxMatchText
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "…"
+}
+
+// hashGrams returns the hash of every k-gram of the tokens
+func hashGrams(tokens []token, k int) []uint64 {
+	if len(tokens) < k {
+		return nil
+	}
+	hashes := make([]uint64, len(tokens)-k+1)
+	for i := range hashes {
+		hashes[i] = hashGram(tokens[i : i+k])
+	}
+	return hashes
+}
+
+func hashGram(tokens []token) uint64 {
+	h := fnv.New64a()
+	for _, t := range tokens {
+		h.Write([]byte(t.text))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// winnow selects the positions of the minimum hash of every window of w consecutive hashes,
+// the rightmost one on ties, as in robust winnowing. Texts shorter than a window keep their minimum.
+func winnow(hashes []uint64, w int) []int {
+	if len(hashes) == 0 {
+		return nil
+	}
+	w = min(w, len(hashes))
+
+	var selected []int
+	for start := 0; start+w <= len(hashes); start++ {
+		minimum := start
+		for i := start + 1; i < start+w; i++ {
+			if hashes[i] <= hashes[minimum] {
+				minimum = i
+			}
+		}
+		if len(selected) == 0 || selected[len(selected)-1] != minimum {
+			selected = append(selected, minimum)
+		}
+	}
+	return selected
+}
+
+// IsText reports whether a file content can be compared as text
+func IsText(contentType string, content []byte) bool {
+	return (strings.HasPrefix(contentType, "text/") || strings.HasPrefix(contentType, "application/json") ||
+		strings.HasPrefix(contentType, "application/xml")) && utf8.Valid(content)
+}
@@ -0,0 +1,92 @@
+package similarity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const original = `package main
+
+import "fmt"
+
+// fib returns the nth Fibonacci number
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func main() {
+	for i := 0; i < 10; i++ {
+		fmt.Println(fib(i))
+	}
+}
+`
+
+// The same program with other names, comments and layout
+const renamed = `package main
+import "fmt"
+/* my own solution */
+func fibonacci(x int) int {
+	if x < 2 { return x }
+	return fibonacci(x-1) + fibonacci(x-2) // recursive
+}
+func main() {
+	for j := 0; j < 20; j++ { fmt.Println(fibonacci(j)) }
+}
+`
+
+const unrelated = `package main
+
+import "sort"
+
+func sortDescending(values []int) {
+	sort.Slice(values, func(a, b int) bool {
+		return values[a] > values[b]
+	})
+}
+`
+
+func TestCompare_SourceCodeIgnoresNamesAndComments(t *testing.T) {
+	a := Fingerprint([]Document{{Name: "main.go", Text: original}})
+	b := Fingerprint([]Document{{Name: "solution.go", Text: renamed}})
+	c := Fingerprint([]Document{{Name: "sort.go", Text: unrelated}})
+
+	copied := Compare(a, b)
+	assert.Greater(t, copied.Score, 0.8)
+	require.NotEmpty(t, copied.Matches)
+	match := copied.Matches[0]
+	assert.Equal(t, "main.go", match.Source)
+	assert.Equal(t, "solution.go", match.OtherSource)
+	assert.Equal(t, original[match.Start:match.End], match.Text)
+	assert.Contains(t, match.OtherText, "fibonacci")
+
+	assert.Less(t, Compare(a, c).Score, 0.2)
+}
+
+func TestCompare_ProseAndExcludedBase(t *testing.T) {
+	statement := "Explain in your own words why the sky looks blue during the day and red at sunset."
+	answer := "Sunlight is scattered by the molecules of the air, and blue light is scattered much more than red light because of its shorter wavelength."
+
+	a := Fingerprint([]Document{{Name: "content", Text: statement + "\n" + answer}})
+	b := Fingerprint([]Document{{Name: "content", Text: statement + "\nThe atmosphere scatters short wavelengths the most, so we see the blue part of the spectrum."}})
+	base := Fingerprint([]Document{{Name: "statement.txt", Text: statement}})
+
+	assert.Greater(t, Compare(a, b).Score, 0.2)
+	a.Exclude(base)
+	b.Exclude(base)
+	assert.Zero(t, Compare(a, b).Score)
+
+	copy := Fingerprint([]Document{{Name: "content", Text: strings.ToUpper(answer)}})
+	assert.Equal(t, 1.0, Compare(a, copy).Score)
+}
+
+func TestWinnow_SelectsRightmostMinimumPerWindow(t *testing.T) {
+	assert.Equal(t, []int{1, 4, 5}, winnow([]uint64{7, 2, 9, 9, 2, 1, 8}, 4))
+	assert.Equal(t, []int{0}, winnow([]uint64{3, 5}, 4))
+	assert.Nil(t, winnow(nil, 4))
+}
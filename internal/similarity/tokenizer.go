@@ -0,0 +1,206 @@
+package similarity
+
+import (
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a normalized word or symbol of a document and where it is in the original text
+type token struct {
+	text       string
+	start, end int // byte offsets in the text of the document
+}
+
+// Normalized forms of the tokens that do not depend on their spelling in source code
+const (
+	identifierToken = "$id"
+	numberToken     = "$num"
+	stringToken     = "$str"
+)
+
+// language tells how comments are written in a family of programming languages
+type language struct {
+	lineComments  []string
+	blockComments [][2]string
+	stringQuotes  []string // longest first, so triple quotes win over single ones
+}
+
+var (
+	cFamily = &language{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		stringQuotes:  []string{`"`, "'", "`"},
+	}
+	hashFamily = &language{
+		lineComments: []string{"#"},
+		stringQuotes: []string{`"""`, "'''", `"`, "'"},
+	}
+	dashFamily = &language{
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"/*", "*/"}, {"{-", "-}"}},
+		stringQuotes:  []string{`"`, "'"},
+	}
+)
+
+// languages maps the extensions of source files to the family of their language
+var languages = map[string]*language{
+	".c": cFamily, ".h": cFamily, ".cc": cFamily, ".cpp": cFamily, ".hpp": cFamily, ".cs": cFamily,
+	".java": cFamily, ".kt": cFamily, ".scala": cFamily, ".go": cFamily, ".rs": cFamily, ".swift": cFamily,
+	".js": cFamily, ".jsx": cFamily, ".ts": cFamily, ".tsx": cFamily, ".php": cFamily, ".dart": cFamily,
+	".py": hashFamily, ".rb": hashFamily, ".sh": hashFamily, ".r": hashFamily, ".pl": hashFamily, ".jl": hashFamily,
+	".sql": dashFamily, ".hs": dashFamily, ".lua": dashFamily,
+}
+
+// keywords are kept as they are when normalizing source code, while any other identifier becomes the same
+// token, so renaming variables does not hide a copy. It covers the common keywords of the supported languages.
+var keywords = toSet(
+	"if", "else", "elif", "elsif", "for", "foreach", "while", "do", "switch", "case", "default", "break",
+	"continue", "return", "goto", "func", "function", "def", "fn", "lambda", "class", "struct", "interface",
+	"enum", "union", "trait", "impl", "type", "typedef", "var", "let", "const", "val", "mut", "static",
+	"import", "package", "from", "as", "include", "using", "namespace", "module", "try", "catch", "except",
+	"finally", "raise", "throw", "throws", "new", "delete", "public", "private", "protected", "void", "int",
+	"long", "short", "float", "double", "char", "bool", "boolean", "string", "byte", "true", "false", "nil",
+	"null", "none", "self", "this", "super", "in", "is", "not", "and", "or", "yield", "go", "defer", "range",
+	"map", "chan", "select", "pass", "with", "async", "await", "match", "loop", "end", "then", "begin",
+	"local", "where", "insert", "update", "into", "values", "join", "on", "group", "by", "order", "having",
+	"create", "table", "print", "println", "printf", "echo",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// languageOf returns the language of a source file from its name, or nil for prose
+func languageOf(name string) *language {
+	return languages[strings.ToLower(path.Ext(name))]
+}
+
+// tokenize splits a document into normalized tokens. Source code loses its comments, and its identifiers,
+// numbers and strings are replaced by placeholders. Prose is split in lowercase words without punctuation.
+func tokenize(doc Document) []token {
+	if lang := languageOf(doc.Name); lang != nil {
+		return tokenizeCode(doc.Text, lang)
+	}
+	return tokenizeProse(doc.Text)
+}
+
+func tokenizeProse(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			tokens = append(tokens, token{text: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{text: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+func tokenizeCode(text string, lang *language) []token {
+	var tokens []token
+	i := 0
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+
+		if end, ok := skipComment(text, i, lang); ok {
+			i = end
+			continue
+		}
+		if end, ok := skipString(text, i, lang); ok {
+			tokens = append(tokens, token{text: stringToken, start: i, end: end})
+			i = end
+			continue
+		}
+
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+			end := scan(text, i, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' })
+			word := strings.ToLower(text[i:end])
+			if !keywords[word] {
+				word = identifierToken
+			}
+			tokens = append(tokens, token{text: word, start: i, end: end})
+			i = end
+		case unicode.IsDigit(r):
+			end := scan(text, i, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' })
+			tokens = append(tokens, token{text: numberToken, start: i, end: end})
+			i = end
+		default:
+			tokens = append(tokens, token{text: string(r), start: i, end: i + size})
+			i += size
+		}
+	}
+	return tokens
+}
+
+// scan returns the offset of the first rune from start that does not match
+func scan(text string, start int, match func(rune) bool) int {
+	for i, r := range text[start:] {
+		if !match(r) {
+			return start + i
+		}
+	}
+	return len(text)
+}
+
+// skipComment returns the offset after the comment starting at i, if any
+func skipComment(text string, i int, lang *language) (int, bool) {
+	rest := text[i:]
+	for _, prefix := range lang.lineComments {
+		if strings.HasPrefix(rest, prefix) {
+			if end := strings.IndexByte(rest, '\n'); end >= 0 {
+				return i + end, true
+			}
+			return len(text), true
+		}
+	}
+	for _, block := range lang.blockComments {
+		if strings.HasPrefix(rest, block[0]) {
+			if end := strings.Index(rest[len(block[0]):], block[1]); end >= 0 {
+				return i + len(block[0]) + end + len(block[1]), true
+			}
+			return len(text), true
+		}
+	}
+	return 0, false
+}
+
+// skipString returns the offset after the string literal starting at i, if any
+func skipString(text string, i int, lang *language) (int, bool) {
+	rest := text[i:]
+	for _, quote := range lang.stringQuotes {
+		if !strings.HasPrefix(rest, quote) {
+			continue
+		}
+		for j := len(quote); j < len(rest); j++ {
+			switch {
+			case rest[j] == '\\':
+				j++
+			case strings.HasPrefix(rest[j:], quote):
+				return i + j + len(quote), true
+			case rest[j] == '\n' && len(quote) == 1 && quote != "`":
+				// Unterminated literal, probably an apostrophe
+				return i + j, true
+			}
+		}
+		return len(text), true
+	}
+	return 0, false
+}
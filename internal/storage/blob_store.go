@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"templateGo/internal/utils"
+)
+
+// Blob store implementations, selected with the BLOB_STORE environment variable
+const (
+	BlobStoreLocal = "local"
+	BlobStoreS3    = "s3"
+)
+
+const defaultBlobDir = "data/blobs"
+
+// BlobStore keeps file contents addressed by the hex encoded SHA-256 digest of their bytes,
+// so a content uploaded many times is stored once
+type BlobStore interface {
+	// Put stores size bytes read from content under their digest
+	Put(ctx context.Context, digest string, content io.Reader, size int64) error
+
+	// Exists reports whether a content is stored under the digest
+	Exists(ctx context.Context, digest string) (bool, error)
+
+	// Open returns the content stored under the digest, or ErrBlobNotFound
+	Open(ctx context.Context, digest string) (Blob, error)
+
+	// Delete removes the content stored under the digest, if any
+	Delete(ctx context.Context, digest string) error
+}
+
+// Blob is a stored content that can be read from any offset, as needed to serve Range requests
+type Blob interface {
+	io.ReadSeekCloser
+	Size() int64
+}
+
+// NewBlobStoreFromEnv creates the blob store configured by environment variables.
+// Contents are kept in the local BLOB_DIR directory unless BLOB_STORE is s3.
+func NewBlobStoreFromEnv() (BlobStore, error) {
+	switch name := strings.ToLower(os.Getenv("BLOB_STORE")); name {
+	case "", BlobStoreLocal:
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = defaultBlobDir
+		}
+		return NewLocalBlobStore(dir)
+	case BlobStoreS3:
+		pathStyle, _ := strconv.ParseBool(os.Getenv("S3_PATH_STYLE"))
+		return NewS3BlobStore(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       pathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown blob store: %s", name)
+	}
+}
+
+// Digest returns the hex encoded SHA-256 of a content, the key it is stored under
+func Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// SaveBytes stores a content unless an identical one is already stored, and returns its digest
+func SaveBytes(ctx context.Context, store BlobStore, content []byte) (string, error) {
+	digest := Digest(content)
+	exists, err := store.Exists(ctx, digest)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if err := store.Put(ctx, digest, bytes.NewReader(content), int64(len(content))); err != nil {
+			return "", err
+		}
+	}
+	return digest, nil
+}
+
+// Save stores up to limit bytes read from r unless an identical content is already stored, and returns
+// its digest and size. The content is spooled to a temporary file while hashing it, since the digest it is
+// stored under is only known once it is fully read. Longer contents return ErrFileTooLarge.
+func Save(ctx context.Context, store BlobStore, r io.Reader, limit int64) (string, int64, error) {
+	spool, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, hash), io.LimitReader(r, limit+1))
+	if err != nil {
+		return "", 0, fmt.Errorf("error reading content: %w", err)
+	}
+	if size > limit {
+		return "", 0, fmt.Errorf("%w: more than %d bytes", utils.ErrFileTooLarge, limit)
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	exists, err := store.Exists(ctx, digest)
+	if err != nil {
+		return "", 0, err
+	}
+	if !exists {
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			return "", 0, err
+		}
+		if err := store.Put(ctx, digest, spool, size); err != nil {
+			return "", 0, err
+		}
+	}
+	return digest, size, nil
+}
+
+// validDigest reports whether the digest is a hex encoded SHA-256, so it is safe to use in paths and keys
+func validDigest(digest string) bool {
+	if len(digest) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(digest)
+	return err == nil && strings.ToLower(digest) == digest
+}
+
+// objectKey spreads the contents over directories named after the first bytes of their digest
+func objectKey(digest string) string {
+	return digest[:2] + "/" + digest
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"templateGo/internal/utils"
+)
+
+// LocalBlobStore keeps contents as files of a local directory
+type LocalBlobStore struct {
+	dir string
+}
+
+// NewLocalBlobStore creates a new instance of LocalBlobStore, creating the directory if needed
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating blob directory: %w", err)
+	}
+	return &LocalBlobStore{dir: dir}, nil
+}
+
+func (s *LocalBlobStore) Put(ctx context.Context, digest string, content io.Reader, size int64) error {
+	if !validDigest(digest) {
+		return fmt.Errorf("invalid blob digest %q", digest)
+	}
+	path := s.path(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial blob
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(file, hash), content)
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("blob %s has %d bytes, expected %d", digest, written, size)
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != digest {
+		return fmt.Errorf("blob content does not match digest %s", digest)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (s *LocalBlobStore) Exists(ctx context.Context, digest string) (bool, error) {
+	if !validDigest(digest) {
+		return false, nil
+	}
+	_, err := os.Stat(s.path(digest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *LocalBlobStore) Open(ctx context.Context, digest string) (Blob, error) {
+	if !validDigest(digest) {
+		return nil, utils.ErrBlobNotFound
+	}
+	file, err := os.Open(s.path(digest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, utils.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &localBlob{File: file, size: info.Size()}, nil
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, digest string) error {
+	if !validDigest(digest) {
+		return nil
+	}
+	err := os.Remove(s.path(digest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *LocalBlobStore) path(digest string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(objectKey(digest)))
+}
+
+type localBlob struct {
+	*os.File
+	size int64
+}
+
+func (b *localBlob) Size() int64 {
+	return b.size
+}
@@ -0,0 +1,325 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"templateGo/internal/utils"
+	"time"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	s3Timeout       = 5 * time.Minute
+	// emptyPayloadHash is the SHA-256 of an empty body, signed in requests without one
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3Config configures an S3 compatible blob store, such as AWS S3 or MinIO
+type S3Config struct {
+	Endpoint        string // https://s3.<region>.amazonaws.com when empty
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool // address the bucket in the path instead of the host name, as MinIO expects
+}
+
+// S3BlobStore keeps contents as objects of an S3 compatible bucket, signing requests with AWS Signature Version 4
+type S3BlobStore struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3BlobStore creates a new instance of S3BlobStore
+func NewS3BlobStore(config S3Config) (*S3BlobStore, error) {
+	if config.Bucket == "" || config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("S3 blob store requires a bucket and credentials")
+	}
+	if config.Region == "" {
+		config.Region = defaultS3Region
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+
+	return &S3BlobStore{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: s3Timeout},
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3BlobStore) Put(ctx context.Context, digest string, content io.Reader, size int64) error {
+	if !validDigest(digest) {
+		return fmt.Errorf("invalid blob digest %q", digest)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(digest), content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	// The key is the SHA-256 of the content, which is also the payload hash S3 verifies
+	resp, err := s.do(req, digest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error("storing", digest, resp)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Exists(ctx context.Context, digest string) (bool, error) {
+	_, err := s.head(ctx, digest)
+	if errors.Is(err, utils.ErrBlobNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *S3BlobStore) Open(ctx context.Context, digest string) (Blob, error) {
+	size, err := s.head(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Blob{ctx: ctx, store: s, digest: digest, size: size}, nil
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, digest string) error {
+	if !validDigest(digest) {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(digest), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return s3Error("deleting", digest, resp)
+	}
+}
+
+// head returns the size of an object, or ErrBlobNotFound
+func (s *S3BlobStore) head(ctx context.Context, digest string) (int64, error) {
+	if !validDigest(digest) {
+		return 0, utils.ErrBlobNotFound
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.objectURL(digest), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.ContentLength, nil
+	case http.StatusNotFound:
+		return 0, utils.ErrBlobNotFound
+	default:
+		return 0, s3Error("reading", digest, resp)
+	}
+}
+
+// get reads an object from the offset to its end
+func (s *S3BlobStore) get(ctx context.Context, digest string, offset int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(digest), nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent, resp.StatusCode == http.StatusOK && offset == 0:
+		return resp.Body, nil
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, utils.ErrBlobNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error("reading", digest, resp)
+	}
+}
+
+func (s *S3BlobStore) objectURL(digest string) string {
+	objectURL := *s.endpoint
+	if s.config.PathStyle {
+		objectURL.Path = strings.TrimSuffix(objectURL.Path, "/") + "/" + s.config.Bucket + "/" + objectKey(digest)
+	} else {
+		objectURL.Host = s.config.Bucket + "." + objectURL.Host
+		objectURL.Path = strings.TrimSuffix(objectURL.Path, "/") + "/" + objectKey(digest)
+	}
+	return objectURL.String()
+}
+
+func (s *S3BlobStore) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.sign(req, payloadHash, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling S3: %w", err)
+	}
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 authorization of the request, covering the host,
+// the Range header and every x-amz header
+func (s *S3BlobStore) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3Error(action string, digest string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("error %s blob %s in S3: %s: %s", action, digest, resp.Status, strings.TrimSpace(string(body)))
+}
+
+// s3Blob reads an object lazily, issuing a ranged GET from the current offset after each seek
+type s3Blob struct {
+	ctx    context.Context
+	store  *S3BlobStore
+	digest string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (b *s3Blob) Read(p []byte) (int, error) {
+	if b.offset >= b.size {
+		return 0, io.EOF
+	}
+	if b.body == nil {
+		body, err := b.store.get(b.ctx, b.digest, b.offset)
+		if err != nil {
+			return 0, err
+		}
+		b.body = body
+	}
+
+	n, err := b.body.Read(p)
+	b.offset += int64(n)
+	return n, err
+}
+
+func (b *s3Blob) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += b.offset
+	case io.SeekEnd:
+		offset += b.size
+	}
+	if offset < 0 {
+		return 0, errors.New("seek before the start of the blob")
+	}
+
+	if offset != b.offset && b.body != nil {
+		b.body.Close()
+		b.body = nil
+	}
+	b.offset = offset
+	return offset, nil
+}
+
+func (b *s3Blob) Close() error {
+	if b.body == nil {
+		return nil
+	}
+	err := b.body.Close()
+	b.body = nil
+	return err
+}
+
+func (b *s3Blob) Size() int64 {
+	return b.size
+}
@@ -9,9 +9,39 @@ import (
 
 // Errores personalizados
 var (
-	ErrUserAlreadyEnrolled = errors.New("user already enrolled in this course")
-	ErrUserNotEnrolled     = errors.New("user not enrolled in this course")
-	ErrCourseNotFound      = errors.New("course not found")
+	ErrUserAlreadyEnrolled   = errors.New("user already enrolled in this course")
+	ErrUserNotEnrolled       = errors.New("user not enrolled in this course")
+	ErrCourseNotFound        = errors.New("course not found")
+	ErrCourseFull            = errors.New("course has reached its capacity")
+	ErrCourseHasSeats        = errors.New("course still has available seats")
+	ErrAlreadyWaitlisted     = errors.New("user already in the waitlist of this course")
+	ErrNotWaitlisted         = errors.New("user not in the waitlist of this course")
+	ErrTaskNotFound          = errors.New("task not found")
+	ErrDeadLetterDisabled    = errors.New("task queue does not keep failed tasks")
+	ErrRubricNotFound        = errors.New("rubric not found")
+	ErrRubricInUse           = errors.New("rubric is used by an assignment")
+	ErrGradeDraftNotFound    = errors.New("grade draft not found")
+	ErrGradeDraftAccepted    = errors.New("grade draft was already accepted")
+	ErrSubmissionTooLarge    = errors.New("submission exceeds the limits of AI grading")
+	ErrMaxAttemptsReached    = errors.New("no attempts left for the assignment")
+	ErrAttemptNotFound       = errors.New("submission attempt not found")
+	ErrBlobNotFound          = errors.New("blob not found")
+	ErrFileNotFound          = errors.New("file not found")
+	ErrFileTooLarge          = errors.New("file exceeds the size limit")
+	ErrInvalidCursor         = errors.New("invalid page cursor")
+	ErrInvalidSort           = errors.New("invalid sort key")
+	ErrEmptySearchQuery      = errors.New("the search has no words")
+	ErrPrerequisiteCycle     = errors.New("prerequisites would form a cycle")
+	ErrPrerequisiteInvalid   = errors.New("prerequisite course not found")
+	ErrPrerequisitesNotMet   = errors.New("prerequisites not met")
+	ErrEnrollmentClosed      = errors.New("course is not open for enrollment")
+	ErrInvalidTransition     = errors.New("course cannot move to that state")
+	ErrScheduleNotFound      = errors.New("scheduled state change not found")
+	ErrCategoryNotFound      = errors.New("grade category not found")
+	ErrApprovalRulesNotFound = errors.New("course has no approval rules")
+	ErrCertificateNotFound   = errors.New("certificate not found")
+	ErrCalendarFeedNotFound  = errors.New("calendar feed not found")
+	ErrAlreadyApproved       = errors.New("user already approved")
 )
 
 // ErrorResponse matches the OpenAPI error schema
@@ -21,10 +51,31 @@ type ErrorResponse struct {
 	Status   int    `json:"status"`
 	Detail   string `json:"detail"`
 	Instance string `json:"instance,omitempty"`
+
+	// InvalidParams lists each rejected part of a request, such as every invalid file of an upload
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// InvalidParam is an RFC 7807 extension member telling why a part of the request was rejected
+type InvalidParam struct {
+	Name   string `json:"name" example:"files[0]"`
+	File   string `json:"file,omitempty" example:"report.exe"`
+	Reason string `json:"reason" example:"application/x-msdownload files are not allowed"`
 }
 
 // NewErrorResponse creates a standard error response
 func NewErrorResponse(c *gin.Context, status int, title string, detail string) {
+	c.JSON(status, newErrorResponse(c, status, title, detail))
+}
+
+// NewInvalidParamsResponse creates a standard error response listing the rejected parts of the request
+func NewInvalidParamsResponse(c *gin.Context, status int, title string, detail string, params []InvalidParam) {
+	response := newErrorResponse(c, status, title, detail)
+	response.InvalidParams = params
+	c.JSON(status, response)
+}
+
+func newErrorResponse(c *gin.Context, status int, title string, detail string) ErrorResponse {
 	// Create path for the error instance
 	instance := c.Request.URL.Path
 
@@ -40,16 +91,16 @@ func NewErrorResponse(c *gin.Context, status int, title string, detail string) {
 		errorType = "https://api.classconnect.edu/errors/not-found"
 	case http.StatusConflict: // 409
 		errorType = "https://api.classconnect.edu/errors/server-error"
+	case http.StatusRequestEntityTooLarge: // 413
+		errorType = "https://api.classconnect.edu/errors/payload-too-large"
 	}
 
 	// RFC 7807 Format
-	response := ErrorResponse{
+	return ErrorResponse{
 		Type:     errorType,
 		Title:    title,
 		Status:   status,
 		Detail:   detail,
 		Instance: instance,
 	}
-
-	c.JSON(status, response)
 }
@@ -119,3 +119,31 @@ func TestErrorResponse_AllFieldsPopulated(t *testing.T) {
 	assert.NotEmpty(t, response.Instance)
 	assert.NotZero(t, response.Status)
 }
+
+func TestNewInvalidParamsResponse(t *testing.T) {
+	c, w := setupTestContext()
+
+	NewInvalidParamsResponse(c, http.StatusBadRequest, "Validation Error", "Some files were rejected", []InvalidParam{
+		{Name: "files[1]", File: "setup.exe", Reason: "application/x-msdownload files are not allowed"},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://api.classconnect.edu/errors/bad-request", response.Type)
+	assert.Equal(t, "/test/path", response.Instance)
+	assert.Len(t, response.InvalidParams, 1)
+	assert.Equal(t, "files[1]", response.InvalidParams[0].Name)
+	assert.Equal(t, "setup.exe", response.InvalidParams[0].File)
+}
+
+func TestNewErrorResponse_OmitsInvalidParams(t *testing.T) {
+	c, w := setupTestContext()
+
+	NewErrorResponse(c, http.StatusBadRequest, "Validation Error", "Invalid input data")
+
+	assert.NotContains(t, w.Body.String(), "invalid_params")
+}